@@ -2,17 +2,36 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/hashicorp/raft"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
 	"github.com/trevatk/tbd/lib/logging"
 	"github.com/trevatk/tbd/lib/protocol"
+	"github.com/trevatk/tbd/lib/protocol/interceptors"
 	"github.com/trevatk/tbd/lib/setup"
+	"github.com/trevatk/tbd/lib/wallet"
+
+	pbaudit "github.com/trevatk/tbd/lib/protocol/audit/v1"
+	pbkv "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
 
 	"github.com/trevatk/tbd/idp/internal/audit"
-	"github.com/trevatk/tbd/idp/internal/audit/lsm"
+	"github.com/trevatk/tbd/idp/internal/identities"
+	"github.com/trevatk/tbd/idp/internal/store"
+)
+
+const (
+	raftMaxPool = 3
+	raftTimeout = time.Second * 10
 )
 
 func main() {
@@ -34,15 +53,66 @@ func realMain(ctx context.Context) error {
 	cfg := setup.UnmarshalConfig()
 	logger := logging.New(cfg.Logger.Level)
 
-	lsm, err := lsm.New(cfg.KeyValue.Dir)
+	kvOpts, err := store.Options(cfg.KeyValue)
+	if err != nil {
+		return err
+	}
+
+	// stores written by the original audit engine
+	// are converted in place on first start
+	err = keyvalue.MigrateLegacy(cfg.KeyValue.Dir, kvOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to migrate legacy store: %w", err)
+	}
+
+	lsm, err := keyvalue.New(cfg.KeyValue.Dir, kvOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to initialize lsm: %w", err)
 	}
+	defer func() { _ = lsm.Close() }()
+
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+
+	signer, closeSigner, err := newSigner(ctx, logger, suite, cfg.Audit)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = closeSigner() }()
 
-	svc, err := audit.NewService(nil, lsm)
+	svcOpts := []audit.ServiceOption{
+		audit.WithSigner(signer),
+		audit.WithNetwork(wallet.Network(cfg.Audit.Network)),
+	}
+	if cfg.Audit.RaftAddr != "" {
+		cluster, err := newCluster(cfg.Audit)
+		if err != nil {
+			return err
+		}
+		svcOpts = append(svcOpts, audit.WithCluster(cluster))
+	}
+
+	svc, err := audit.NewService(suite, lsm, svcOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to initialize audit service: %w", err)
 	}
+	defer func() { _ = svc.Close() }()
+
+	go svc.Produce(ctx, logger, cfg.Audit.BlockInterval, cfg.Audit.MaxBlockTxs)
+
+	if cfg.KeyValue.ServerAddr != "" {
+		conn, err := protocol.NewConn(cfg.KeyValue.ServerAddr)
+		if err != nil {
+			return fmt.Errorf("failed to connect to keyvalue service: %w", err)
+		}
+		defer func() { _ = conn.Close() }()
+
+		go func() {
+			err := svc.Follow(ctx, logger, pbkv.NewKeyValueServiceClient(conn))
+			if err != nil {
+				logger.ErrorContext(ctx, "stopped following identity changes", "error", err)
+			}
+		}()
+	}
 
 	desc, service := audit.NewTransport(logger, svc)
 
@@ -53,10 +123,19 @@ func realMain(ctx context.Context) error {
 		},
 	}
 
+	// only the configured recorders, authenticated with a token
+	// signed by the shared auth key, may record decisions
+	auth := interceptors.NewAuth(identities.NewAuth(cfg.Auth.SigningKey))
+	recorders := strings.Split(cfg.Audit.Recorders, ",")
+
 	opts := []protocol.ServerOption{
 		protocol.WithHost(cfg.Gateway.Host),
 		protocol.WithPort(cfg.Gateway.Port),
 		protocol.WithTransports(trs),
+		protocol.WithUnaryInterceptors(
+			auth.ValidToken(),
+			interceptors.RequireCaller([]string{pbaudit.AuditService_Decision_FullMethodName}, recorders...),
+		),
 		protocol.WithLogger(logger),
 	}
 
@@ -64,3 +143,61 @@ func realMain(ctx context.Context) error {
 
 	return s.StartAndStop(ctx)
 }
+
+// loadWallet node wallet keystore, a new wallet
+// is created and exported when missing
+func loadWallet(suite wallet.Suite, cfg setup.Audit) (wallet.Wallet, error) {
+	password, err := readSecret(cfg.WalletPasswordFile)
+	if err != nil {
+		return wallet.Wallet{}, fmt.Errorf("failed to read wallet password: %w", err)
+	}
+
+	w, err := wallet.Import(suite, cfg.WalletPath, password)
+	if err == nil {
+		return w, nil
+	} else if !errors.Is(err, wallet.ErrNotExists) {
+		return wallet.Wallet{}, fmt.Errorf("failed to import wallet: %w", err)
+	}
+
+	w = wallet.NewV1(suite)
+	err = w.Export(cfg.WalletPath, password)
+	if err != nil {
+		return wallet.Wallet{}, fmt.Errorf("failed to export wallet: %w", err)
+	}
+
+	return w, nil
+}
+
+// newCluster raft cluster of the audit nodes listed in peers
+//
+// every node is bootstrapped with the same peers, bootstrapping
+// is skipped once a node has raft state
+func newCluster(cfg setup.Audit) (audit.Cluster, error) {
+	transport, err := raft.NewTCPTransport(cfg.RaftAddr, nil, raftMaxPool, raftTimeout, os.Stderr)
+	if err != nil {
+		return audit.Cluster{}, fmt.Errorf("failed to create raft transport: %w", err)
+	}
+
+	servers := make([]raft.Server, 0)
+	for _, peer := range strings.Split(cfg.Peers, ",") {
+		if peer == "" {
+			continue
+		}
+
+		id, addr, ok := strings.Cut(peer, "=")
+		if !ok {
+			return audit.Cluster{}, fmt.Errorf("invalid peer %q, expected id=addr", peer)
+		}
+		servers = append(servers, raft.Server{
+			ID:      raft.ServerID(id),
+			Address: raft.ServerAddress(addr),
+		})
+	}
+
+	return audit.Cluster{
+		NodeID:    cfg.NodeID,
+		Dir:       cfg.RaftDir,
+		Transport: transport,
+		Servers:   servers,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"go.dedis.ch/kyber/v4"
+
+	"github.com/trevatk/tbd/lib/protocol"
+	"github.com/trevatk/tbd/lib/setup"
+	"github.com/trevatk/tbd/lib/wallet"
+	"github.com/trevatk/tbd/lib/wallet/remote"
+	"github.com/trevatk/tbd/lib/wallet/threshold"
+
+	pbsigner "github.com/trevatk/tbd/lib/protocol/signer/v1"
+)
+
+const (
+	signerKeystore  = "keystore"
+	signerRemote    = "remote"
+	signerPKCS11    = "pkcs11"
+	signerThreshold = "threshold"
+)
+
+// newSigner signer of the configured backend, the returned
+// func releases the connection, token session or threshold service
+func newSigner(ctx context.Context, logger *slog.Logger, suite threshold.Suite, cfg setup.Audit) (wallet.Signer, func() error, error) {
+	switch cfg.Signer {
+	case signerKeystore:
+		if cfg.WalletPasswordFile == "" {
+			logger.WarnContext(ctx, "audit wallet keystore is encrypted with an empty password")
+		}
+
+		w, err := loadWallet(suite, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return wallet.NewSigner(suite, w), func() error { return nil }, nil
+
+	case signerRemote:
+		if cfg.SignerAddr == "" {
+			return nil, nil, errors.New("remote signer address not set")
+		}
+
+		// the service is only trusted to hold the configured key
+		pinned, err := parsePublicKey(suite, cfg.SignerPublicKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid remote signer public key: %w", err)
+		}
+
+		conn, err := protocol.NewTLSConn(cfg.SignerAddr, protocol.TLSConfig{
+			CAFile:   cfg.SignerCAFile,
+			CertFile: cfg.SignerCertFile,
+			KeyFile:  cfg.SignerKeyFile,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to signer service: %w", err)
+		}
+
+		s, err := remote.NewSigner(ctx, suite, pbsigner.NewSignerServiceClient(conn), remote.WithPublicKey(pinned))
+		if err != nil {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+		return s, conn.Close, nil
+
+	case signerPKCS11:
+		return newHSMSigner(suite, cfg)
+
+	case signerThreshold:
+		return newThresholdSigner(ctx, logger, suite, cfg)
+	}
+
+	return nil, nil, fmt.Errorf("unknown signer %q, expected %s, %s, %s or %s", cfg.Signer, signerKeystore, signerRemote, signerPKCS11, signerThreshold)
+}
+
+// parsePublicKey hex encoded public key
+func parsePublicKey(suite wallet.Suite, s string) (kyber.Point, error) {
+	if s == "" {
+		return nil, errors.New("public key not set")
+	}
+
+	pb, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := suite.Point()
+	err = p.UnmarshalBinary(pb)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// readSecret content of file without the trailing newline,
+// an empty path is an empty secret
+func readSecret(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(b, "\r\n"), nil
+}
@@ -0,0 +1,15 @@
+//go:build !pkcs11
+
+package main
+
+import (
+	"errors"
+
+	"github.com/trevatk/tbd/lib/setup"
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+// newHSMSigner PKCS#11 support requires cgo and the pkcs11 build tag
+func newHSMSigner(wallet.Suite, setup.Audit) (wallet.Signer, func() error, error) {
+	return nil, nil, errors.New("pkcs11 signer not supported, build with -tags pkcs11")
+}
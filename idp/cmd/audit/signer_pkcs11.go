@@ -0,0 +1,31 @@
+//go:build pkcs11
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/trevatk/tbd/lib/setup"
+	"github.com/trevatk/tbd/lib/wallet"
+	"github.com/trevatk/tbd/lib/wallet/hsm"
+)
+
+// newHSMSigner signer of the key pair held by the PKCS#11 token
+func newHSMSigner(suite wallet.Suite, cfg setup.Audit) (wallet.Signer, func() error, error) {
+	pin, err := readSecret(cfg.PKCS11PINFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read token pin: %w", err)
+	}
+
+	s, err := hsm.New(suite, hsm.Config{
+		Module:     cfg.PKCS11Module,
+		TokenLabel: cfg.PKCS11Token,
+		PIN:        string(pin),
+		KeyLabel:   cfg.PKCS11Key,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open token signer: %w", err)
+	}
+
+	return s, s.Close, nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.dedis.ch/kyber/v4"
+	"google.golang.org/grpc"
+
+	"github.com/trevatk/tbd/lib/protocol"
+	"github.com/trevatk/tbd/lib/setup"
+	"github.com/trevatk/tbd/lib/wallet"
+	"github.com/trevatk/tbd/lib/wallet/threshold"
+
+	pbthreshold "github.com/trevatk/tbd/lib/protocol/threshold/v1"
+)
+
+const keySharePerm = 0o600
+
+// newThresholdSigner signer of the group key shared by the audit
+// nodes, the key is generated with every participant on first start
+//
+// the threshold service listens on its own address, key generation
+// completes before the audit service and its gateway start
+func newThresholdSigner(ctx context.Context, logger *slog.Logger, suite threshold.Suite, cfg setup.Audit) (wallet.Signer, func() error, error) {
+	host, port, err := net.SplitHostPort(cfg.ThresholdAddr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid threshold address %q: %w", cfg.ThresholdAddr, err)
+	}
+
+	w, err := loadWallet(suite, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	participants, err := parseParticipants(suite, cfg.ThresholdParticipants)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	peers, conns, err := dialPeers(cfg.ThresholdPeers)
+	closeConns := func() error {
+		errs := make([]error, 0, len(conns))
+		for _, conn := range conns {
+			errs = append(errs, conn.Close())
+		}
+		return errors.Join(errs...)
+	}
+	if err != nil {
+		_ = closeConns()
+		return nil, nil, err
+	}
+
+	opts := []threshold.NodeOption{
+		threshold.WithPeers(peers),
+		threshold.WithApprover(threshold.DigestApprover),
+	}
+	key, err := readKeyShare(suite, cfg.ThresholdKeySharePath)
+	if err != nil {
+		_ = closeConns()
+		return nil, nil, err
+	} else if key != nil {
+		opts = append(opts, threshold.WithKeyShare(key))
+	}
+
+	node, err := threshold.NewNode(suite, w, participants, cfg.Threshold, opts...)
+	if err != nil {
+		_ = closeConns()
+		return nil, nil, fmt.Errorf("failed to create threshold node: %w", err)
+	}
+
+	desc, service := threshold.NewTransport(logger, node)
+	srv := protocol.NewServer(
+		protocol.WithHost(host),
+		protocol.WithPort(port),
+		protocol.WithTransports([]protocol.Transport{{ServiceDesc: desc, Service: service}}),
+		protocol.WithLogger(logger),
+	)
+
+	srvCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		err := srv.StartAndStop(srvCtx)
+		if err != nil {
+			logger.ErrorContext(ctx, "threshold server stopped", "error", err)
+		}
+	}()
+	closeSigner := func() error {
+		cancel()
+		return closeConns()
+	}
+
+	if key == nil {
+		logger.InfoContext(ctx, "generating group key", "participants", len(participants), "threshold", cfg.Threshold)
+
+		_, err = node.Keygen(ctx)
+		if err != nil {
+			_ = closeSigner()
+			return nil, nil, err
+		}
+
+		err = writeKeyShare(cfg.ThresholdKeySharePath, node.KeyShare())
+		if err != nil {
+			_ = closeSigner()
+			return nil, nil, err
+		}
+	}
+
+	s, err := threshold.NewSigner(node)
+	if err != nil {
+		_ = closeSigner()
+		return nil, nil, err
+	}
+
+	return s, closeSigner, nil
+}
+
+// parseParticipants comma separated hex public keys
+func parseParticipants(suite wallet.Suite, s string) ([]kyber.Point, error) {
+	participants := make([]kyber.Point, 0)
+	for _, pk := range strings.Split(s, ",") {
+		if pk == "" {
+			continue
+		}
+
+		p, err := parsePublicKey(suite, pk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid participant %q: %w", pk, err)
+		}
+		participants = append(participants, p)
+	}
+
+	return participants, nil
+}
+
+// dialPeers clients of the comma separated index=addr peers, the
+// returned connections are closed by the caller even on error
+func dialPeers(s string) (map[int]pbthreshold.ThresholdServiceClient, []*grpc.ClientConn, error) {
+	peers := make(map[int]pbthreshold.ThresholdServiceClient)
+	conns := make([]*grpc.ClientConn, 0)
+	for _, peer := range strings.Split(s, ",") {
+		if peer == "" {
+			continue
+		}
+
+		index, addr, ok := strings.Cut(peer, "=")
+		if !ok {
+			return nil, conns, fmt.Errorf("invalid threshold peer %q, expected index=addr", peer)
+		}
+		i, err := strconv.Atoi(index)
+		if err != nil {
+			return nil, conns, fmt.Errorf("invalid threshold peer %q: %w", peer, err)
+		}
+
+		conn, err := protocol.NewConn(addr)
+		if err != nil {
+			return nil, conns, fmt.Errorf("failed to connect to threshold peer %d: %w", i, err)
+		}
+		conns = append(conns, conn)
+		peers[i] = pbthreshold.NewThresholdServiceClient(conn)
+	}
+
+	return peers, conns, nil
+}
+
+// readKeyShare group key share, nil when not generated yet
+func readKeyShare(suite threshold.Suite, path string) (*threshold.KeyShare, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read key share: %w", err)
+	}
+
+	return threshold.UnmarshalKeyShare(suite, b)
+}
+
+// writeKeyShare persist the secret key share readable by the owner only
+func writeKeyShare(path string, key *threshold.KeyShare) error {
+	b, err := key.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Clean(path), b, keySharePerm)
+	if err != nil {
+		return fmt.Errorf("failed to write key share: %w", err)
+	}
+
+	return nil
+}
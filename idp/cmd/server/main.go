@@ -2,15 +2,24 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"log/slog"
 	"os/signal"
 	"syscall"
 
+	"google.golang.org/grpc"
+
 	"github.com/trevatk/tbd/idp/internal/identities"
+	"github.com/trevatk/tbd/idp/internal/store"
+	"github.com/trevatk/tbd/lib/keyvalue"
 	"github.com/trevatk/tbd/lib/logging"
 	"github.com/trevatk/tbd/lib/protocol"
+	"github.com/trevatk/tbd/lib/protocol/interceptors"
 	"github.com/trevatk/tbd/lib/setup"
+
+	pbaudit "github.com/trevatk/tbd/lib/protocol/audit/v1"
+	pbkv "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
 )
 
 func main() {
@@ -34,9 +43,66 @@ func realMain(ctx context.Context) error {
 
 	logger := logging.New(cfg.Logger.Level)
 	logger.InfoContext(ctx, "service configuration", slog.Any("config", cfg))
-	_ = identities.NewAuth(cfg.Auth.SigningKey)
-	graph := identities.NewGraph()
-	svc := identities.NewService(graph)
+	verifier := identities.NewAuth(cfg.Auth.SigningKey)
+	auth := interceptors.NewAuth(verifier)
+
+	kvOpts, err := store.Options(cfg.KeyValue)
+	if err != nil {
+		return err
+	}
+
+	lsm, err := keyvalue.New(cfg.KeyValue.Dir, kvOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to initialize lsm: %w", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	graph := identities.NewGraph(lsm)
+
+	var client pbkv.KeyValueServiceClient
+	if cfg.KeyValue.ServerAddr != "" {
+		conn, err := protocol.NewConn(cfg.KeyValue.ServerAddr)
+		if err != nil {
+			return fmt.Errorf("failed to connect to keyvalue service: %w", err)
+		}
+		defer func() { _ = conn.Close() }()
+		client = pbkv.NewKeyValueServiceClient(conn)
+	}
+
+	var acOpts []identities.AccessControlOption
+	if cfg.Identities.PolicyFile != "" {
+		policy, err := identities.LoadPolicy(cfg.Identities.PolicyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load policy: %w", err)
+		}
+		acOpts = append(acOpts, identities.WithPolicy(policy))
+	}
+	ac := identities.NewAccessControl(graph, acOpts...)
+	if cfg.Identities.PolicyFile != "" {
+		go ac.WatchPolicy(ctx, logger, cfg.Identities.PolicyFile, cfg.Identities.PolicyReloadInterval)
+	}
+
+	// every request is authenticated, checked against the
+	// policy and its decision recorded before it is handled
+	var accessOpts []interceptors.AccessControlOption
+	if cfg.Identities.AuditAddr != "" {
+		// the audit service only records decisions of known callers
+		token, err := verifier.SignWithClaims(map[string]interface{}{"user_id": cfg.Identities.ServiceName})
+		if err != nil {
+			return fmt.Errorf("failed to sign audit token: %w", err)
+		}
+
+		conn, err := protocol.NewConn(cfg.Identities.AuditAddr, grpc.WithUnaryInterceptor(interceptors.BearerToken(token)))
+		if err != nil {
+			return fmt.Errorf("failed to connect to audit service: %w", err)
+		}
+		defer func() { _ = conn.Close() }()
+		accessOpts = append(accessOpts, interceptors.WithDecisionRecorder(pbaudit.NewAuditServiceClient(conn)))
+	}
+	access := interceptors.NewAccessControl(ac, identities.Resolve, accessOpts...)
+
+	svc := identities.NewService(graph, ac, identities.NewEmitter(client))
+	go svc.RelayEvents(ctx, logger, cfg.Identities.EventRelayInterval)
 	desc, service := identities.NewTransport(logger, svc)
 
 	trs := []protocol.Transport{
@@ -50,6 +116,10 @@ func realMain(ctx context.Context) error {
 		protocol.WithHost(cfg.Gateway.Host),
 		protocol.WithPort(cfg.Gateway.Port),
 		protocol.WithTransports(trs),
+		protocol.WithUnaryInterceptors(
+			auth.ValidToken(),
+			access.EnsureResourceAccess(),
+		),
 		protocol.WithLogger(logger),
 	}
 
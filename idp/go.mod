@@ -3,6 +3,7 @@ module github.com/trevatk/tbd/idp
 go 1.24.4
 
 replace (
+	github.com/trevatk/tbd/lib/keyvalue => ../lib/keyvalue
 	github.com/trevatk/tbd/lib/logging => ../lib/logging
 	github.com/trevatk/tbd/lib/protocol => ../lib/protocol
 	github.com/trevatk/tbd/lib/setup => ../lib/setup
@@ -12,11 +13,14 @@ replace (
 require (
 	buf.build/go/protovalidate v0.13.1
 	github.com/golang-jwt/jwt/v5 v5.2.2
-	github.com/stretchr/testify v1.10.0
+	github.com/google/uuid v1.6.0
+	github.com/hashicorp/raft v1.7.3
+	github.com/trevatk/tbd/lib/keyvalue v0.0.0-00010101000000-000000000000
 	github.com/trevatk/tbd/lib/logging v0.0.0-00010101000000-000000000000
 	github.com/trevatk/tbd/lib/protocol v0.0.0-00010101000000-000000000000
 	github.com/trevatk/tbd/lib/setup v0.0.0-00010101000000-000000000000
 	github.com/trevatk/tbd/lib/wallet v0.0.0-00010101000000-000000000000
+	go.dedis.ch/kyber/v4 v4.0.0-pre2
 	google.golang.org/grpc v1.73.0
 	google.golang.org/protobuf v1.36.6
 )
@@ -25,16 +29,26 @@ require (
 	buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go v1.36.6-20250625184727-c923a0c2a132.1 // indirect
 	cel.dev/expr v0.23.1 // indirect
 	github.com/antlr4-go/antlr/v4 v4.13.0 // indirect
-	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/armon/go-metrics v0.4.1 // indirect
+	github.com/fatih/color v1.13.0 // indirect
 	github.com/google/cel-go v0.25.0 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/hashicorp/go-hclog v1.6.2 // indirect
+	github.com/hashicorp/go-immutable-radix v1.0.0 // indirect
+	github.com/hashicorp/go-metrics v0.5.4 // indirect
+	github.com/hashicorp/go-msgpack/v2 v2.1.2 // indirect
+	github.com/hashicorp/golang-lru v0.5.0 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/mattn/go-colorable v0.1.12 // indirect
+	github.com/mattn/go-isatty v0.0.14 // indirect
+	github.com/miekg/pkcs11 v1.1.2 // indirect
 	github.com/stoewer/go-strcase v1.3.0 // indirect
-	go.dedis.ch/kyber/v4 v4.0.0-pre2 // indirect
+	github.com/tyler-smith/go-bip39 v1.1.0 // indirect
+	go.dedis.ch/fixbuf v1.0.3 // indirect
+	golang.org/x/crypto v0.36.0 // indirect
 	golang.org/x/exp v0.0.0-20240325151524-a685a6edb6d8 // indirect
 	golang.org/x/net v0.38.0 // indirect
 	golang.org/x/sys v0.32.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20250324211829-b45e905df463 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250324211829-b45e905df463 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
+	"github.com/trevatk/tbd/lib/protocol/ledger"
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+const (
+	// defaultHeight height of the genesis block
+	defaultHeight = 1
+)
+
+type block struct {
+	Hash       string    `json:"hash"`
+	PrevHash   string    `json:"prev_hash"`
+	Height     int64     `json:"height"`
+	MerkleRoot string    `json:"merkle_root"`
+	Txs        []*tx     `json:"txs"`
+	Timestamp  time.Time `json:"timestamp"`
+	// Signer sender whose registered key signed the hash
+	Signer string `json:"signer"`
+	// SignerKey hex public key of Signer, covered by the hash
+	SignerKey string `json:"signer_key"`
+	Sig       string `json:"sig"`
+}
+
+func (b block) Marshal() []byte {
+	bb, _ := json.Marshal(b)
+	return bb
+}
+
+func genesisBlock(signer string, coinTx *tx) *block {
+	return &block{
+		Signer:    signer,
+		PrevHash:  "",
+		Height:    defaultHeight,
+		Txs:       []*tx{coinTx},
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// newBlock block of txs following prev signed by signer
+func newBlock(signer string, prev *block, txs []*tx) *block {
+	return &block{
+		Signer:    signer,
+		PrevHash:  prev.Hash,
+		Height:    prev.Height + 1,
+		Txs:       txs,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// blockHeader header fields covered by the block hash and signature
+func (b *block) blockHeader() *pb.BlockHeader {
+	return &pb.BlockHeader{
+		Hash:       b.Hash,
+		PrevHash:   b.PrevHash,
+		Height:     b.Height,
+		MerkleRoot: b.MerkleRoot,
+		Timestamp:  timestamppb.New(b.Timestamp),
+		Sig:        b.Sig,
+		Signer:     b.SignerKey,
+		TxCount:    int64(len(b.Txs)),
+	}
+}
+
+// txLeaves merkle leaves of the block transactions,
+// a tx sealed twice in one block is rejected
+func (b *block) txLeaves() ([][]byte, error) {
+	leaves := make([][]byte, 0, len(b.Txs))
+	seen := make(map[string]bool, len(b.Txs))
+	for _, tx := range b.Txs {
+		if seen[tx.Hash] {
+			return nil, fmt.Errorf("duplicate tx %s", tx.Hash)
+		}
+		seen[tx.Hash] = true
+
+		hb, err := hex.DecodeString(tx.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tx hash %q", tx.Hash)
+		}
+		leaves = append(leaves, hb)
+	}
+	return leaves, nil
+}
+
+// txRoot merkle root over the hashes of the block transactions
+func (b *block) txRoot() ([]byte, error) {
+	leaves, err := b.txLeaves()
+	if err != nil {
+		return nil, err
+	}
+
+	return ledger.MerkleRoot(leaves), nil
+}
+
+// computeHash hash of the block header
+func (b *block) computeHash() (string, error) {
+	return ledger.HeaderHash(b.blockHeader())
+}
+
+// seal set merkle root, signer key and hash then sign the hash with signer
+func (b *block) seal(signer wallet.Signer) error {
+	root, err := b.txRoot()
+	if err != nil {
+		return err
+	}
+	b.MerkleRoot = hex.EncodeToString(root)
+
+	kb, err := signer.Public().MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal signer key: %w", err)
+	}
+	b.SignerKey = hex.EncodeToString(kb)
+
+	b.Hash, err = b.computeHash()
+	if err != nil {
+		return err
+	}
+
+	hb, _ := hex.DecodeString(b.Hash)
+	sig, err := signer.Sign(hb)
+	if err != nil {
+		return fmt.Errorf("failed to sign block: %w", err)
+	}
+	b.Sig = hex.EncodeToString(sig)
+
+	return nil
+}
@@ -0,0 +1,291 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+const (
+	bufSize     = 1024 * 1024
+	waitTimeout = time.Second * 30
+)
+
+// bufNetwork in process network of raft nodes
+type bufNetwork struct {
+	mu        sync.Mutex
+	listeners map[raft.ServerAddress]*bufconn.Listener
+}
+
+type bufAddr string
+
+func (a bufAddr) Network() string { return "bufconn" }
+func (a bufAddr) String() string  { return string(a) }
+
+// bufStreamLayer raft.StreamLayer over bufconn
+type bufStreamLayer struct {
+	*bufconn.Listener
+	addr    bufAddr
+	network *bufNetwork
+}
+
+func (l *bufStreamLayer) Addr() net.Addr {
+	return l.addr
+}
+
+func (l *bufStreamLayer) Dial(address raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	l.network.mu.Lock()
+	lis, ok := l.network.listeners[address]
+	l.network.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown address %s", address)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return lis.DialContext(ctx)
+}
+
+func (n *bufNetwork) transport(addr raft.ServerAddress) raft.Transport {
+	lis := bufconn.Listen(bufSize)
+
+	n.mu.Lock()
+	n.listeners[addr] = lis
+	n.mu.Unlock()
+
+	return raft.NewNetworkTransport(&bufStreamLayer{
+		Listener: lis,
+		addr:     bufAddr(addr),
+		network:  n,
+	}, 3, time.Second, io.Discard)
+}
+
+func testRaftConfig() *raft.Config {
+	conf := raft.DefaultConfig()
+	// shorter timeouts elect new leaders while writes are in
+	// flight when the race detector slows the nodes down
+	conf.HeartbeatTimeout = time.Second
+	conf.ElectionTimeout = time.Second
+	conf.LeaderLeaseTimeout = time.Second
+	conf.CommitTimeout = time.Millisecond * 5
+	conf.SnapshotThreshold = 4
+	conf.SnapshotInterval = time.Hour
+	conf.TrailingLogs = 2
+	conf.LogOutput = io.Discard
+	return conf
+}
+
+func newTestNode(t *testing.T, n *bufNetwork, id string, servers []raft.Server) *serviceImpl {
+	t.Helper()
+
+	lsm, err := keyvalue.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { _ = lsm.Close() })
+
+	s, err := NewService(edwards25519.NewBlakeSHA256Ed25519(), lsm, WithCluster(Cluster{
+		NodeID:    id,
+		Dir:       t.TempDir(),
+		Transport: n.transport(raft.ServerAddress(id)),
+		Servers:   servers,
+		Config:    testRaftConfig(),
+	}))
+	if err != nil {
+		t.Fatalf("failed to start node %s: %v", id, err)
+	}
+
+	return s
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+func waitForLeader(t *testing.T, nodes []*serviceImpl) *serviceImpl {
+	t.Helper()
+
+	var leader *serviceImpl
+	waitFor(t, "leader election", func() bool {
+		for _, s := range nodes {
+			if s.repl.leader() {
+				leader = s
+				return true
+			}
+		}
+		return false
+	})
+
+	return leader
+}
+
+func tipHeight(s *serviceImpl) int64 {
+	tip := s.currentTip()
+	if tip == nil {
+		return 0
+	}
+	return tip.Height
+}
+
+func recordDecisions(t *testing.T, s *serviceImpl, from, to int) {
+	t.Helper()
+
+	for i := from; i < to; i++ {
+		_, err := s.recordDecision(decision{
+			Subject:   "user",
+			Resource:  "audit.v1.AuditService",
+			Action:    "ListDecisions",
+			Outcome:   "OUTCOME_ALLOW",
+			RequestID: fmt.Sprintf("request-%d", i),
+		})
+		if err != nil {
+			t.Fatalf("failed to record decision %d: %v", i, err)
+		}
+	}
+}
+
+func TestCluster(t *testing.T) {
+	n := &bufNetwork{listeners: make(map[raft.ServerAddress]*bufconn.Listener)}
+
+	servers := make([]raft.Server, 0)
+	for _, id := range []string{"node1", "node2", "node3"} {
+		servers = append(servers, raft.Server{ID: raft.ServerID(id), Address: raft.ServerAddress(id)})
+	}
+
+	nodes := make([]*serviceImpl, 0)
+	for _, srv := range servers {
+		s := newTestNode(t, n, string(srv.ID), servers)
+		t.Cleanup(func() { _ = s.Close() })
+		nodes = append(nodes, s)
+	}
+
+	// leadership may move between writes, the leader is
+	// resolved again before each of them
+	waitForLeader(t, nodes)
+
+	// nodes added by subtests live until the end of the test
+	root := t
+
+	t.Run("replicate", func(t *testing.T) {
+		_, err := waitForLeader(t, nodes).produceBlock(10)
+		if err != nil {
+			t.Fatalf("failed to produce genesis: %v", err)
+		}
+
+		recordDecisions(t, waitForLeader(t, nodes), 0, 5)
+		b, err := waitForLeader(t, nodes).produceBlock(10)
+		if err != nil || b == nil {
+			t.Fatalf("failed to produce block: %v", err)
+		}
+
+		for _, s := range nodes {
+			waitFor(t, "block replication", func() bool { return tipHeight(s) == b.Height })
+
+			records, _, err := s.listDecisions(decisionFilter{}, "", 0)
+			if err != nil {
+				t.Fatalf("failed to list decisions: %v", err)
+			}
+			if len(records) != 5 || records[0].Height != b.Height {
+				t.Fatalf("unexpected decisions %d at height %d", len(records), records[0].Height)
+			}
+		}
+	})
+
+	t.Run("follower", func(t *testing.T) {
+		leader := waitForLeader(t, nodes)
+		for _, s := range nodes {
+			if s == leader {
+				continue
+			}
+
+			b, err := s.produceBlock(10)
+			if b != nil || err != nil {
+				t.Fatalf("follower produced block %v %v", b, err)
+			}
+
+			_, err = s.recordDecision(decision{Subject: "user", Outcome: "OUTCOME_DENY", RequestID: "follower"})
+			if !errors.Is(err, errNotLeader) {
+				t.Fatalf("unexpected error %v expected %v", err, errNotLeader)
+			}
+		}
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		// pending decisions travel with the snapshot
+		recordDecisions(t, waitForLeader(t, nodes), 5, 8)
+
+		err := waitForLeader(t, nodes).repl.(*raftReplicator).raft.Snapshot().Error()
+		if err != nil {
+			t.Fatalf("failed to snapshot: %v", err)
+		}
+
+		s := newTestNode(root, n, "node4", nil)
+		root.Cleanup(func() { _ = s.Close() })
+
+		err = waitForLeader(t, nodes).repl.(*raftReplicator).raft.AddVoter("node4", "node4", 0, waitTimeout).Error()
+		if err != nil {
+			t.Fatalf("failed to add voter: %v", err)
+		}
+
+		waitFor(t, "snapshot transfer", func() bool {
+			return tipHeight(s) == tipHeight(waitForLeader(t, nodes)) && len(s.pool.all()) == 3
+		})
+
+		b, err := waitForLeader(t, nodes).produceBlock(10)
+		if err != nil || b == nil {
+			t.Fatalf("failed to produce block: %v", err)
+		}
+		waitFor(t, "catch up", func() bool { return tipHeight(s) == b.Height && len(s.pool.all()) == 0 })
+
+		nodes = append(nodes, s)
+	})
+
+	t.Run("election", func(t *testing.T) {
+		leader := waitForLeader(t, nodes)
+		err := leader.Close()
+		if err != nil {
+			t.Fatalf("failed to stop leader: %v", err)
+		}
+
+		remaining := make([]*serviceImpl, 0)
+		for _, s := range nodes {
+			if s != leader {
+				remaining = append(remaining, s)
+			}
+		}
+
+		recordDecisions(t, waitForLeader(t, remaining), 8, 10)
+		b, err := waitForLeader(t, remaining).produceBlock(10)
+		if err != nil || b == nil {
+			t.Fatalf("failed to produce block after election: %v", err)
+		}
+
+		for _, s := range remaining {
+			waitFor(t, "block after election", func() bool { return tipHeight(s) == b.Height })
+
+			height, inc, err := s.verifyChain()
+			if err != nil || inc != nil || height != b.Height {
+				t.Fatalf("chain invalid at %d: %v %v", height, inc, err)
+			}
+		}
+	})
+}
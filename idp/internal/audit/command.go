@@ -0,0 +1,243 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+const (
+	// appliedKey index of the last replicated command applied
+	appliedKey = "applied"
+	// failedPrefix key prefix of replicated commands rejected on apply
+	//
+	// failed_<index> to the rejection reason
+	failedPrefix = "failed_"
+
+	// writeAttempts writes of a committed command
+	// before the node halts
+	writeAttempts = 5
+	writeBackoff  = time.Millisecond * 100
+)
+
+var (
+	errStaleBlock = errors.New("block does not extend the tip")
+)
+
+// command change to the ledger, every node applies
+// the same commands in the same order
+type command struct {
+	// Keys sender public keys to register
+	Keys map[string]string `json:"keys,omitempty"`
+	// Txs pending transactions
+	Txs []*tx `json:"txs,omitempty"`
+	// Cursor change feed position committed with Txs
+	Cursor *uint64 `json:"cursor,omitempty"`
+	// Block sealed on top of the tip
+	Block *block `json:"block,omitempty"`
+}
+
+// apply command at replicated log index, 0 when not replicated
+//
+// commands at or below the applied index are skipped so a
+// replayed log leaves the store unchanged, checks only read
+// applied state so every node reaches the same result
+//
+// a command carrying a tx or block not signed by the
+// registered key of its sender or a write the store cannot
+// index is rejected as a whole
+func (s *serviceImpl) apply(c *command, index uint64) error {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	if index != 0 && index <= s.applied {
+		return nil
+	}
+
+	err := s.verifyCommand(c)
+	if err != nil {
+		return err
+	}
+
+	b := keyvalue.NewBatch()
+	if index != 0 {
+		b.Put(appliedKey, []byte(strconv.FormatUint(index, 10)), nil, -1)
+	}
+
+	for sender, key := range c.Keys {
+		b.Put(keyPrefix+sender, []byte(key), nil, -1)
+	}
+
+	txs, err := s.newTxs(c.Txs)
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		err = putPendingTx(b, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Cursor != nil {
+		b.Put(cursorKey, []byte(strconv.FormatUint(*c.Cursor, 10)), nil, -1)
+	}
+
+	var blockErr error
+	if c.Block != nil {
+		blockErr = s.extendsTip(c.Block)
+		if blockErr == nil {
+			err = putBlock(b, c.Block)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = b.Validate()
+	if err != nil {
+		return s.rejectCommand(index, err)
+	}
+
+	err = s.writeCommand(b, index)
+	if err != nil {
+		return fmt.Errorf("failed to write command: %w", err)
+	}
+
+	if index != 0 {
+		s.applied = index
+	}
+	s.pool.add(txs...)
+	if c.Block != nil && blockErr == nil {
+		s.tip = c.Block
+		s.pool.remove(c.Block.Txs)
+	}
+
+	return blockErr
+}
+
+// rejectCommand record the command at index as failed, a batch
+// the store cannot write is rejected by every node so the applied
+// index moves past it rather than replaying it on restart
+func (s *serviceImpl) rejectCommand(index uint64, reason error) error {
+	if index == 0 {
+		return reason
+	}
+
+	b := keyvalue.NewBatch()
+	b.Put(appliedKey, []byte(strconv.FormatUint(index, 10)), nil, -1)
+	b.Put(fmt.Sprintf("%s%020d", failedPrefix, index), []byte(reason.Error()), nil, -1)
+
+	err := s.writeCommand(b, index)
+	if err != nil {
+		return fmt.Errorf("failed to write command: %w", err)
+	}
+	s.applied = index
+
+	return reason
+}
+
+// newTxs transactions not yet recorded, a decision
+// is recorded once however often it is retried
+func (s *serviceImpl) newTxs(txs []*tx) ([]*tx, error) {
+	fresh := make([]*tx, 0, len(txs))
+	for _, tx := range txs {
+		if tx.To != decisionTo {
+			fresh = append(fresh, tx)
+			continue
+		}
+
+		r, err := newDecisionRecord(tx, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = s.store.Get(r.dedupKey())
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			fresh = append(fresh, tx)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get decision: %w", err)
+		}
+	}
+
+	return fresh, nil
+}
+
+// extendsTip block follows the applied tip
+func (s *serviceImpl) extendsTip(b *block) error {
+	if s.tip == nil {
+		if b.Height != defaultHeight {
+			return errStaleBlock
+		}
+		return nil
+	}
+
+	if b.Height != s.tip.Height+1 || b.PrevHash != s.tip.Hash {
+		return errStaleBlock
+	}
+
+	return nil
+}
+
+// putPendingTx add tx waiting for a block to batch, decisions
+// are indexed for listing and by their key
+func putPendingTx(b *keyvalue.Batch, tx *tx) error {
+	b.Put(txPrefix+tx.Hash, tx.Marshal(), map[string]string{statusIndex: statusPending}, -1)
+
+	if tx.To != decisionTo {
+		return nil
+	}
+
+	r, err := newDecisionRecord(tx, 0)
+	if err != nil {
+		return err
+	}
+	r.put(b)
+	b.Put(r.dedupKey(), []byte(tx.Hash), nil, -1)
+
+	return nil
+}
+
+func (s *serviceImpl) loadApplied() (uint64, error) {
+	vb, err := s.store.Get(appliedKey)
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get applied index: %w", err)
+	}
+
+	index, err := strconv.ParseUint(string(vb), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("strconv.ParseUint: %w", err)
+	}
+
+	return index, nil
+}
+
+// writeCommand write the batch of the command at index
+//
+// raft never delivers a committed command again, one left
+// unwritten diverges this node from the others, so the write is
+// retried and the node halts when it keeps failing. the command is
+// applied again from the log when the node restarts, batches are
+// validated beforehand so only store failures reach here
+func (s *serviceImpl) writeCommand(b *keyvalue.Batch, index uint64) error {
+	err := s.store.Write(b)
+	if err == nil || index == 0 {
+		return err
+	}
+
+	for attempt := 1; attempt < writeAttempts; attempt++ {
+		time.Sleep(writeBackoff * time.Duration(attempt))
+
+		err = s.store.Write(b)
+		if err == nil {
+			return nil
+		}
+	}
+
+	panic(fmt.Sprintf("failed to write committed command %d: %v", index, err))
+}
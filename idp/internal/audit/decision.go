@@ -0,0 +1,311 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+const (
+	// decisionPrefix key prefix of decision digest to decision tx hash
+	decisionPrefix = "decision_"
+	// decisionTo recipient of decision transactions
+	decisionTo = "decisions"
+
+	// decisionLogPrefix key prefix of decision records ordered by time
+	//
+	// decisionlog_<unix nano>_<tx hash>
+	decisionLogPrefix = "decisionlog_"
+	// decisionLogEnd exclusive upper bound of decision record keys
+	decisionLogEnd = "decisionlog`"
+
+	subjectIndex  = "subject"
+	resourceIndex = "resource"
+	outcomeIndex  = "outcome"
+
+	defaultDecisionLimit = 50
+)
+
+var (
+	errInvalidCursor   = errors.New("invalid cursor")
+	errInvalidDecision = errors.New("invalid decision")
+)
+
+// decision authorization decision
+type decision struct {
+	Subject       string `json:"subject"`
+	Resource      string `json:"resource"`
+	Action        string `json:"action"`
+	Outcome       string `json:"outcome"`
+	PolicyVersion string `json:"policy_version"`
+	RequestID     string `json:"request_id"`
+}
+
+// decisionRecord decision as listed, indexed by
+// subject, resource and outcome
+type decisionRecord struct {
+	decision
+	TxHash    string    `json:"tx_hash"`
+	Timestamp time.Time `json:"timestamp"`
+	// Height block sealing the decision, 0 while pending
+	Height int64 `json:"height"`
+}
+
+// decisionFilter empty fields match any decision
+type decisionFilter struct {
+	Subject  string
+	Resource string
+	Outcome  string
+	// Start inclusive, End exclusive, zero is unbounded
+	Start time.Time
+	End   time.Time
+}
+
+// dedupKey key of the recorded decision, a retried request with the same
+// request id maps to it while a different decision never does
+func (d decision) dedupKey() string {
+	h := sha256.New()
+	for _, field := range []string{d.RequestID, d.Subject, d.Resource, d.Action, d.Outcome, d.PolicyVersion} {
+		// length prefixed so fields cannot run into each other
+		_, _ = fmt.Fprintf(h, "%d:%s", len(field), field)
+	}
+	return decisionPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// validate reject control characters, subject, resource and
+// outcome are index values and a separator would corrupt them
+func (d decision) validate() error {
+	for _, field := range []string{d.Subject, d.Resource, d.Action, d.Outcome, d.PolicyVersion, d.RequestID} {
+		if strings.IndexFunc(field, unicode.IsControl) >= 0 {
+			return fmt.Errorf("%w: control character in %q", errInvalidDecision, field)
+		}
+	}
+	return nil
+}
+
+func newDecisionRecord(t *tx, height int64) (*decisionRecord, error) {
+	var d decision
+	err := json.Unmarshal(t.Data, &d)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return &decisionRecord{
+		decision:  d,
+		TxHash:    t.Hash,
+		Timestamp: t.Timestamp,
+		Height:    height,
+	}, nil
+}
+
+func (r *decisionRecord) key() string {
+	return decisionLogKey(r.Timestamp) + "_" + r.TxHash
+}
+
+// put record with its secondary indices
+func (r *decisionRecord) put(b *keyvalue.Batch) {
+	rb, _ := json.Marshal(r)
+	b.Put(r.key(), rb, map[string]string{
+		subjectIndex:  r.Subject,
+		resourceIndex: r.Resource,
+		outcomeIndex:  r.Outcome,
+	}, -1)
+}
+
+func decisionLogKey(t time.Time) string {
+	return fmt.Sprintf("%s%019d", decisionLogPrefix, t.UnixNano())
+}
+
+// bounds key range covering the filter time range
+func (f decisionFilter) bounds() (string, string) {
+	start, end := decisionLogPrefix, decisionLogEnd
+	if !f.Start.IsZero() {
+		start = decisionLogKey(f.Start)
+	}
+	if !f.End.IsZero() {
+		end = decisionLogKey(f.End)
+	}
+	return start, end
+}
+
+func (f decisionFilter) match(r *decisionRecord) bool {
+	return (f.Subject == "" || f.Subject == r.Subject) &&
+		(f.Resource == "" || f.Resource == r.Resource) &&
+		(f.Outcome == "" || f.Outcome == r.Outcome)
+}
+
+// recordDecision sign decision into a pending transaction
+//
+// a retried decision returns the transaction already recorded
+func (s *serviceImpl) recordDecision(d decision) (*tx, error) {
+	s.decisionMu.Lock()
+	defer s.decisionMu.Unlock()
+
+	hash, err := s.store.Get(d.dedupKey())
+	if err == nil {
+		return s.getTx(string(hash))
+	} else if !errors.Is(err, keyvalue.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get decision: %w", err)
+	}
+
+	db, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	t := &tx{
+		From:      s.sender,
+		To:        decisionTo,
+		Amount:    coinTxAmount,
+		Data:      db,
+		Timestamp: time.Now().UTC(),
+	}
+
+	err = t.signAndHash(s.signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign and hash tx: %w", err)
+	}
+
+	err = s.propose(&command{Txs: []*tx{t}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit decision: %w", err)
+	}
+
+	// a decision committed concurrently by a previous
+	// leader takes precedence over this one
+	hash, err = s.store.Get(d.dedupKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decision: %w", err)
+	}
+	if string(hash) != t.Hash {
+		return s.getTx(string(hash))
+	}
+
+	return t, nil
+}
+
+func (s *serviceImpl) getTx(hash string) (*tx, error) {
+	tb, err := s.store.Get(txPrefix + hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx %s: %w", hash, err)
+	}
+
+	var t tx
+	err = json.Unmarshal(tb, &t)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return &t, nil
+}
+
+// listDecisions page of up to limit decisions matching the filter
+// after cursor, oldest first
+//
+// the returned cursor is empty on the last page
+func (s *serviceImpl) listDecisions(f decisionFilter, cursor string, limit int) ([]*decisionRecord, string, error) {
+	if limit <= 0 {
+		limit = defaultDecisionLimit
+	}
+
+	start, end := f.bounds()
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		// resume strictly after the last key of the previous page
+		start = max(start, after+"\x00")
+	}
+
+	records := make([]*decisionRecord, 0, limit+1)
+	err := s.walkDecisions(f, start, end, func(r *decisionRecord) bool {
+		records = append(records, r)
+		return len(records) <= limit
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(records) <= limit {
+		return records, "", nil
+	}
+
+	records = records[:limit]
+	return records, encodeCursor(records[limit-1].key()), nil
+}
+
+// exportDecisions call fn with every decision matching the filter
+func (s *serviceImpl) exportDecisions(f decisionFilter, fn func(*decisionRecord) error) error {
+	var fnErr error
+
+	start, end := f.bounds()
+	err := s.walkDecisions(f, start, end, func(r *decisionRecord) bool {
+		fnErr = fn(r)
+		return fnErr == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return fnErr
+}
+
+// walkDecisions call fn in key order with decisions matching the
+// filter in [start, end) until fn returns false
+//
+// the most selective secondary index in the filter drives the scan,
+// remaining criteria are matched on the decoded record
+func (s *serviceImpl) walkDecisions(f decisionFilter, start, end string, fn func(*decisionRecord) bool) error {
+	var it keyvalue.Iterator
+	switch {
+	case f.Subject != "":
+		it = s.store.GetByIndexRange(subjectIndex, f.Subject, start, end)
+	case f.Resource != "":
+		it = s.store.GetByIndexRange(resourceIndex, f.Resource, start, end)
+	case f.Outcome != "":
+		it = s.store.GetByIndexRange(outcomeIndex, f.Outcome, start, end)
+	default:
+		it = s.store.Range(start, end)
+	}
+	defer it.Close()
+
+	for it.HasNext() {
+		_, rb, err := it.Next()
+		if err != nil {
+			return fmt.Errorf("failed to read decision: %w", err)
+		}
+
+		var r decisionRecord
+		err = json.Unmarshal(rb, &r)
+		if err != nil {
+			return fmt.Errorf("json.Unmarshal: %w", err)
+		}
+
+		if f.match(&r) && !fn(&r) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func encodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	kb, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(kb), decisionLogPrefix) {
+		return "", errInvalidCursor
+	}
+	return string(kb), nil
+}
@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+
+	pbkv "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
+)
+
+const (
+	// cursorKey next sequence number of the followed change feed
+	cursorKey = "cursor_identities"
+	// txPrefix key prefix of transactions
+	txPrefix = "tx_"
+
+	followPrefix  = "identities/"
+	followBackoff = time.Second
+)
+
+// Follow turn identity changes published to the keyvalue
+// service into transactions until ctx is done
+//
+// only the leader of a cluster follows the change feed
+//
+// the position in the change feed is committed together with
+// the transactions so a restart resumes without gaps or duplicates
+func (s *serviceImpl) Follow(ctx context.Context, logger *slog.Logger, client pbkv.KeyValueServiceClient) error {
+	for {
+		err := s.follow(ctx, client)
+		if ctx.Err() != nil {
+			return nil
+		} else if status.Code(err) == codes.OutOfRange {
+			return fmt.Errorf("identity changes no longer retained: %w", err)
+		}
+
+		// followers wait for leadership before subscribing
+		if !errors.Is(err, errNotLeader) {
+			logger.WarnContext(ctx, "identity change feed interrupted", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(followBackoff):
+		}
+	}
+}
+
+func (s *serviceImpl) follow(ctx context.Context, client pbkv.KeyValueServiceClient) error {
+	if !s.repl.leader() {
+		return errNotLeader
+	}
+
+	from, err := s.cursor()
+	if err != nil {
+		return err
+	}
+
+	stream, err := client.Subscribe(ctx, &pbkv.SubscribeRequest{
+		FromSeq: from,
+		Prefix:  followPrefix,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		txs := make([]*tx, 0, len(resp.Events))
+		for _, e := range resp.Events {
+			tx, err := s.changeTx(e)
+			if err != nil {
+				return err
+			}
+			txs = append(txs, tx)
+		}
+
+		cursor := resp.Seq + 1
+		err = s.propose(&command{Txs: txs, Cursor: &cursor})
+		if err != nil {
+			return fmt.Errorf("failed to commit transactions: %w", err)
+		}
+	}
+}
+
+// cursor sequence number to resume from, zero for the oldest retained
+func (s *serviceImpl) cursor() (uint64, error) {
+	vb, err := s.store.Get(cursorKey)
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get cursor: %w", err)
+	}
+
+	seq, err := strconv.ParseUint(string(vb), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("strconv.ParseUint: %w", err)
+	}
+
+	return seq, nil
+}
+
+// changeTx signed transaction recording a change
+func (s *serviceImpl) changeTx(e *pbkv.Event) (*tx, error) {
+	tx := &tx{
+		From:      s.sender,
+		To:        e.Entry.Key,
+		Amount:    coinTxAmount,
+		Data:      e.Entry.Value,
+		Timestamp: time.Now().UTC(),
+	}
+
+	if e.Type == pbkv.Event_TYPE_DELETE {
+		tx.Data = []byte("deleted")
+	}
+
+	err := tx.signAndHash(s.signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign and hash tx: %w", err)
+	}
+
+	return tx, nil
+}
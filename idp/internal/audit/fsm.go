@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+// fsm raft state machine applying commands to the service
+type fsm serviceImpl
+
+// snapshotState head of a snapshot, followed by every
+// block from genesis up to Height
+type snapshotState struct {
+	Applied uint64 `json:"applied"`
+	Height  int64  `json:"height"`
+	Cursor  uint64 `json:"cursor"`
+	Pending []*tx  `json:"pending"`
+	// Keys registered sender public keys
+	Keys map[string]string `json:"keys"`
+}
+
+// snapshot point in time copy of the ledger, sealed blocks
+// never change so they are read while persisting
+type snapshot struct {
+	s     *serviceImpl
+	state snapshotState
+}
+
+// Apply raft.FSM
+func (f *fsm) Apply(l *raft.Log) any {
+	var c command
+	err := json.Unmarshal(l.Data, &c)
+	if err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return (*serviceImpl)(f).apply(&c, l.Index)
+}
+
+// Snapshot raft.FSM
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	s := (*serviceImpl)(f)
+
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+
+	cursor, err := s.cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.registeredKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	state := snapshotState{
+		Applied: s.applied,
+		Cursor:  cursor,
+		Pending: s.pool.all(),
+		Keys:    keys,
+	}
+	if s.tip != nil {
+		state.Height = s.tip.Height
+	}
+
+	return &snapshot{s: s, state: state}, nil
+}
+
+// Restore raft.FSM
+//
+// blocks above the local tip are appended and pending
+// transactions replaced, a snapshot older than the applied
+// state is ignored as the store already holds it
+func (f *fsm) Restore(rc io.ReadCloser) error {
+	defer func() { _ = rc.Close() }()
+
+	s := (*serviceImpl)(f)
+	dec := json.NewDecoder(rc)
+
+	var state snapshotState
+	err := dec.Decode(&state)
+	if err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	if state.Applied <= s.applied {
+		return nil
+	}
+
+	for height := int64(defaultHeight); height <= state.Height; height++ {
+		var b block
+		err = dec.Decode(&b)
+		if err != nil {
+			return fmt.Errorf("failed to decode snapshot block %d: %w", height, err)
+		}
+
+		if s.tip != nil && b.Height <= s.tip.Height {
+			continue
+		} else if err := s.extendsTip(&b); err != nil {
+			return fmt.Errorf("snapshot block %d: %w", height, err)
+		}
+
+		batch := keyvalue.NewBatch()
+		err = putBlock(batch, &b)
+		if err != nil {
+			return err
+		}
+
+		err = s.store.Write(batch)
+		if err != nil {
+			return fmt.Errorf("failed to write snapshot block %d: %w", height, err)
+		}
+		s.tip = &b
+	}
+
+	batch := keyvalue.NewBatch()
+	for sender, key := range state.Keys {
+		batch.Put(keyPrefix+sender, []byte(key), nil, -1)
+	}
+	for _, tx := range state.Pending {
+		err = putPendingTx(batch, tx)
+		if err != nil {
+			return err
+		}
+	}
+	batch.Put(cursorKey, []byte(strconv.FormatUint(state.Cursor, 10)), nil, -1)
+	batch.Put(appliedKey, []byte(strconv.FormatUint(state.Applied, 10)), nil, -1)
+
+	err = s.store.Write(batch)
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot state: %w", err)
+	}
+
+	s.applied = state.Applied
+	s.pool.reset(state.Pending)
+
+	return nil
+}
+
+// Persist raft.FSMSnapshot
+func (sn *snapshot) Persist(sink raft.SnapshotSink) error {
+	err := sn.persist(sink)
+	if err != nil {
+		return errors.Join(err, sink.Cancel())
+	}
+	return sink.Close()
+}
+
+func (sn *snapshot) persist(w io.Writer) error {
+	enc := json.NewEncoder(w)
+
+	err := enc.Encode(sn.state)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	for height := int64(defaultHeight); height <= sn.state.Height; height++ {
+		b, err := sn.s.blockAt(height)
+		if err != nil {
+			return err
+		}
+
+		err = enc.Encode(b)
+		if err != nil {
+			return fmt.Errorf("failed to encode block %d: %w", height, err)
+		}
+	}
+
+	return nil
+}
+
+// Release raft.FSMSnapshot
+func (sn *snapshot) Release() {}
@@ -0,0 +1,245 @@
+package audit
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+const (
+	// keyPrefix key prefix of sender address to hex public key
+	keyPrefix = "key_"
+	// keyEnd exclusive upper bound of sender keys
+	keyEnd = "key`"
+)
+
+var (
+	errUnknownSender = errors.New("sender has no registered key")
+	errKeyConflict   = errors.New("sender is registered with another key")
+	errKeyAddress    = errors.New("public key does not match sender address")
+)
+
+// publicKey hex public key of the node wallet
+func (s *serviceImpl) publicKey() (string, error) {
+	pb, err := s.signer.Public().MarshalBinary()
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	return hex.EncodeToString(pb), nil
+}
+
+// propose commit c once the node key is registered so
+// every node can verify what this node signs
+//
+// c is verified first so a command every node would
+// reject never reaches the log
+func (s *serviceImpl) propose(c *command) error {
+	_, err := s.store.Get(keyPrefix + s.sender)
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		key, err := s.publicKey()
+		if err != nil {
+			return err
+		}
+
+		err = s.repl.commit(&command{Keys: map[string]string{s.sender: key}})
+		if err != nil {
+			return fmt.Errorf("failed to register key: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get sender key: %w", err)
+	}
+
+	err = s.verifyCommand(c)
+	if err != nil {
+		return err
+	}
+
+	return s.repl.commit(c)
+}
+
+// validateSender check sender is an address on the service network
+func (s *serviceImpl) validateSender(sender string) error {
+	err := wallet.ValidateAddress(sender, s.network)
+	if err != nil {
+		return fmt.Errorf("sender %q: %w", sender, err)
+	}
+	return nil
+}
+
+// senderKeyHex registered hex public key of sender
+func (s *serviceImpl) senderKeyHex(sender string) (string, error) {
+	err := s.validateSender(sender)
+	if err != nil {
+		return "", err
+	}
+
+	kb, err := s.store.Get(keyPrefix + sender)
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return "", fmt.Errorf("%w: %q", errUnknownSender, sender)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get sender key: %w", err)
+	}
+	return string(kb), nil
+}
+
+// senderKey registered public key of sender
+func (s *serviceImpl) senderKey(sender string) (kyber.Point, error) {
+	key, err := s.senderKeyHex(sender)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseKey(key)
+}
+
+func (s *serviceImpl) parseKey(key string) (kyber.Point, error) {
+	kb, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %q", key)
+	}
+
+	p := s.suite.Point()
+	err = p.UnmarshalBinary(kb)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	return p, nil
+}
+
+// verifyTx check tx is signed by the registered key of its sender
+func (s *serviceImpl) verifyTx(t *tx) error {
+	key, err := s.senderKey(t.From)
+	if err != nil {
+		return fmt.Errorf("tx %s: %w", t.Hash, err)
+	}
+
+	err = t.verify(s.suite, key)
+	if err != nil {
+		return fmt.Errorf("tx %s: %w", t.Hash, err)
+	}
+
+	return nil
+}
+
+// verifySigs check the block hash and every tx
+// are signed by their registered senders
+func (s *serviceImpl) verifySigs(b *block) error {
+	registered, err := s.senderKeyHex(b.Signer)
+	if err != nil {
+		return fmt.Errorf("block %s: %w", b.Hash, err)
+	}
+	if registered != b.SignerKey {
+		return fmt.Errorf("block %s: signer key is not the key registered for %s", b.Hash, b.Signer)
+	}
+
+	key, err := s.parseKey(registered)
+	if err != nil {
+		return fmt.Errorf("block %s: %w", b.Hash, err)
+	}
+
+	hb, err := hex.DecodeString(b.Hash)
+	if err != nil {
+		return fmt.Errorf("invalid block hash %q", b.Hash)
+	}
+
+	err = verifySig(s.suite, key, hb, b.Sig)
+	if err != nil {
+		return fmt.Errorf("block %s: %w", b.Hash, err)
+	}
+
+	for _, t := range b.Txs {
+		err = s.verifyTx(t)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registeredKeys every sender and its hex public key
+func (s *serviceImpl) registeredKeys() (map[string]string, error) {
+	keys := make(map[string]string)
+
+	it := s.store.Range(keyPrefix, keyEnd)
+	defer it.Close()
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sender key: %w", err)
+		}
+		keys[k[len(keyPrefix):]] = string(v)
+	}
+
+	return keys, nil
+}
+
+// verifyCommand reject keys conflicting with registered ones or
+// their sender address, transactions or blocks not signed
+// by their registered sender and invalid decisions
+func (s *serviceImpl) verifyCommand(c *command) error {
+	for sender, key := range c.Keys {
+		registered, err := s.senderKeyHex(sender)
+		if errors.Is(err, errUnknownSender) {
+			err = s.verifyKeyAddress(sender, key)
+			if err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if registered != key {
+			return fmt.Errorf("%w: %q", errKeyConflict, sender)
+		}
+	}
+
+	for _, t := range c.Txs {
+		err := s.verifyTx(t)
+		if err != nil {
+			return err
+		}
+
+		if t.To != decisionTo {
+			continue
+		}
+		r, err := newDecisionRecord(t, 0)
+		if err != nil {
+			return fmt.Errorf("tx %s: %w", t.Hash, err)
+		}
+		err = r.validate()
+		if err != nil {
+			return fmt.Errorf("tx %s: %w", t.Hash, err)
+		}
+	}
+
+	if c.Block != nil {
+		return s.verifySigs(c.Block)
+	}
+
+	return nil
+}
+
+// verifyKeyAddress check sender is the address of key
+func (s *serviceImpl) verifyKeyAddress(sender, key string) error {
+	p, err := s.parseKey(key)
+	if err != nil {
+		return err
+	}
+
+	addr, err := wallet.ParseAddress(sender)
+	if err != nil {
+		return fmt.Errorf("sender %q: %w", sender, err)
+	}
+	if !addr.Matches(p) {
+		return fmt.Errorf("%w: %q", errKeyAddress, sender)
+	}
+
+	return nil
+}
@@ -1,6 +0,0 @@
-package lsm
-
-import "errors"
-
-// ErrNotFound key not found
-var ErrNotFound = errors.New("key not found")
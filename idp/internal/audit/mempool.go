@@ -0,0 +1,63 @@
+package audit
+
+import "sync"
+
+// mempool transactions waiting to be sealed into a block, oldest first
+type mempool struct {
+	mu  sync.Mutex
+	txs []*tx
+}
+
+func newMempool() *mempool {
+	return &mempool{
+		txs: make([]*tx, 0),
+	}
+}
+
+func (p *mempool) add(txs ...*tx) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.txs = append(p.txs, txs...)
+}
+
+// peek up to n oldest transactions
+func (p *mempool) peek(n int) []*tx {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n = min(n, len(p.txs))
+	return append([]*tx(nil), p.txs[:n]...)
+}
+
+// remove transactions once they are sealed
+func (p *mempool) remove(sealed []*tx) {
+	hashes := make(map[string]struct{}, len(sealed))
+	for _, tx := range sealed {
+		hashes[tx.Hash] = struct{}{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	txs := make([]*tx, 0, len(p.txs))
+	for _, tx := range p.txs {
+		if _, ok := hashes[tx.Hash]; !ok {
+			txs = append(txs, tx)
+		}
+	}
+	p.txs = txs
+}
+
+// reset replace every transaction
+func (p *mempool) reset(txs []*tx) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.txs = append([]*tx(nil), txs...)
+}
+
+// all pending transactions oldest first
+func (p *mempool) all() []*tx {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*tx(nil), p.txs...)
+}
@@ -0,0 +1,195 @@
+package audit
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+const (
+	// logPrefix key prefix of raft log entries by index
+	logPrefix = "log_"
+	// logEnd exclusive upper bound of raft log keys
+	logEnd = "log`"
+	// stablePrefix key prefix of raft stable state
+	stablePrefix = "stable_"
+)
+
+var (
+	// errKeyNotFound raft matches missing stable keys by message
+	errKeyNotFound = errors.New("not found")
+)
+
+// logStore raft log and stable store backed by a keyvalue store
+type logStore struct {
+	store keyvalue.Store
+
+	// mu guards the cached index bounds
+	mu    sync.RWMutex
+	first uint64
+	last  uint64
+}
+
+func newLogStore(store keyvalue.Store) (*logStore, error) {
+	ls := &logStore{store: store}
+
+	it := store.Range(logPrefix, logEnd)
+	defer it.Close()
+	for it.HasNext() {
+		key, _, err := it.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read raft log: %w", err)
+		}
+
+		index, err := strconv.ParseUint(key[len(logPrefix):], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("strconv.ParseUint: %w", err)
+		}
+
+		if ls.first == 0 {
+			ls.first = index
+		}
+		ls.last = index
+	}
+
+	return ls, nil
+}
+
+func logKey(index uint64) string {
+	return fmt.Sprintf("%s%020d", logPrefix, index)
+}
+
+// FirstIndex raft.LogStore
+func (ls *logStore) FirstIndex() (uint64, error) {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	return ls.first, nil
+}
+
+// LastIndex raft.LogStore
+func (ls *logStore) LastIndex() (uint64, error) {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	return ls.last, nil
+}
+
+// GetLog raft.LogStore
+func (ls *logStore) GetLog(index uint64, log *raft.Log) error {
+	lb, err := ls.store.Get(logKey(index))
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return raft.ErrLogNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to get raft log %d: %w", index, err)
+	}
+
+	err = json.Unmarshal(lb, log)
+	if err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return nil
+}
+
+// StoreLog raft.LogStore
+func (ls *logStore) StoreLog(log *raft.Log) error {
+	return ls.StoreLogs([]*raft.Log{log})
+}
+
+// StoreLogs raft.LogStore
+func (ls *logStore) StoreLogs(logs []*raft.Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	b := keyvalue.NewBatch()
+	for _, log := range logs {
+		lb, err := json.Marshal(log)
+		if err != nil {
+			return fmt.Errorf("json.Marshal: %w", err)
+		}
+		b.Put(logKey(log.Index), lb, nil, -1)
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	err := ls.store.Write(b)
+	if err != nil {
+		return fmt.Errorf("failed to write raft logs: %w", err)
+	}
+
+	if ls.first == 0 {
+		ls.first = logs[0].Index
+	}
+	ls.last = max(ls.last, logs[len(logs)-1].Index)
+
+	return nil
+}
+
+// DeleteRange raft.LogStore
+func (ls *logStore) DeleteRange(minIndex, maxIndex uint64) error {
+	b := keyvalue.NewBatch()
+	for index := minIndex; index <= maxIndex; index++ {
+		b.Delete(logKey(index))
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	err := ls.store.Write(b)
+	if err != nil {
+		return fmt.Errorf("failed to delete raft logs: %w", err)
+	}
+
+	if minIndex <= ls.first {
+		ls.first = maxIndex + 1
+	}
+	if maxIndex >= ls.last {
+		ls.last = minIndex - 1
+	}
+	if ls.first > ls.last {
+		ls.first, ls.last = 0, 0
+	}
+
+	return nil
+}
+
+// Set raft.StableStore
+func (ls *logStore) Set(key []byte, val []byte) error {
+	return ls.store.Put(stablePrefix+string(key), val, nil, -1)
+}
+
+// Get raft.StableStore
+func (ls *logStore) Get(key []byte) ([]byte, error) {
+	val, err := ls.store.Get(stablePrefix + string(key))
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return nil, errKeyNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	return val, nil
+}
+
+// SetUint64 raft.StableStore
+func (ls *logStore) SetUint64(key []byte, val uint64) error {
+	return ls.Set(key, binary.BigEndian.AppendUint64(nil, val))
+}
+
+// GetUint64 raft.StableStore
+func (ls *logStore) GetUint64(key []byte) (uint64, error) {
+	val, err := ls.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(val) != 8 {
+		return 0, fmt.Errorf("invalid uint64 %s", key)
+	}
+	return binary.BigEndian.Uint64(val), nil
+}
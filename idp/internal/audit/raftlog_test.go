@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+func TestLogStoreCompaction(t *testing.T) {
+	dir := t.TempDir()
+	lsm, err := keyvalue.New(dir,
+		keyvalue.WithMemtableSize(4096),
+		keyvalue.WithSync(false),
+		keyvalue.WithCompactionTrigger(raftLogCompaction),
+	)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	ls, err := newLogStore(lsm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// raft truncates the log behind every snapshot
+	data := bytes.Repeat([]byte("x"), 256)
+	var written int
+	for index := uint64(1); index <= 400; index++ {
+		log := &raft.Log{Index: index, Term: 1, Data: data}
+		lb, err := json.Marshal(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		written += len(lb)
+
+		err = ls.StoreLog(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index%50 == 0 {
+			if err = ls.DeleteRange(index-49, index-10); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// truncated entries and their tombstones are compacted away
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		tables, err := filepath.Glob(filepath.Join(dir, "sstable_*.data"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var size int64
+		for _, table := range tables {
+			fi, err := os.Stat(table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			size += fi.Size()
+		}
+		if len(tables) < raftLogCompaction && size < int64(written/2) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d tables of %d bytes left uncompacted", len(tables), size)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	var log raft.Log
+	if err = ls.GetLog(1, &log); !errors.Is(err, raft.ErrLogNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, raft.ErrLogNotFound)
+	}
+	if err = ls.GetLog(400, &log); err != nil || !bytes.Equal(log.Data, data) {
+		t.Fatalf("failed to get log 400: %v", err)
+	}
+}
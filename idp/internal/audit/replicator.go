@@ -0,0 +1,174 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+const (
+	raftTimeout        = time.Second * 10
+	raftSnapshotRetain = 2
+	// raftLogCompaction tables flushed before the raft log is
+	// compacted, discarding the entries raft truncated
+	raftLogCompaction = 4
+)
+
+var (
+	errNotLeader = errors.New("node is not the leader")
+)
+
+// replicator commits commands to every node of the ledger
+type replicator interface {
+	// commit command once it is applied locally
+	commit(c *command) error
+	// leader node accepts commands
+	leader() bool
+	close() error
+}
+
+// Cluster raft replication of the ledger
+type Cluster struct {
+	// NodeID raft server id of this node
+	NodeID string
+	// Dir raft log and snapshot directory
+	Dir string
+	// Transport to the other nodes
+	Transport raft.Transport
+	// Servers the cluster is bootstrapped with, empty
+	// for a node joining an existing cluster
+	Servers []raft.Server
+	// Config raft config, defaults to raft.DefaultConfig
+	Config *raft.Config
+}
+
+// WithCluster replicate the ledger with raft, blocks are
+// produced by the leader and applied by every node
+func WithCluster(c Cluster) ServiceOption {
+	return func(s *serviceImpl) {
+		s.cluster = &c
+	}
+}
+
+// localReplicator single node applying commands directly
+type localReplicator struct {
+	s *serviceImpl
+}
+
+func (r localReplicator) commit(c *command) error {
+	return r.s.apply(c, 0)
+}
+
+func (r localReplicator) leader() bool {
+	return true
+}
+
+func (r localReplicator) close() error {
+	return nil
+}
+
+// raftReplicator node of a raft cluster
+type raftReplicator struct {
+	raft *raft.Raft
+	logs *keyvalue.LSM
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newRaftReplicator(s *serviceImpl, c Cluster) (*raftReplicator, error) {
+	conf := raft.DefaultConfig()
+	conf.LogLevel = "WARN"
+	if c.Config != nil {
+		conf = c.Config
+	}
+	conf.LocalID = raft.ServerID(c.NodeID)
+
+	logDir := filepath.Join(c.Dir, "log")
+	err := os.MkdirAll(logDir, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create raft log dir: %w", err)
+	}
+
+	logs, err := keyvalue.New(logDir, keyvalue.WithCompactionTrigger(raftLogCompaction))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raft log: %w", err)
+	}
+
+	store, err := newLogStore(logs)
+	if err != nil {
+		_ = logs.Close()
+		return nil, err
+	}
+
+	snaps, err := raft.NewFileSnapshotStore(c.Dir, raftSnapshotRetain, os.Stderr)
+	if err != nil {
+		_ = logs.Close()
+		return nil, fmt.Errorf("failed to open raft snapshots: %w", err)
+	}
+
+	r, err := raft.NewRaft(conf, (*fsm)(s), store, store, snaps, c.Transport)
+	if err != nil {
+		_ = logs.Close()
+		return nil, fmt.Errorf("failed to start raft: %w", err)
+	}
+
+	if len(c.Servers) > 0 {
+		err = r.BootstrapCluster(raft.Configuration{Servers: c.Servers}).Error()
+		if err != nil && !errors.Is(err, raft.ErrCantBootstrap) {
+			_ = r.Shutdown().Error()
+			_ = logs.Close()
+			return nil, fmt.Errorf("failed to bootstrap cluster: %w", err)
+		}
+	}
+
+	return &raftReplicator{
+		raft: r,
+		logs: logs,
+	}, nil
+}
+
+func (r *raftReplicator) commit(c *command) error {
+	cb, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	f := r.raft.Apply(cb, raftTimeout)
+	err = f.Error()
+	if errors.Is(err, raft.ErrNotLeader) || errors.Is(err, raft.ErrLeadershipLost) {
+		return errNotLeader
+	} else if err != nil {
+		return fmt.Errorf("failed to apply command: %w", err)
+	}
+
+	if err, ok := f.Response().(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+func (r *raftReplicator) leader() bool {
+	return r.raft.State() == raft.Leader
+}
+
+func (r *raftReplicator) close() error {
+	r.closeOnce.Do(func() {
+		err := r.raft.Shutdown().Error()
+		if err != nil {
+			r.closeErr = fmt.Errorf("failed to shutdown raft: %w", err)
+			return
+		}
+		r.closeErr = r.logs.Close()
+	})
+	return r.closeErr
+}
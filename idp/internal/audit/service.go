@@ -1,83 +1,281 @@
 package audit
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
-	"github.com/trevatk/tbd/idp/internal/audit/lsm"
+	"github.com/trevatk/tbd/lib/keyvalue"
 	"github.com/trevatk/tbd/lib/wallet"
 )
 
-const defaultHeight = 1
+const (
+	// tipKey hash of the latest block
+	tipKey = "tip"
+	// blockPrefix key prefix of blocks
+	blockPrefix = "block_"
 
-type block struct {
-	Hash      string    `json:"hash"`
-	PrevHash  string    `json:"prev_hash"`
-	Height    int64     `json:"height"`
-	Txs       []*tx     `json:"txs"`
-	Timestamp time.Time `json:"timestamp"`
-	Sig       string    `json:"sig"`
+	// heightIndex block height index
+	heightIndex = "height"
+	// blockIndex block a transaction was sealed in
+	blockIndex = "block"
+	// statusIndex pending transactions index
+	statusIndex   = "status"
+	statusPending = "pending"
+)
+
+// service implementation is an append only chain
+// of blocks sealing audit transactions
+//
+// transactions wait in the mempool until the next
+// block is produced on top of the current tip, every
+// change is committed through the replicator
+type serviceImpl struct {
+	suite wallet.Suite
+	store keyvalue.Store
+
+	signer  wallet.Signer
+	network wallet.Network
+	// sender address signing transactions and blocks of this node
+	sender string
+
+	cluster *Cluster
+	repl    replicator
+
+	// mu serialises block production
+	mu sync.Mutex
+
+	// stateMu guards state written as commands are applied
+	stateMu sync.RWMutex
+	tip     *block
+	applied uint64
+	pool    *mempool
+
+	// decisionMu serialises recording decisions by request id
+	decisionMu sync.Mutex
 }
 
-func (b block) Marshal() []byte {
-	bb, _ := json.Marshal(b)
-	return bb
+// ServiceOption audit service option pattern
+type ServiceOption func(*serviceImpl)
+
+// WithSigner node signer of transactions and blocks,
+// a random in memory wallet is used when not set
+func WithSigner(signer wallet.Signer) ServiceOption {
+	return func(s *serviceImpl) {
+		s.signer = signer
+	}
 }
 
-func genesisBlock(coinTx *tx) *block {
-	return &block{
-		Hash:      "000",
-		PrevHash:  "",
-		Height:    defaultHeight,
-		Txs:       []*tx{coinTx},
-		Timestamp: time.Now(),
+// WithNetwork network of sender addresses, defaults to mainnet
+func WithNetwork(network wallet.Network) ServiceOption {
+	return func(s *serviceImpl) {
+		s.network = network
 	}
 }
 
-// service implementation is a linked list
-// to manage all the realms
+// NewService return new audit service
 //
-// since this is a blockchain
-// we will need a genesis block
-// being the same as the master realm
-type serviceImpl struct {
-	suite wallet.Suite
-	store *lsm.LSM
+// the chain is resumed from the stored tip, a single node
+// creates the genesis block for an empty store while a
+// cluster leaves it to the first elected leader
+func NewService(suite wallet.Suite, store keyvalue.Store, opts ...ServiceOption) (*serviceImpl, error) {
+	s := &serviceImpl{
+		suite:   suite,
+		network: wallet.Mainnet,
+		store:   store,
+		pool:    newMempool(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.signer == nil {
+		s.signer = wallet.NewSigner(suite, wallet.NewV1(suite))
+	}
+
+	var err error
+	s.sender, err = wallet.SignerAddr(s.signer, s.network)
+	if err != nil {
+		return &serviceImpl{}, fmt.Errorf("failed to create sender address: %w", err)
+	}
+
+	tip, err := s.loadTip()
+	if err != nil && !errors.Is(err, keyvalue.ErrNotFound) {
+		return &serviceImpl{}, err
+	}
+	s.tip = tip
+
+	s.applied, err = s.loadApplied()
+	if err != nil {
+		return &serviceImpl{}, err
+	}
+
+	pending, err := s.pendingTxs()
+	if err != nil {
+		return &serviceImpl{}, err
+	}
+	s.pool.add(pending...)
+
+	if s.cluster != nil {
+		s.repl, err = newRaftReplicator(s, *s.cluster)
+		if err != nil {
+			return &serviceImpl{}, err
+		}
+		return s, nil
+	}
 
-	wallet wallet.Wallet
+	s.repl = localReplicator{s: s}
+	if s.tip == nil {
+		_, err = s.genesis()
+		if err != nil {
+			return &serviceImpl{}, err
+		}
+	}
+
+	return s, nil
 }
 
-// NewService return new audit service
-func NewService(suite wallet.Suite, store *lsm.LSM) (*serviceImpl, error) {
-	// TODO
-	// random cipher stream
-	w := wallet.NewV1(suite)
+// Close stop replicating the ledger
+func (s *serviceImpl) Close() error {
+	return s.repl.close()
+}
 
-	tx := newCoinTx()
-	err := tx.signAndHash(suite, w)
+// genesis commit the genesis block sealing the coin tx
+func (s *serviceImpl) genesis() (*block, error) {
+	tx := newCoinTx(s.sender)
+	err := tx.signAndHash(s.signer)
 	if err != nil {
-		return &serviceImpl{}, fmt.Errorf("failed to sign and hash coin tx: %w", err)
+		return nil, fmt.Errorf("failed to sign and hash coin tx: %w", err)
 	}
 
-	gb := genesisBlock(tx)
+	gb := genesisBlock(s.sender, tx)
+	err = gb.seal(s.signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal genesis block: %w", err)
+	}
 
-	err = store.Put(gb.Hash, gb.Marshal(), map[string]string{}, -1)
+	err = s.propose(&command{Block: gb})
 	if err != nil {
-		return &serviceImpl{}, fmt.Errorf("failed to put genesis block: %w", err)
+		return nil, fmt.Errorf("failed to commit genesis block: %w", err)
 	}
 
-	return &serviceImpl{
-		suite:  suite,
-		wallet: w,
-		store:  store,
-	}, nil
+	return gb, nil
+}
+
+// Produce seal pending transactions into a block
+// every interval until ctx is done
+//
+// only the leader of a cluster produces blocks
+func (s *serviceImpl) Produce(ctx context.Context, logger *slog.Logger, interval time.Duration, maxTxs int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b, err := s.produceBlock(maxTxs)
+			if err != nil {
+				logger.ErrorContext(ctx, "failed to produce block", "error", err)
+			} else if b != nil {
+				logger.DebugContext(ctx, "block produced", "height", b.Height, "hash", b.Hash, "txs", len(b.Txs))
+			}
+		}
+	}
 }
 
-func (s *serviceImpl) listTxs(limit, offset int64) ([]*tx, error) {
-	bb, err := s.store.Get("000")
+// produceBlock append a block of up to maxTxs pending
+// transactions to the chain, nil when none are pending
+// or the node is not the leader
+func (s *serviceImpl) produceBlock(maxTxs int) (*block, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.repl.leader() {
+		return nil, nil
+	}
+
+	tip := s.currentTip()
+	if tip == nil {
+		return s.genesis()
+	}
+
+	txs := s.pool.peek(maxTxs)
+	if len(txs) == 0 {
+		return nil, nil
+	}
+
+	b := newBlock(s.sender, tip, txs)
+	err := b.seal(s.signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal block: %w", err)
+	}
+
+	err = s.propose(&command{Block: b})
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit block: %w", err)
+	}
+
+	return b, nil
+}
+
+// currentTip latest applied block, nil before genesis
+func (s *serviceImpl) currentTip() *block {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	return s.tip
+}
+
+// putBlock add block, its transactions re-indexed
+// under the block and the new tip to batch
+//
+// decision records are updated with the block height
+func putBlock(batch *keyvalue.Batch, b *block) error {
+	batch.Put(blockPrefix+b.Hash, b.Marshal(), map[string]string{
+		heightIndex: strconv.FormatInt(b.Height, 10),
+	}, -1)
+
+	for _, tx := range b.Txs {
+		batch.Put(txPrefix+tx.Hash, tx.Marshal(), map[string]string{blockIndex: b.Hash}, -1)
+		batch.Put(txBlockPrefix+tx.Hash, []byte(b.Hash), nil, -1)
+
+		if tx.To != decisionTo {
+			continue
+		}
+
+		r, err := newDecisionRecord(tx, b.Height)
+		if err != nil {
+			return fmt.Errorf("failed to decode decision tx %s: %w", tx.Hash, err)
+		}
+		r.put(batch)
+	}
+
+	batch.Put(tipKey, []byte(b.Hash), nil, -1)
+
+	return nil
+}
+
+func (s *serviceImpl) loadTip() (*block, error) {
+	hash, err := s.store.Get(tipKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get genesis block: %w", err)
+		return nil, err
+	}
+
+	return s.getBlock(string(hash))
+}
+
+func (s *serviceImpl) getBlock(hash string) (*block, error) {
+	bb, err := s.store.Get(blockPrefix + hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s: %w", hash, err)
 	}
 
 	var b block
@@ -86,5 +284,55 @@ func (s *serviceImpl) listTxs(limit, offset int64) ([]*tx, error) {
 		return nil, fmt.Errorf("json.Unmarshal: %w", err)
 	}
 
-	return b.Txs, nil
+	return &b, nil
+}
+
+// blockAt block by height
+func (s *serviceImpl) blockAt(height int64) (*block, error) {
+	it := s.store.GetByIndex(heightIndex, strconv.FormatInt(height, 10))
+	defer it.Close()
+	if !it.HasNext() {
+		return nil, fmt.Errorf("block at height %d: %w", height, keyvalue.ErrNotFound)
+	}
+
+	_, bb, err := it.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block at height %d: %w", height, err)
+	}
+
+	var b block
+	err = json.Unmarshal(bb, &b)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return &b, nil
+}
+
+// pendingTxs transactions not yet sealed in a block
+func (s *serviceImpl) pendingTxs() ([]*tx, error) {
+	txs := make([]*tx, 0)
+
+	it := s.store.GetByIndex(statusIndex, statusPending)
+	defer it.Close()
+	for it.HasNext() {
+		_, tb, err := it.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pending tx: %w", err)
+		}
+
+		var t tx
+		err = json.Unmarshal(tb, &t)
+		if err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		txs = append(txs, &t)
+	}
+
+	// index order is by hash, the mempool is oldest first
+	sort.SliceStable(txs, func(i, j int) bool {
+		return txs[i].Timestamp.Before(txs[j].Timestamp)
+	})
+
+	return txs, nil
 }
@@ -2,11 +2,14 @@ package audit
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
+	"buf.build/go/protovalidate"
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/trevatk/tbd/lib/keyvalue"
 	"github.com/trevatk/tbd/lib/protocol"
 	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
 )
@@ -27,31 +30,175 @@ func NewTransport(logger *slog.Logger, svc *serviceImpl) (*grpc.ServiceDesc, pb.
 		}
 }
 
-func (t *transport) Decision(ctx context.Context, in *pb.CreateDecisionRequest) (*emptypb.Empty, error) {
-	return protocol.NewEmptyResponse(), nil
+// Decision
+func (t *transport) Decision(ctx context.Context, in *pb.DecisionRequest) (*pb.DecisionResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	d := decision{
+		Subject:       in.Subject,
+		Resource:      in.Resource,
+		Action:        in.Action,
+		Outcome:       in.Outcome.String(),
+		PolicyVersion: in.PolicyVersion,
+		RequestID:     in.RequestId,
+	}
+	if err := d.validate(); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	tx, err := t.svc.recordDecision(d)
+	if errors.Is(err, errInvalidDecision) {
+		return nil, protocol.ErrInvalidArgument()
+	} else if errors.Is(err, errNotLeader) {
+		return nil, protocol.ErrUnavailable()
+	} else if err != nil {
+		t.logger.ErrorContext(ctx, "failed to record decision", "error", err)
+		return nil, protocol.ErrInternal()
+	}
+
+	return &pb.DecisionResponse{
+		TxHash: tx.Hash,
+	}, nil
 }
 
+// ListDecisions
 func (t *transport) ListDecisions(ctx context.Context, in *pb.ListDecisionsRequest) (*pb.ListDecisionsResponse, error) {
-	txs, err := t.svc.listTxs(in.Limit, in.Offset)
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	records, cursor, err := t.svc.listDecisions(newDecisionFilter(in.Filter), in.Cursor, int(in.Limit))
+	if errors.Is(err, errInvalidCursor) {
+		return nil, protocol.ErrInvalidArgument()
+	} else if err != nil {
+		t.logger.ErrorContext(ctx, "failed to list decisions", "error", err)
+		return nil, protocol.ErrInternal()
+	}
+
+	decisions := make([]*pb.DecisionRecord, 0, len(records))
+	for _, r := range records {
+		decisions = append(decisions, transformDecision(r))
+	}
+
+	return &pb.ListDecisionsResponse{
+		Decisions:  decisions,
+		NextCursor: cursor,
+	}, nil
+}
+
+// ExportDecisions
+func (t *transport) ExportDecisions(in *pb.ExportDecisionsRequest, stream grpc.ServerStreamingServer[pb.DecisionRecord]) error {
+	if err := protovalidate.Validate(in); err != nil {
+		return protocol.ErrInvalidArgument()
+	}
+
+	var sendErr error
+	err := t.svc.exportDecisions(newDecisionFilter(in.Filter), func(r *decisionRecord) error {
+		sendErr = stream.Send(transformDecision(r))
+		return sendErr
+	})
+	if sendErr != nil {
+		return sendErr
+	} else if err != nil {
+		t.logger.ErrorContext(stream.Context(), "failed to export decisions", "error", err)
+		return protocol.ErrInternal()
+	}
+
+	return nil
+}
+
+// GetProof
+func (t *transport) GetProof(ctx context.Context, in *pb.GetProofRequest) (*pb.Proof, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	proof, err := t.svc.proof(in.TxHash)
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return nil, protocol.ErrNotFound()
+	} else if errors.Is(err, errTxPending) {
+		return nil, protocol.ErrFailedPrecondition()
+	} else if err != nil {
+		t.logger.ErrorContext(ctx, "failed to build proof", "error", err)
+		return nil, protocol.ErrInternal()
+	}
+
+	return proof, nil
+}
+
+// GetBlockHeader
+func (t *transport) GetBlockHeader(ctx context.Context, in *pb.GetBlockHeaderRequest) (*pb.BlockHeader, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	header, err := t.svc.header(in.Height)
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return nil, protocol.ErrNotFound()
+	} else if err != nil {
+		t.logger.ErrorContext(ctx, "failed to get block header", "error", err)
+		return nil, protocol.ErrInternal()
+	}
+
+	return header, nil
+}
+
+// VerifyChain
+func (t *transport) VerifyChain(ctx context.Context, _ *pb.VerifyChainRequest) (*pb.VerifyChainResponse, error) {
+	height, inc, err := t.svc.verifyChain()
 	if err != nil {
-		t.logger.ErrorContext(ctx, "failed to list transactions", "error", err)
+		t.logger.ErrorContext(ctx, "failed to verify chain", "error", err)
 		return nil, protocol.ErrInternal()
 	}
 
-	return newListDecisionsResponse(txs), nil
+	resp := &pb.VerifyChainResponse{
+		Valid:  inc == nil,
+		Height: height,
+	}
+	if inc != nil {
+		resp.Inconsistency = &pb.Inconsistency{
+			Height:    inc.Height,
+			BlockHash: inc.BlockHash,
+			Reason:    inc.Reason,
+		}
+	}
+
+	return resp, nil
 }
 
-func newListDecisionsResponse(txs []*tx) *pb.ListDecisionsResponse {
-	return &pb.ListDecisionsResponse{
-		Txs: []*pb.Tx{transformTx(txs[0])},
+func newDecisionFilter(in *pb.DecisionFilter) decisionFilter {
+	var f decisionFilter
+	if in == nil {
+		return f
+	}
+
+	f.Subject = in.Subject
+	f.Resource = in.Resource
+	if in.Outcome != pb.Outcome_OUTCOME_UNSPECIFIED {
+		f.Outcome = in.Outcome.String()
 	}
+	if in.Start != nil {
+		f.Start = in.Start.AsTime()
+	}
+	if in.End != nil {
+		f.End = in.End.AsTime()
+	}
+
+	return f
 }
 
-func transformTx(tx *tx) *pb.Tx {
-	return &pb.Tx{
-		Hash: tx.Hash,
-		From: tx.From,
-		To:   tx.To,
-		Sig:  tx.Sig,
+func transformDecision(r *decisionRecord) *pb.DecisionRecord {
+	return &pb.DecisionRecord{
+		TxHash:        r.TxHash,
+		Subject:       r.Subject,
+		Resource:      r.Resource,
+		Action:        r.Action,
+		Outcome:       pb.Outcome(pb.Outcome_value[r.Outcome]),
+		PolicyVersion: r.PolicyVersion,
+		RequestId:     r.RequestID,
+		Timestamp:     timestamppb.New(r.Timestamp),
+		Height:        r.Height,
 	}
 }
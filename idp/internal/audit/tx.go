@@ -2,16 +2,27 @@ package audit
 
 import (
 	"crypto/sha3"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"go.dedis.ch/kyber/v4"
+
 	"github.com/trevatk/tbd/lib/wallet"
 )
 
 const (
 	coinTxAmount = 0
+
+	// txVersion version of the canonical tx encoding
+	txVersion byte = 1
+)
+
+var (
+	errTxHash = errors.New("tx hash does not match its fields")
 )
 
 type tx struct {
@@ -24,37 +35,77 @@ type tx struct {
 	Sig       string    `json:"sig"`
 }
 
-func (tx *tx) signAndHash(suite wallet.Suite, wallet wallet.Wallet) error {
-	tb, err := json.Marshal(tx)
-	if err != nil {
-		return fmt.Errorf("tx json.Marshal: %w", err)
-	}
+func (tx *tx) Marshal() []byte {
+	tb, _ := json.Marshal(tx)
+	return tb
+}
+
+// encode canonical encoding of the fields covered by the tx hash
+//
+// | version | len from | from | len to | to | amount int64 | len data | data | timestamp unix nano int64 |
+func (tx *tx) encode() []byte {
+	buf := make([]byte, 0, 1+4*3+len(tx.From)+len(tx.To)+len(tx.Data)+8*2)
+	buf = append(buf, txVersion)
+	buf = appendBytes(buf, []byte(tx.From))
+	buf = appendBytes(buf, []byte(tx.To))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(tx.Amount))
+	buf = appendBytes(buf, tx.Data)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(tx.Timestamp.UnixNano()))
+	return buf
+}
+
+func appendBytes(buf, b []byte) []byte {
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(b)))
+	return append(buf, b...)
+}
 
-	c, err := wallet.Sign(suite, tb)
+// computeHash hex sha3-256 of the canonical encoding
+func (tx *tx) computeHash() string {
+	h := sha3.Sum256(tx.encode())
+	return hex.EncodeToString(h[:])
+}
+
+// signAndHash set the hash then sign it with the sender signer
+func (tx *tx) signAndHash(signer wallet.Signer) error {
+	tx.Hash = tx.computeHash()
+
+	hb, _ := hex.DecodeString(tx.Hash)
+	sig, err := signer.Sign(hb)
 	if err != nil {
 		return fmt.Errorf("failed to sign transaction: %w", err)
 	}
+	tx.Sig = hex.EncodeToString(sig)
 
-	cb, err := c.MarshalBinary()
-	if err != nil {
-		return fmt.Errorf("failed to marshal signature: %w", err)
+	return nil
+}
+
+// verify hash covers the tx fields and is signed by publicKey
+func (tx *tx) verify(suite wallet.Suite, publicKey kyber.Point) error {
+	if tx.computeHash() != tx.Hash {
+		return errTxHash
 	}
 
-	tx.Sig = hex.EncodeToString(cb)
+	hb, _ := hex.DecodeString(tx.Hash)
+	return verifySig(suite, publicKey, hb, tx.Sig)
+}
 
-	k := fmt.Sprintf("%s%d%d%s", tx.From, tx.Timestamp.UTC().UnixMilli(), tx.Amount, tx.To)
-	h := sha3.Sum224([]byte(k))
-	tx.Hash = hex.EncodeToString(h[:])
+// verifySig check hex sig of message by publicKey
+func verifySig(suite wallet.Suite, publicKey kyber.Point, message []byte, sig string) error {
+	sb, err := hex.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("invalid signature %q", sig)
+	}
 
-	return nil
+	return wallet.Verify(suite, publicKey, message, sb)
 }
 
-func newCoinTx() *tx {
+// newCoinTx coin tx sealed in the genesis block by sender
+func newCoinTx(sender string) *tx {
 	return &tx{
-		From:      "", // mirror genesis block hash
+		From:      sender,
 		To:        "master-realm",
 		Amount:    coinTxAmount,
-		Timestamp: time.Now(),
+		Timestamp: time.Now().UTC(),
 		Data:      []byte("master realm creation"),
 	}
 }
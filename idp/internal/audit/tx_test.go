@@ -0,0 +1,281 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+func TestTxVerify(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	w := wallet.NewV1(suite)
+	sender, err := w.Addr(wallet.Testnet)
+	if err != nil {
+		t.Fatalf("failed to create address: %v", err)
+	}
+
+	signed := func() *tx {
+		tx := &tx{
+			From:      sender,
+			To:        decisionTo,
+			Data:      []byte(`{"subject":"user"}`),
+			Timestamp: time.Now().UTC(),
+		}
+		if err := tx.signAndHash(wallet.NewSigner(suite, w)); err != nil {
+			t.Fatalf("failed to sign tx: %v", err)
+		}
+		return tx
+	}
+
+	t.Run("success", func(t *testing.T) {
+		if err := signed().verify(suite, w.P); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("tampered data", func(t *testing.T) {
+		tx := signed()
+		tx.Data = []byte(`{"subject":"admin"}`)
+		if err := tx.verify(suite, w.P); !errors.Is(err, errTxHash) {
+			t.Fatalf("unexpected error %v expected %v", err, errTxHash)
+		}
+	})
+	t.Run("other key", func(t *testing.T) {
+		other := suite.Point().Pick(suite.RandomStream())
+		if err := signed().verify(suite, other); !errors.Is(err, wallet.ErrInvalidSignature) {
+			t.Fatalf("unexpected error %v expected %v", err, wallet.ErrInvalidSignature)
+		}
+	})
+	t.Run("unique hash", func(t *testing.T) {
+		a, b := signed(), signed()
+		b.Timestamp = a.Timestamp
+		b.Data = []byte(`{"subject":"other"}`)
+		if a.computeHash() == b.computeHash() {
+			t.Fatal("hash does not cover data")
+		}
+	})
+}
+
+func TestApplyRejectsUnverifiedTx(t *testing.T) {
+	lsm, err := keyvalue.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	s, err := NewService(edwards25519.NewBlakeSHA256Ed25519(), lsm)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+
+	unknown, err := wallet.NewV1(s.suite).Addr(s.network)
+	if err != nil {
+		t.Fatalf("failed to create address: %v", err)
+	}
+
+	forged := &tx{From: "unknown", To: decisionTo, Timestamp: time.Now().UTC()}
+	if err := forged.signAndHash(s.signer); err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	if err := s.apply(&command{Txs: []*tx{forged}}, 0); !errors.Is(err, wallet.ErrInvalidAddress) {
+		t.Fatalf("unexpected error %v expected %v", err, wallet.ErrInvalidAddress)
+	}
+
+	forged.From = unknown
+	if err := s.apply(&command{Txs: []*tx{forged}}, 0); !errors.Is(err, errUnknownSender) {
+		t.Fatalf("unexpected error %v expected %v", err, errUnknownSender)
+	}
+
+	forged.From = s.sender
+	if err := s.apply(&command{Txs: []*tx{forged}}, 0); !errors.Is(err, errTxHash) {
+		t.Fatalf("unexpected error %v expected %v", err, errTxHash)
+	}
+
+	if len(s.pool.all()) != 0 {
+		t.Fatal("rejected tx added to the mempool")
+	}
+}
+
+func TestApplyRejectsKeyAddressMismatch(t *testing.T) {
+	lsm, err := keyvalue.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	s, err := NewService(edwards25519.NewBlakeSHA256Ed25519(), lsm, WithNetwork(wallet.Testnet))
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+
+	other, err := wallet.NewV1(s.suite).Addr(wallet.Testnet)
+	if err != nil {
+		t.Fatalf("failed to create address: %v", err)
+	}
+	key, err := s.publicKey()
+	if err != nil {
+		t.Fatalf("failed to get public key: %v", err)
+	}
+
+	err = s.apply(&command{Keys: map[string]string{other: key}}, 0)
+	if !errors.Is(err, errKeyAddress) {
+		t.Fatalf("unexpected error %v expected %v", err, errKeyAddress)
+	}
+
+	mainnet, err := wallet.SignerAddr(s.signer, wallet.Mainnet)
+	if err != nil {
+		t.Fatalf("failed to create address: %v", err)
+	}
+	err = s.apply(&command{Keys: map[string]string{mainnet: key}}, 0)
+	if !errors.Is(err, wallet.ErrNetworkMismatch) {
+		t.Fatalf("unexpected error %v expected %v", err, wallet.ErrNetworkMismatch)
+	}
+}
+
+func TestRecordDecisionRetry(t *testing.T) {
+	lsm, err := keyvalue.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	s, err := NewService(edwards25519.NewBlakeSHA256Ed25519(), lsm)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+
+	allow := decision{Subject: "alice", Resource: "acme", Action: "view", Outcome: "OUTCOME_ALLOW", RequestID: "request"}
+	first, err := s.recordDecision(allow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retried, err := s.recordDecision(allow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retried.Hash != first.Hash {
+		t.Fatalf("retried decision recorded as %s expected %s", retried.Hash, first.Hash)
+	}
+
+	// a reused request id never hides another decision
+	deny := allow
+	deny.Outcome = "OUTCOME_DENY"
+	denied, err := s.recordDecision(deny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if denied.Hash == first.Hash {
+		t.Fatal("decision with a reused request id not recorded")
+	}
+
+	records, _, err := s.listDecisions(decisionFilter{Subject: "alice"}, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("unexpected %d decisions expected 2", len(records))
+	}
+}
+
+func TestApplyInvalidDecision(t *testing.T) {
+	lsm, err := keyvalue.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	s, err := NewService(edwards25519.NewBlakeSHA256Ed25519(), lsm)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+
+	// a separator in an index value is rejected before it is proposed
+	invalid := decision{Subject: "al\x00ice", Resource: "acme", Action: "view", Outcome: "OUTCOME_ALLOW"}
+	if _, err = s.recordDecision(invalid); !errors.Is(err, errInvalidDecision) {
+		t.Fatalf("unexpected error %v expected %v", err, errInvalidDecision)
+	}
+
+	// and on apply when a node proposes it anyway
+	forged := &tx{From: s.sender, To: decisionTo, Data: []byte(`{"subject":"al\u0000ice"}`), Timestamp: time.Now().UTC()}
+	if err = forged.signAndHash(s.signer); err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	if err = s.apply(&command{Txs: []*tx{forged}}, 2); !errors.Is(err, errInvalidDecision) {
+		t.Fatalf("unexpected error %v expected %v", err, errInvalidDecision)
+	}
+
+	// a batch the store rejects is recorded as failed and skipped on replay
+	if err = s.rejectCommand(3, keyvalue.ErrInvalidIndex); !errors.Is(err, keyvalue.ErrInvalidIndex) {
+		t.Fatalf("unexpected error %v expected %v", err, keyvalue.ErrInvalidIndex)
+	}
+	if s.applied != 3 {
+		t.Fatalf("unexpected applied %d expected 3", s.applied)
+	}
+	if _, err = lsm.Get(fmt.Sprintf("%s%020d", failedPrefix, 3)); err != nil {
+		t.Fatalf("failed command not recorded: %v", err)
+	}
+	if err = s.apply(&command{Txs: []*tx{forged}}, 3); err != nil {
+		t.Fatalf("replayed command not skipped: %v", err)
+	}
+}
+
+// failingStore store failing the next writes
+type failingStore struct {
+	keyvalue.Store
+	failures int
+}
+
+func (f *failingStore) Write(b *keyvalue.Batch) error {
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("disk full")
+	}
+	return f.Store.Write(b)
+}
+
+func TestApplyWriteFailure(t *testing.T) {
+	lsm, err := keyvalue.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	store := &failingStore{Store: lsm}
+	s, err := NewService(edwards25519.NewBlakeSHA256Ed25519(), store)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	cursor := uint64(7)
+
+	// committed commands are retried until written
+	store.failures = writeAttempts - 1
+	if err = s.apply(&command{Cursor: &cursor}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if s.applied != 1 {
+		t.Fatalf("unexpected applied %d expected 1", s.applied)
+	}
+
+	// commands without an index fail the caller
+	store.failures = 1
+	if err = s.apply(&command{Cursor: &cursor}, 0); err == nil {
+		t.Fatal("expected write failure")
+	}
+
+	// the node halts rather than skip a committed command
+	store.failures = writeAttempts
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected node to halt")
+		}
+		if s.applied != 1 {
+			t.Fatalf("unexpected applied %d expected 1", s.applied)
+		}
+	}()
+	_ = s.apply(&command{Cursor: &cursor}, 2)
+}
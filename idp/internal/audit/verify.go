@@ -0,0 +1,182 @@
+package audit
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
+	"github.com/trevatk/tbd/lib/protocol/ledger"
+)
+
+const (
+	// txBlockPrefix key prefix of tx hash to sealing block hash
+	txBlockPrefix = "txblock_"
+)
+
+var (
+	errTxPending = errors.New("tx is not sealed in a block")
+)
+
+// inconsistency first block failing chain verification
+type inconsistency struct {
+	Height    int64
+	BlockHash string
+	Reason    string
+}
+
+// proof merkle inclusion proof of a sealed tx
+func (s *serviceImpl) proof(txHash string) (*pb.Proof, error) {
+	_, err := s.getTx(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	bh, err := s.store.Get(txBlockPrefix + txHash)
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return nil, errTxPending
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get tx block: %w", err)
+	}
+
+	b, err := s.getBlock(string(bh))
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, err := b.txLeaves()
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, tx := range b.Txs {
+		if tx.Hash == txHash {
+			index = i
+			break
+		}
+	}
+
+	path, err := ledger.MerkleProof(leaves, index)
+	if err != nil {
+		return nil, fmt.Errorf("tx %s not in block %s: %w", txHash, b.Hash, err)
+	}
+
+	return &pb.Proof{
+		TxHash:    txHash,
+		BlockHash: b.Hash,
+		Height:    b.Height,
+		Path:      path,
+		Index:     int64(index),
+	}, nil
+}
+
+// header signed header of the block at height, 0 for the tip
+func (s *serviceImpl) header(height int64) (*pb.BlockHeader, error) {
+	tip := s.currentTip()
+	if tip == nil {
+		return nil, fmt.Errorf("no genesis block: %w", keyvalue.ErrNotFound)
+	}
+
+	b := tip
+	if height != 0 && height != tip.Height {
+		var err error
+		b, err = s.blockAt(height)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b.blockHeader(), nil
+}
+
+// verifyChain walk the chain from genesis to tip, the height of the
+// last block checked is returned with the first inconsistency found
+//
+// the tip and its stored hash are captured together, blocks
+// produced during the walk are left to the next verification
+func (s *serviceImpl) verifyChain() (int64, *inconsistency, error) {
+	tip, th, err := s.capturedTip()
+	if err != nil || tip == nil {
+		return 0, nil, err
+	}
+
+	var prev *block
+	for height := int64(defaultHeight); height <= tip.Height; height++ {
+		b, err := s.blockAt(height)
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return height - 1, &inconsistency{Height: height, Reason: "missing block"}, nil
+		} else if err != nil {
+			return height - 1, nil, err
+		}
+
+		reason, err := s.verifyBlock(b, prev, height)
+		if err != nil {
+			return height - 1, nil, err
+		} else if reason != "" {
+			return height - 1, &inconsistency{Height: height, BlockHash: b.Hash, Reason: reason}, nil
+		}
+
+		prev = b
+	}
+
+	if th != prev.Hash {
+		return tip.Height, &inconsistency{Height: tip.Height, BlockHash: th, Reason: "tip does not match last block"}, nil
+	}
+
+	return tip.Height, nil, nil
+}
+
+// capturedTip applied tip and the tip hash stored with it
+func (s *serviceImpl) capturedTip() (*block, string, error) {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+
+	if s.tip == nil {
+		return nil, "", nil
+	}
+
+	th, err := s.store.Get(tipKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get tip: %w", err)
+	}
+
+	return s.tip, string(th), nil
+}
+
+// verifyBlock reason b is inconsistent, empty when valid
+func (s *serviceImpl) verifyBlock(b, prev *block, height int64) (string, error) {
+	if b.Height != height {
+		return fmt.Sprintf("height %d stored at height %d", b.Height, height), nil
+	}
+
+	if prev == nil && b.PrevHash != "" {
+		return "genesis block has a previous hash", nil
+	} else if prev != nil && b.PrevHash != prev.Hash {
+		return "previous hash does not match", nil
+	}
+
+	root, err := b.txRoot()
+	if err != nil {
+		return err.Error(), nil
+	}
+	if hex.EncodeToString(root) != b.MerkleRoot {
+		return "merkle root does not match transactions", nil
+	}
+
+	hash, err := b.computeHash()
+	if err != nil {
+		return err.Error(), nil
+	}
+	if hash != b.Hash {
+		return "block hash does not match header", nil
+	}
+
+	err = s.verifySigs(b)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+	"github.com/trevatk/tbd/lib/protocol/ledger"
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+func TestVerifyBlock(t *testing.T) {
+	lsm, err := keyvalue.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	s, err := NewService(edwards25519.NewBlakeSHA256Ed25519(), lsm)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	key, err := s.publicKey()
+	if err != nil {
+		t.Fatalf("failed to get public key: %v", err)
+	}
+	if err = s.apply(&command{Keys: map[string]string{s.sender: key}}, 0); err != nil {
+		t.Fatalf("failed to register key: %v", err)
+	}
+
+	sealed := func(t *testing.T, n int) *block {
+		t.Helper()
+		txs := make([]*tx, 0, n)
+		for range n {
+			tx := &tx{From: s.sender, To: decisionTo, Data: []byte(`{"subject":"user"}`), Timestamp: time.Now().UTC()}
+			if err := tx.signAndHash(s.signer); err != nil {
+				t.Fatalf("failed to sign tx: %v", err)
+			}
+			txs = append(txs, tx)
+		}
+		b := &block{Signer: s.sender, Height: defaultHeight, Txs: txs, Timestamp: time.Now().UTC()}
+		if err := b.seal(s.signer); err != nil {
+			t.Fatalf("failed to seal block: %v", err)
+		}
+		return b
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		b := sealed(t, 3)
+		reason, err := s.verifyBlock(b, nil, defaultHeight)
+		if err != nil || reason != "" {
+			t.Fatalf("unexpected reason %q error %v", reason, err)
+		}
+		if b.SignerKey != key {
+			t.Fatalf("unexpected signer key %s expected %s", b.SignerKey, key)
+		}
+
+		h := b.blockHeader()
+		if h.TxCount != 3 {
+			t.Fatalf("unexpected tx count %d expected 3", h.TxCount)
+		}
+		if hash, err := ledger.HeaderHash(h); err != nil || hash != b.Hash {
+			t.Fatalf("unexpected header hash %s error %v expected %s", hash, err, b.Hash)
+		}
+	})
+
+	t.Run("duplicate tx", func(t *testing.T) {
+		b := sealed(t, 3)
+		// repeating the odd last tx once left the root unchanged
+		b.Txs = append(b.Txs, b.Txs[2])
+		reason, err := s.verifyBlock(b, nil, defaultHeight)
+		if err != nil || !strings.Contains(reason, "duplicate tx") {
+			t.Fatalf("unexpected reason %q error %v", reason, err)
+		}
+		if err = b.seal(s.signer); err == nil {
+			t.Fatal("block with duplicate tx sealed")
+		}
+	})
+
+	t.Run("unregistered signer key", func(t *testing.T) {
+		b := sealed(t, 1)
+		other := wallet.NewSigner(s.suite, wallet.NewV1(s.suite))
+		if err := b.seal(other); err != nil {
+			t.Fatalf("failed to seal block: %v", err)
+		}
+
+		reason, err := s.verifyBlock(b, nil, defaultHeight)
+		if err != nil || !strings.Contains(reason, "signer key") {
+			t.Fatalf("unexpected reason %q error %v", reason, err)
+		}
+	})
+
+	t.Run("signer key swapped", func(t *testing.T) {
+		b := sealed(t, 1)
+		kb, _ := wallet.NewV1(s.suite).P.MarshalBinary()
+		b.SignerKey = hex.EncodeToString(kb)
+
+		reason, err := s.verifyBlock(b, nil, defaultHeight)
+		if err != nil || reason == "" {
+			t.Fatalf("unexpected reason %q error %v", reason, err)
+		}
+	})
+}
+
+func TestVerifyChainConcurrent(t *testing.T) {
+	lsm, err := keyvalue.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	s, err := NewService(edwards25519.NewBlakeSHA256Ed25519(), lsm)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	if _, err = s.produceBlock(1); err != nil {
+		t.Fatalf("failed to produce genesis: %v", err)
+	}
+
+	// blocks produced while the chain is walked are not inconsistencies
+	done := make(chan error, 1)
+	go func() {
+		for i := range 50 {
+			d := decision{Subject: "alice", Resource: "acme", Action: "view", Outcome: "OUTCOME_ALLOW", RequestID: fmt.Sprintf("request-%d", i)}
+			if _, err := s.recordDecision(d); err != nil {
+				done <- err
+				return
+			}
+			if _, err := s.produceBlock(1); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+			return
+		default:
+		}
+
+		_, inc, err := s.verifyChain()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inc != nil {
+			t.Fatalf("unexpected inconsistency at height %d: %s", inc.Height, inc.Reason)
+		}
+	}
+}
@@ -1,33 +1,294 @@
 package identities
 
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/trevatk/tbd/lib/protocol/interceptors"
+)
+
+const (
+	defaultMaxDepth = 32
+)
+
+var (
+	errUnknownRelation = errors.New("relation not defined for resource type")
+	errInvalidSubject  = errors.New("subject type not allowed for relation")
+	errDepthExceeded   = errors.New("check exceeded maximum depth")
+	errInvalidToken    = errors.New("invalid consistency token")
+	errTokenAhead      = errors.New("consistency token is ahead of the graph")
+)
+
+// accessControl relationship based access control over the
+// identity graph
+//
+// a subject holds a relation to an object through a direct
+// edge, through membership of a vertex with a direct edge or
+// through the rewrites of the relation in the active policy
 type accessControl struct {
-	g *graph
+	g        *graph
+	policy   atomic.Pointer[policy]
+	maxDepth int
+}
+
+// interface compliance
+var _ interceptors.Checker = (*accessControl)(nil)
+
+// AccessControlOption access control option pattern
+type AccessControlOption func(*accessControl)
+
+// WithMaxDepth maximum nesting of rewrites and memberships
+// followed by a check
+func WithMaxDepth(depth int) AccessControlOption {
+	return func(ac *accessControl) {
+		ac.maxDepth = depth
+	}
+}
+
+// WithPolicy initial policy, replaced by WatchPolicy
+func WithPolicy(p *policy) AccessControlOption {
+	return func(ac *accessControl) {
+		ac.policy.Store(p)
+	}
 }
 
 // NewAccessControl return new identities access control implementation
-func NewAccessControl(g *graph) *accessControl {
-	return &accessControl{
-		g: g,
+//
+// the built in policy is used without WithPolicy
+func NewAccessControl(g *graph, opts ...AccessControlOption) *accessControl {
+	ac := &accessControl{
+		g:        g,
+		maxDepth: defaultMaxDepth,
+	}
+	ac.policy.Store(defaultPolicy)
+
+	for _, opt := range opts {
+		opt(ac)
+	}
+
+	return ac
+}
+
+// Check subject holds relation to object at the latest revision
+func (ac *accessControl) Check(ctx context.Context, subject, relation, object string) (interceptors.Decision, error) {
+	d, _, err := ac.check(ctx, subject, relation, object, "")
+	return d, err
+}
+
+// check subject holds relation to object at a revision at least
+// as new as token, an empty token accepts any revision
+//
+// returns the token of the revision the check observed, the
+// whole check is evaluated against the policy active at its start
+func (ac *accessControl) check(ctx context.Context, subject, relation, object, token string) (interceptors.Decision, string, error) {
+	p := ac.policy.Load()
+	d := interceptors.Decision{PolicyVersion: p.version}
+
+	rev, err := ac.g.revision()
+	if err != nil {
+		return d, "", err
+	}
+
+	if token != "" {
+		min, err := decodeToken(token)
+		if err != nil {
+			return d, "", err
+		}
+		if min > rev {
+			return d, "", fmt.Errorf("%w: %d > %d", errTokenAhead, min, rev)
+		}
+	}
+
+	w := &walk{
+		ctx:      ctx,
+		g:        ac.g,
+		policy:   p,
+		subject:  subject,
+		maxDepth: ac.maxDepth,
+		path:     make(map[string]bool),
 	}
+	d.Allowed, err = w.check(relation, object, 0)
+	if err != nil {
+		return d, "", err
+	}
+
+	return d, encodeToken(rev), nil
 }
 
-// HasResourceAccess
-func (ac accessControl) HasResourceAccess(userHash, resourceHash string) bool {
-	v, err := ac.g.getVertex(resourceHash)
+// writeRelationship add edge object -relation-> subject
+// and return the token of a revision including it
+func (ac *accessControl) writeRelationship(object, relation, subject string, records ...record) (string, error) {
+	rw, err := ac.validateRelation(object, relation)
 	if err != nil {
-		return false
+		return "", err
 	}
 
+	s, err := ac.g.getVertex(subject)
+	if err != nil {
+		return "", fmt.Errorf("subject: %w", err)
+	}
+	if _, ok := rw.subjects[s.resource]; !ok {
+		return "", fmt.Errorf("%w: %s#%s %s", errInvalidSubject, object, relation, s.resource)
+	}
+
+	err = ac.g.addEdge(object, &edge{relationship: relation, to: subject}, records...)
+	if err != nil {
+		return "", err
+	}
+
+	return ac.token()
+}
+
+// deleteRelationship remove edge object -relation-> subject
+// and return the token of a revision without it
+func (ac *accessControl) deleteRelationship(object, relation, subject string, records ...record) (string, error) {
+	_, err := ac.validateRelation(object, relation)
+	if err != nil {
+		return "", err
+	}
+
+	err = ac.g.removeEdge(object, &edge{relationship: relation, to: subject}, records...)
+	if err != nil {
+		return "", err
+	}
+
+	return ac.token()
+}
+
+// validateRelation relation is written for the type of object,
+// permissions are computed and never written
+func (ac *accessControl) validateRelation(object, relation string) (*rewrite, error) {
+	v, err := ac.g.getVertex(object)
+	if err != nil {
+		return nil, err
+	}
+
+	rw := ac.policy.Load().relation(v.resource, relation)
+	if rw == nil || rw.subjects == nil {
+		return nil, fmt.Errorf("%w: %s#%s", errUnknownRelation, v.resource, relation)
+	}
+	return rw, nil
+}
+
+// token of the current revision, concurrent writes may make
+// it newer than the write it is returned for
+func (ac *accessControl) token() (string, error) {
+	rev, err := ac.g.revision()
+	if err != nil {
+		return "", err
+	}
+	return encodeToken(rev), nil
+}
+
+// walk state of one check
+type walk struct {
+	ctx      context.Context
+	g        *graph
+	policy   *policy
+	subject  string
+	maxDepth int
+
+	// path object and relation pairs being evaluated,
+	// reaching one again is a cycle
+	path map[string]bool
+}
+
+func (w *walk) check(relation, object string, depth int) (bool, error) {
+	if err := w.ctx.Err(); err != nil {
+		return false, err
+	}
+	if depth > w.maxDepth {
+		return false, fmt.Errorf("%w: %d", errDepthExceeded, w.maxDepth)
+	}
+
+	key := object + idSeparator + relation
+	if w.path[key] {
+		return false, nil
+	}
+	w.path[key] = true
+	defer delete(w.path, key)
+
+	v, err := w.g.getVertex(object)
+	if err != nil {
+		return false, err
+	}
+	rw := w.policy.relation(v.resource, relation)
+	if rw == nil {
+		return false, fmt.Errorf("%w: %s#%s", errUnknownRelation, v.resource, relation)
+	}
+
+	// edges exist for relations only, permissions have no subjects
 	for _, e := range v.edges {
-		if e.relationship == "DENY" {
-			return false
+		if e.relationship == relation && e.to == w.subject {
+			return true, nil
 		}
+	}
 
-		_, err := ac.g.getVertex(e.to)
-		if err != nil {
-			return false
+	for _, e := range v.edges {
+		if e.relationship != relation {
+			continue
+		}
+		ok, err := w.userset(rw, e.to, depth)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	for _, include := range rw.includes {
+		ok, err := w.check(include, object, depth+1)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	for _, p := range rw.parents {
+		for _, e := range v.edges {
+			if e.relationship != p.tupleset {
+				continue
+			}
+			ok, err := w.check(p.relation, e.to, depth+1)
+			if errors.Is(err, errUnknownRelation) {
+				// subject types without the relation never grant it
+				continue
+			} else if err != nil || ok {
+				return ok, err
+			}
 		}
 	}
 
-	return true
+	return false, nil
+}
+
+// userset subjects holding the userset relation of vertex id,
+// when the relation allows its type as a userset
+func (w *walk) userset(rw *rewrite, id string, depth int) (bool, error) {
+	v, err := w.g.getVertex(id)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	userset := rw.subjects[v.resource]
+	if userset == "" {
+		return false, nil
+	}
+	return w.check(userset, id, depth+1)
+}
+
+// encodeToken opaque consistency token of revision rev
+func encodeToken(rev uint64) string {
+	return base64.RawURLEncoding.EncodeToString(binary.BigEndian.AppendUint64(nil, rev))
+}
+
+func decodeToken(token string) (uint64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) != 8 {
+		return 0, fmt.Errorf("%w: %q", errInvalidToken, token)
+	}
+	return binary.BigEndian.Uint64(b), nil
 }
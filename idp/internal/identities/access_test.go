@@ -0,0 +1,210 @@
+package identities
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+// newTestAccessControl access control over a graph of realm acme
+//
+//	acme  owner alice, member bob, viewer members of eng
+//	eng   group of acme, member carol
+//	erin  user of acme
+func newTestAccessControl(t *testing.T, opts ...AccessControlOption) *accessControl {
+	t.Helper()
+
+	lsm, err := keyvalue.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { _ = lsm.Close() })
+
+	g := NewGraph(lsm)
+	for _, v := range []*vertex{
+		{id: "acme", resource: resourceRealm},
+		{id: "eng", resource: resourceGroup},
+		{id: "alice", resource: resourceUser},
+		{id: "bob", resource: resourceUser},
+		{id: "carol", resource: resourceUser},
+		{id: "erin", resource: resourceUser},
+	} {
+		if err = g.addVertex(v); err != nil {
+			t.Fatalf("failed to add vertex: %v", err)
+		}
+	}
+
+	ac := NewAccessControl(g, opts...)
+	for _, r := range [][3]string{
+		{"acme", "owner", "alice"},
+		{"acme", relationMember, "bob"},
+		{"acme", "viewer", "eng"},
+		{"eng", relationParent, "acme"},
+		{"eng", relationMember, "carol"},
+		{"erin", relationParent, "acme"},
+	} {
+		if _, err = ac.writeRelationship(r[0], r[1], r[2]); err != nil {
+			t.Fatalf("failed to write relationship %v: %v", r, err)
+		}
+	}
+
+	return ac
+}
+
+func TestCheck(t *testing.T) {
+	ac := newTestAccessControl(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		subject, relation, object string
+		expected                  bool
+	}{
+		{"alice", "owner", "acme", true},
+		// viewer includes member includes editor includes admin includes owner
+		{"alice", "viewer", "acme", true},
+		{"bob", "viewer", "acme", true},
+		{"bob", "admin", "acme", false},
+		// members of eng view acme without being members
+		{"carol", "viewer", "acme", true},
+		{"carol", relationMember, "acme", false},
+		// realm admins own its groups and view its users
+		{"alice", "owner", "eng", true},
+		{"bob", "owner", "eng", false},
+		{"alice", "view", "erin", true},
+		{"carol", "view", "erin", false},
+		// permissions are computed from relations
+		{"bob", "view", "acme", true},
+		{"bob", "manage", "acme", false},
+		{"carol", "view", "eng", true},
+		{"dave", "viewer", "acme", false},
+	}
+	for _, tt := range tests {
+		d, err := ac.Check(ctx, tt.subject, tt.relation, tt.object)
+		if err != nil {
+			t.Fatalf("unexpected error %v checking %s %s %s", err, tt.subject, tt.relation, tt.object)
+		}
+		if d.Allowed != tt.expected {
+			t.Fatalf("unexpected check %s %s %s %t expected %t", tt.subject, tt.relation, tt.object, d.Allowed, tt.expected)
+		}
+		if d.PolicyVersion != defaultPolicy.version {
+			t.Fatalf("unexpected policy version %s expected %s", d.PolicyVersion, defaultPolicy.version)
+		}
+	}
+
+	if _, err := ac.Check(ctx, "alice", "approver", "acme"); !errors.Is(err, errUnknownRelation) {
+		t.Fatalf("unexpected error %v expected %v", err, errUnknownRelation)
+	}
+	if _, err := ac.Check(ctx, "alice", "viewer", "globex"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrNotFound)
+	}
+	if _, err := ac.writeRelationship("erin", relationMember, "alice"); !errors.Is(err, errUnknownRelation) {
+		t.Fatalf("unexpected error %v expected %v", err, errUnknownRelation)
+	}
+	if _, err := ac.writeRelationship("acme", "view", "alice"); !errors.Is(err, errUnknownRelation) {
+		t.Fatalf("unexpected error %v expected %v", err, errUnknownRelation)
+	}
+	if _, err := ac.writeRelationship("acme", "owner", "eng"); !errors.Is(err, errInvalidSubject) {
+		t.Fatalf("unexpected error %v expected %v", err, errInvalidSubject)
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	ac := newTestAccessControl(t, WithMaxDepth(2))
+	ctx := context.Background()
+
+	// groups holding each other as members are a cycle
+	for _, id := range []string{"g1", "g2", "g3", "g4"} {
+		if err := ac.g.addVertex(&vertex{id: id, resource: resourceGroup}); err != nil {
+			t.Fatalf("failed to add vertex: %v", err)
+		}
+	}
+	write := func(rs ...[3]string) {
+		t.Helper()
+		for _, r := range rs {
+			if _, err := ac.writeRelationship(r[0], r[1], r[2]); err != nil {
+				t.Fatalf("failed to write relationship %v: %v", r, err)
+			}
+		}
+	}
+
+	write([3]string{"g1", relationMember, "g2"}, [3]string{"g2", relationMember, "g1"})
+	d, err := ac.Check(ctx, "bob", relationMember, "g1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Allowed {
+		t.Fatal("unexpected access through a cycle")
+	}
+
+	write(
+		[3]string{"g2", relationMember, "g3"},
+		[3]string{"g3", relationMember, "g4"},
+		[3]string{"g4", relationMember, "alice"},
+	)
+
+	d, err = ac.Check(ctx, "alice", relationMember, "g3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Allowed {
+		t.Fatal("expected access through nested group")
+	}
+
+	// alice is a member of g1 through three nested groups
+	if _, err = ac.Check(ctx, "alice", relationMember, "g1"); !errors.Is(err, errDepthExceeded) {
+		t.Fatalf("unexpected error %v expected %v", err, errDepthExceeded)
+	}
+}
+
+func TestConsistencyToken(t *testing.T) {
+	ac := newTestAccessControl(t)
+	ctx := context.Background()
+
+	token, err := ac.writeRelationship("acme", "admin", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, observed, err := ac.check(ctx, "bob", "admin", "acme", token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Allowed || observed != token {
+		t.Fatalf("unexpected check %t at %s expected true at %s", d.Allowed, observed, token)
+	}
+
+	token, err = ac.deleteRelationship("acme", "admin", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, _, err = ac.check(ctx, "bob", "admin", "acme", token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Allowed {
+		t.Fatal("unexpected access after delete")
+	}
+
+	if _, err = ac.deleteRelationship("acme", "admin", "bob"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrNotFound)
+	}
+
+	rev, err := decodeToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = ac.check(ctx, "bob", "viewer", "acme", encodeToken(rev+1)); !errors.Is(err, errTokenAhead) {
+		t.Fatalf("unexpected error %v expected %v", err, errTokenAhead)
+	}
+	if _, _, err = ac.check(ctx, "bob", "viewer", "acme", "not a token"); !errors.Is(err, errInvalidToken) {
+		t.Fatalf("unexpected error %v expected %v", err, errInvalidToken)
+	}
+
+	// the revision survives a new graph over the same store
+	reopened := NewAccessControl(NewGraph(ac.g.store))
+	if _, _, err = reopened.check(ctx, "bob", "viewer", "acme", token); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -27,10 +27,13 @@ func NewAuth(hmacSecret string) interceptors.Verifier {
 func (a *auth) Verify(token string) (map[string]interface{}, error) {
 	var cc interceptors.CustomClaims
 	if t, err := jwt.ParseWithClaims(token, &cc, func(t *jwt.Token) (interface{}, error) {
-		return a.hmacSecret, nil
-	}); err != nil {
+		return []byte(a.hmacSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()})); err != nil {
 		return nil, fmt.Errorf("failed to parse claims: %w", err)
 	} else if claims, ok := t.Claims.(*interceptors.CustomClaims); ok {
+		if claims.Issuer == "" {
+			return nil, errors.New("token has no issuer")
+		}
 		md := make(map[string]interface{})
 		md["user_id"] = claims.Issuer
 		return md, nil
@@ -42,6 +45,9 @@ func (a *auth) Verify(token string) (map[string]interface{}, error) {
 // SignWithClaims
 func (a *auth) SignWithClaims(claims map[string]interface{}) (string, error) {
 	md := &interceptors.CustomClaims{}
+	if userID, ok := claims["user_id"].(string); ok {
+		md.Issuer = userID
+	}
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, md)
 	s, err := t.SignedString([]byte(a.hmacSecret))
 	if err != nil {
@@ -0,0 +1,107 @@
+package identities
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	pbkv "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
+)
+
+const (
+	// EventPrefix key prefix of identity changes published to the keyvalue service
+	EventPrefix = "identities/"
+
+	realmEventPrefix = EventPrefix + "realms/"
+	userEventPrefix  = EventPrefix + "users/"
+	groupEventPrefix = EventPrefix + "groups/"
+
+	eventRealmCreated  = "realm.created"
+	eventRealmUpdated  = "realm.updated"
+	eventRealmDeleted  = "realm.deleted"
+	eventUserCreated   = "user.created"
+	eventUserUpdated   = "user.updated"
+	eventUserDeleted   = "user.deleted"
+	eventGroupCreated  = "group.created"
+	eventGroupUpdated  = "group.updated"
+	eventGroupDeleted  = "group.deleted"
+	eventMemberAdded   = "member.added"
+	eventMemberRemoved = "member.removed"
+	eventRoleAssigned  = "role.assigned"
+	eventRoleRevoked   = "role.revoked"
+)
+
+// outbox layout, events are written in the batch of the
+// mutation they describe and published once it is committed
+//
+//	outbox_<revision>_<n>   n-th event of a graph revision
+const (
+	outboxPrefix = "outbox_"
+	outboxEnd    = "outbox`"
+)
+
+// event realm, user or group change
+type event struct {
+	Type      string    `json:"type"`
+	Subject   string    `json:"subject"`
+	Realm     string    `json:"realm,omitempty"`
+	Group     string    `json:"group,omitempty"`
+	Role      string    `json:"role,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// record event staged in the outbox with its key
+type record struct {
+	Key   string `json:"key"`
+	Event event  `json:"event"`
+}
+
+type emitter interface {
+	emit(ctx context.Context, key string, e event) error
+}
+
+// kvEmitter publish events as records of the keyvalue service
+// so followers receive them through its change feed
+type kvEmitter struct {
+	client pbkv.KeyValueServiceClient
+}
+
+// NewEmitter return event emitter writing to the keyvalue service,
+// events are dropped when client is nil
+func NewEmitter(client pbkv.KeyValueServiceClient) emitter {
+	if client == nil {
+		return nopEmitter{}
+	}
+
+	return &kvEmitter{
+		client: client,
+	}
+}
+
+func (e *kvEmitter) emit(ctx context.Context, key string, evt event) error {
+	eb, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	_, err = e.client.Put(ctx, &pbkv.PutRequest{
+		Entry: &pbkv.Entry{
+			Key:    key,
+			Value:  eb,
+			Indice: map[string]string{"type": evt.Type},
+			Ttl:    -1,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put event: %w", err)
+	}
+
+	return nil
+}
+
+type nopEmitter struct{}
+
+func (nopEmitter) emit(context.Context, string, event) error {
+	return nil
+}
@@ -1,12 +1,49 @@
 package identities
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
 )
 
+// graph storage layout
+//
+// vertices are json records, adjacency is stored in the keys of
+// empty records so the edges of a vertex are one range scan
+//
+//	vertex_<id>                    vertex record indexed by resource and key
+//	edge_<from>\x1f<rel>\x1f<to>    outgoing edge
+//	redge_<to>\x1f<rel>\x1f<from>   incoming edge
+//	graph_revision                 revision of the last mutation
+//	outbox_<rev>_<n>               event of a mutation, see events.go
 const (
-	numZero = 0
+	vertexPrefix = "vertex_"
+	vertexEnd    = "vertex`"
+	edgePrefix   = "edge_"
+	redgePrefix  = "redge_"
+	revisionKey  = "graph_revision"
+
+	// idSeparator separates the parts of an edge key,
+	// never part of a vertex id or relationship
+	idSeparator = "\x1f"
+	// idEnd exclusive upper bound of keys with an id prefix
+	idEnd = "\x20"
+
+	// resourceIndex vertices by resource type
+	resourceIndex = "resource"
+	// keyIndex vertices by resource type and natural key
+	keyIndex = "key"
+)
+
+var (
+	errVertexExists = errors.New("vertex already exists")
+	errInvalidID    = errors.New("invalid vertex id or relationship")
 )
 
 type edge struct {
@@ -16,102 +53,565 @@ type edge struct {
 }
 
 type vertex struct {
-	id         string
-	resource   string
+	id       string
+	resource string
+	// key optional natural key, unique among vertices
+	// of the resource type
+	key        string
 	edges      []*edge
 	attributes map[string]interface{}
 }
 
+// vertexRecord stored vertex without its edges
+type vertexRecord struct {
+	ID         string                 `json:"id"`
+	Resource   string                 `json:"resource"`
+	Key        string                 `json:"key,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// graph identity graph persisted in the key value store
+//
+// vertices and their outgoing edges are loaded on first use
+// and cached, every mutation is written to the store before
+// the cache is updated
 type graph struct {
+	store    keyvalue.Store
 	vertices map[string]*vertex
 	mu       sync.RWMutex
+
+	// rev incremented by every mutation, zero until loaded
+	rev    uint64
+	loaded bool
 }
 
-// NewGraph return to graph
-func NewGraph() *graph {
+// NewGraph return graph stored in store
+func NewGraph(store keyvalue.Store) *graph {
 	return &graph{
-		vertices: make(map[string]*vertex, numZero),
+		store:    store,
+		vertices: make(map[string]*vertex),
 		mu:       sync.RWMutex{},
 	}
 }
 
-// func bootstrapGraph(g *graph) error {
-// 	permissionVertex := &vertex{
-// 		resource: "PERMISSION",
-// 		edges:    make([]*edge, 0),
-// 		attributes: map[string]interface{}{
-// 			"CREATE_REALM": true,
-// 		},
-// 	}
-// 	err := g.addVertex(permissionVertex)
-// 	if err != nil {
-// 	}
+// addVertex add vertex with its outgoing edges,
+// records are staged in the outbox of the same write
+func (g *graph) addVertex(v *vertex, records ...record) error {
+	return g.addVertices([]*vertex{v}, nil, records...)
+}
 
-// 	roleVertex := &vertex{}
-// 	err = g.addVertex(roleVertex)
-// 	if err != nil {
-// 	}
+// link edge from an existing vertex to an added one
+type link struct {
+	from string
+	edge *edge
+}
 
-// 	if err = g.addEdge(roleVertex.id, &edge{
-// 		relationship: "GRANT",
-// 		to:           permissionVertex.id,
-// 	}); err != nil {
+// addVertices add vertices with their outgoing edges and the
+// links of existing vertices to them in one write
+func (g *graph) addVertices(vertices []*vertex, links []link, records ...record) error {
+	for _, v := range vertices {
+		err := validateID(v.id)
+		if err != nil {
+			return err
+		}
+	}
 
-// 	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-// 	userVertex := &vertex{}
-// 	err = g.addVertex(userVertex)
-// 	if err != nil {
-// 	}
+	batch := keyvalue.NewBatch()
+	for _, v := range vertices {
+		_, err := g.loadVertex(v.id)
+		if err == nil {
+			return fmt.Errorf("%w: %s", errVertexExists, v.id)
+		} else if !errors.Is(err, ErrNotFound) {
+			return err
+		}
 
-// 	if err = g.addEdge(userVertex.id, &edge{
-// 		relationship: "ROLE",
-// 		to:           roleVertex.id,
-// 	}); err != nil {
-// 	}
+		err = g.checkKey(v.resource, v.key, v.id)
+		if err != nil {
+			return err
+		}
+
+		err = putVertex(batch, v)
+		if err != nil {
+			return err
+		}
+		for _, e := range v.edges {
+			putEdge(batch, v.id, e)
+		}
+	}
 
-// 	return nil
-// }
+	linked := make(map[string]*vertex, len(links))
+	for _, l := range links {
+		err := validateID(l.edge.relationship)
+		if err != nil {
+			return err
+		}
+
+		from, ok := linked[l.from]
+		if !ok {
+			v, err := g.loadVertex(l.from)
+			if err != nil {
+				return fmt.Errorf("FROM vertex: %w", err)
+			}
+			nv := *v
+			nv.edges = slices.Clone(v.edges)
+			from = &nv
+			linked[l.from] = from
+		}
+		from.edges = append(from.edges, l.edge)
+		putEdge(batch, l.from, l.edge)
+	}
+
+	err := g.write(batch, records)
+	if err != nil {
+		return fmt.Errorf("failed to write vertex: %w", err)
+	}
+
+	for _, v := range vertices {
+		g.vertices[v.id] = v
+	}
+	for id, v := range linked {
+		g.vertices[id] = v
+	}
+	return nil
+}
+
+// updateVertex replace the key and attributes of vertex id
+func (g *graph) updateVertex(id, key string, attributes map[string]interface{}, records ...record) (*vertex, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	v, err := g.loadVertex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != v.key {
+		err = g.checkKey(v.resource, key, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nv := *v
+	nv.key = key
+	nv.attributes = attributes
+
+	batch := keyvalue.NewBatch()
+	err = putVertex(batch, &nv)
+	if err != nil {
+		return nil, err
+	}
+	err = g.write(batch, records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write vertex: %w", err)
+	}
+
+	g.vertices[id] = &nv
+	return &nv, nil
+}
 
-func (g *graph) addVertex(vertex *vertex) error {
+// removeVertex delete vertex id and every edge from or to it,
+// vertices with a cascade edge to a removed vertex are removed
+// with it in the same write
+//
+// recordsOf returns the events of the removed vertices written
+// with them, the removed vertices are returned
+func (g *graph) removeVertex(id, cascade string, recordsOf func([]*vertex) []record) ([]*vertex, error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if _, exists := g.vertices[vertex.id]; exists {
-		// found existing vertex
-		return errors.New("existing vertex found")
+	v, err := g.loadVertex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []*vertex{v}
+	seen := map[string]bool{id: true}
+	// vertices losing outgoing edges are dropped from the cache
+	touched := make(map[string]bool)
+
+	batch := keyvalue.NewBatch()
+	deleted := make(map[string]bool)
+	deleteKey := func(key string) {
+		if !deleted[key] {
+			deleted[key] = true
+			batch.Delete(key)
+		}
+	}
+	deleteEdge := func(from, rel, to string) {
+		deleteKey(edgePrefix + from + idSeparator + rel + idSeparator + to)
+		deleteKey(redgePrefix + to + idSeparator + rel + idSeparator + from)
+	}
+
+	for i := 0; i < len(removed); i++ {
+		r := removed[i]
+		deleteKey(vertexPrefix + r.id)
+		for _, e := range r.edges {
+			deleteEdge(r.id, e.relationship, e.to)
+		}
+
+		var loadErr error
+		err = g.incoming(r.id, "", "", func(rel, from string) bool {
+			deleteEdge(from, rel, r.id)
+			touched[from] = true
+
+			if rel != cascade || seen[from] {
+				return true
+			}
+			seen[from] = true
+
+			var child *vertex
+			child, loadErr = g.loadVertex(from)
+			if loadErr != nil {
+				return false
+			}
+			removed = append(removed, child)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		} else if loadErr != nil {
+			return nil, loadErr
+		}
+	}
+
+	var records []record
+	if recordsOf != nil {
+		records = recordsOf(removed)
+	}
+	err = g.write(batch, records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete vertex: %w", err)
+	}
+
+	for id := range touched {
+		delete(g.vertices, id)
+	}
+	for _, r := range removed {
+		delete(g.vertices, r.id)
+	}
+
+	return removed, nil
+}
+
+// listVertices call fn in id order with the ids of vertices of
+// resource type after id after until fn returns false
+func (g *graph) listVertices(resource, after string, fn func(id string) bool) error {
+	start := vertexPrefix
+	if after != "" {
+		start = vertexPrefix + after + "\x00"
+	}
+
+	it := g.store.GetByIndexRange(resourceIndex, resource, start, vertexEnd)
+	defer it.Close()
+	for it.HasNext() {
+		k, _, err := it.Next()
+		if err != nil {
+			return fmt.Errorf("failed to read vertices: %w", err)
+		}
+		if !fn(strings.TrimPrefix(k, vertexPrefix)) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// incoming call fn in order with the edges pointing at vertex to,
+// limited to relationship when set and starting after the vertex
+// after, until fn returns false
+func (g *graph) incoming(to, relationship, after string, fn func(relationship, from string) bool) error {
+	prefix := redgePrefix + to + idSeparator
+	start, end := prefix, redgePrefix+to+idEnd
+	if relationship != "" {
+		start = prefix + relationship + idSeparator
+		end = prefix + relationship + idEnd
+		if after != "" {
+			start += after + "\x00"
+		}
+	}
+
+	it := g.store.Range(start, end)
+	defer it.Close()
+	for it.HasNext() {
+		k, _, err := it.Next()
+		if err != nil {
+			return fmt.Errorf("failed to read incoming edges of %s: %w", to, err)
+		}
+
+		rel, from, ok := strings.Cut(strings.TrimPrefix(k, prefix), idSeparator)
+		if !ok {
+			return fmt.Errorf("malformed edge key %q", k)
+		}
+		if !fn(rel, from) {
+			return nil
+		}
 	}
 
-	g.vertices[vertex.id] = vertex
 	return nil
 }
 
 func (g *graph) getVertex(id string) (*vertex, error) {
 	g.mu.RLock()
-	defer g.mu.RUnlock()
+	v, ok := g.vertices[id]
+	g.mu.RUnlock()
+	if ok {
+		return v, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	v, exists := g.vertices[id]
-	if !exists {
-		return nil, errors.New("vertex not found")
+	return g.loadVertex(id)
+}
+
+func (g *graph) addEdge(from string, edge *edge, records ...record) error {
+	err := validateID(edge.relationship)
+	if err != nil {
+		return err
 	}
 
-	return v, nil
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	v, err := g.loadVertex(from)
+	if err != nil {
+		return fmt.Errorf("FROM vertex: %w", err)
+	}
+
+	_, err = g.loadVertex(edge.to)
+	if err != nil {
+		return fmt.Errorf("TO vertex: %w", err)
+	}
+
+	for _, e := range v.edges {
+		if e.relationship == edge.relationship && e.to == edge.to {
+			return nil
+		}
+	}
+
+	batch := keyvalue.NewBatch()
+	putEdge(batch, from, edge)
+	err = g.write(batch, records)
+	if err != nil {
+		return fmt.Errorf("failed to write edge: %w", err)
+	}
+
+	// cached vertices are never modified, readers
+	// holding the previous vertex keep a consistent view
+	nv := *v
+	nv.edges = append(slices.Clone(v.edges), edge)
+	g.vertices[from] = &nv
+
+	return nil
 }
 
-func (g *graph) addEdge(from string, edge *edge) error {
+func (g *graph) removeEdge(from string, edge *edge, records ...record) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	v, exists := g.vertices[from]
-	if !exists {
-		return errors.New("FROM vertex not found")
+	v, err := g.loadVertex(from)
+	if err != nil {
+		return fmt.Errorf("FROM vertex: %w", err)
+	}
+
+	i := -1
+	for j, e := range v.edges {
+		if e.relationship == edge.relationship && e.to == edge.to {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return fmt.Errorf("%w: edge %s %s %s", ErrNotFound, from, edge.relationship, edge.to)
+	}
+
+	batch := keyvalue.NewBatch()
+	batch.Delete(edgePrefix + from + idSeparator + edge.relationship + idSeparator + edge.to)
+	batch.Delete(redgePrefix + edge.to + idSeparator + edge.relationship + idSeparator + from)
+	err = g.write(batch, records)
+	if err != nil {
+		return fmt.Errorf("failed to delete edge: %w", err)
+	}
+
+	nv := *v
+	nv.edges = slices.Delete(slices.Clone(v.edges), i, i+1)
+	g.vertices[from] = &nv
+
+	return nil
+}
+
+// revision of the last mutation written to the store
+func (g *graph) revision() (uint64, error) {
+	g.mu.RLock()
+	rev, loaded := g.rev, g.loaded
+	g.mu.RUnlock()
+	if loaded {
+		return rev, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	err := g.loadRevision()
+	if err != nil {
+		return 0, err
+	}
+	return g.rev, nil
+}
+
+// write batch as the next revision with records staged in
+// the outbox, must hold mu
+func (g *graph) write(batch *keyvalue.Batch, records []record) error {
+	err := g.loadRevision()
+	if err != nil {
+		return err
+	}
+
+	rev := g.rev + 1
+	batch.Put(revisionKey, binary.BigEndian.AppendUint64(nil, rev), nil, -1)
+	for i, r := range records {
+		rb, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("json.Marshal: %w", err)
+		}
+		batch.Put(fmt.Sprintf("%s%016x_%04x", outboxPrefix, rev, i), rb, nil, -1)
+	}
+	err = g.store.Write(batch)
+	if err != nil {
+		return err
+	}
+
+	g.rev = rev
+	return nil
+}
+
+// loadRevision read the revision once, must hold mu
+func (g *graph) loadRevision() error {
+	if g.loaded {
+		return nil
+	}
+
+	b, err := g.store.Get(revisionKey)
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		g.loaded = true
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get graph revision: %w", err)
 	}
+	if len(b) != 8 {
+		return fmt.Errorf("malformed graph revision %x", b)
+	}
+
+	g.rev = binary.BigEndian.Uint64(b)
+	g.loaded = true
+	return nil
+}
+
+// loadVertex cached vertex or read it and its outgoing
+// edges from the store, must hold mu
+func (g *graph) loadVertex(id string) (*vertex, error) {
+	if v, ok := g.vertices[id]; ok {
+		return v, nil
+	}
+
+	vb, err := g.store.Get(vertexPrefix + id)
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return nil, fmt.Errorf("%w: vertex %s", ErrNotFound, id)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get vertex %s: %w", id, err)
+	}
+
+	var r vertexRecord
+	err = json.Unmarshal(vb, &r)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	v := &vertex{
+		id:         r.ID,
+		resource:   r.Resource,
+		key:        r.Key,
+		edges:      make([]*edge, 0),
+		attributes: r.Attributes,
+	}
+	if v.attributes == nil {
+		v.attributes = make(map[string]interface{})
+	}
+
+	prefix := edgePrefix + id + idSeparator
+	it := g.store.Range(prefix, edgePrefix+id+idEnd)
+	defer it.Close()
+	for it.HasNext() {
+		k, _, err := it.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read edges of %s: %w", id, err)
+		}
 
-	_, exists = g.vertices[edge.to]
-	if !exists {
-		return errors.New("TO vertex not found")
+		rel, to, ok := strings.Cut(strings.TrimPrefix(k, prefix), idSeparator)
+		if !ok {
+			return nil, fmt.Errorf("malformed edge key %q", k)
+		}
+		v.edges = append(v.edges, &edge{relationship: rel, to: to})
 	}
 
-	v.edges = append(v.edges, edge)
+	g.vertices[id] = v
+	return v, nil
+}
+
+// checkKey no other vertex of resource has key, must hold mu
+func (g *graph) checkKey(resource, key, id string) error {
+	if key == "" {
+		return nil
+	}
+
+	it := g.store.GetByIndex(keyIndex, resource+idSeparator+key)
+	defer it.Close()
+	for it.HasNext() {
+		k, _, err := it.Next()
+		if err != nil {
+			return fmt.Errorf("failed to read vertex key: %w", err)
+		}
+		if k != vertexPrefix+id {
+			return fmt.Errorf("%w: %s key %s", errVertexExists, resource, key)
+		}
+	}
+	return nil
+}
+
+// putVertex add the record of v to batch
+func putVertex(batch *keyvalue.Batch, v *vertex) error {
+	vb, err := json.Marshal(vertexRecord{
+		ID:         v.id,
+		Resource:   v.resource,
+		Key:        v.key,
+		Attributes: v.attributes,
+	})
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	indice := map[string]string{resourceIndex: v.resource}
+	if v.key != "" {
+		indice[keyIndex] = v.resource + idSeparator + v.key
+	}
+	batch.Put(vertexPrefix+v.id, vb, indice, -1)
+	return nil
+}
+
+// putEdge add the outgoing and incoming keys of e to batch
+func putEdge(batch *keyvalue.Batch, from string, e *edge) {
+	batch.Put(edgePrefix+from+idSeparator+e.relationship+idSeparator+e.to, []byte{}, nil, -1)
+	batch.Put(redgePrefix+e.to+idSeparator+e.relationship+idSeparator+from, []byte{}, nil, -1)
+}
+
+// validateID ids and relationships are part of edge keys
+func validateID(id string) error {
+	if id == "" || strings.ContainsAny(id, idSeparator+"\x00") {
+		return fmt.Errorf("%w: %q", errInvalidID, id)
+	}
 	return nil
 }
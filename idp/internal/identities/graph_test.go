@@ -0,0 +1,88 @@
+package identities
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+func TestGraphPersistence(t *testing.T) {
+	dir := t.TempDir()
+
+	lsm, err := keyvalue.New(dir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+
+	g := NewGraph(lsm)
+	for _, v := range []*vertex{
+		{id: "tbd", resource: "REALM", attributes: map[string]interface{}{"display_name": "tbd"}},
+		{id: "alice", resource: "USER"},
+		{id: "bob", resource: "USER"},
+	} {
+		if err = g.addVertex(v); err != nil {
+			t.Fatalf("failed to add vertex: %v", err)
+		}
+	}
+	for _, to := range []string{"alice", "bob"} {
+		if err = g.addEdge("tbd", &edge{relationship: relationMember, to: to}); err != nil {
+			t.Fatalf("failed to add edge: %v", err)
+		}
+	}
+
+	if err = g.addVertex(&vertex{id: "alice", resource: "USER"}); !errors.Is(err, errVertexExists) {
+		t.Fatalf("unexpected error %v expected %v", err, errVertexExists)
+	}
+	if err = g.addEdge("tbd", &edge{relationship: relationMember, to: "carol"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrNotFound)
+	}
+	if err = g.addVertex(&vertex{id: "a\x1fb"}); !errors.Is(err, errInvalidID) {
+		t.Fatalf("unexpected error %v expected %v", err, errInvalidID)
+	}
+
+	if err = lsm.Close(); err != nil {
+		t.Fatalf("failed to close store: %v", err)
+	}
+
+	// a new graph over the reopened store loads what was written
+	lsm, err = keyvalue.New(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	g = NewGraph(lsm)
+	if len(g.vertices) != 0 {
+		t.Fatal("graph loaded vertices before use")
+	}
+
+	v, err := g.getVertex("tbd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.resource != "REALM" || v.attributes["display_name"] != "tbd" {
+		t.Fatalf("unexpected vertex %+v", v)
+	}
+	if len(v.edges) != 2 || v.edges[0].to != "alice" || v.edges[1].to != "bob" {
+		t.Fatalf("unexpected edges %+v", v.edges)
+	}
+
+	if _, err = g.getVertex("carol"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrNotFound)
+	}
+
+	// adjacency of one vertex never includes another vertex
+	// sharing its id as a prefix
+	if err = g.addVertex(&vertex{id: "tb", resource: "REALM"}); err != nil {
+		t.Fatalf("failed to add vertex: %v", err)
+	}
+	g = NewGraph(lsm)
+	v, err = g.getVertex("tb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v.edges) != 0 {
+		t.Fatalf("unexpected edges %+v", v.edges)
+	}
+}
@@ -2,6 +2,7 @@ package identities
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"buf.build/go/protovalidate"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/trevatk/tbd/lib/protocol"
 	pb "github.com/trevatk/tbd/lib/protocol/identities/v1"
+	"github.com/trevatk/tbd/lib/protocol/interceptors"
 )
 
 type grpcTransport struct {
@@ -33,16 +35,87 @@ func (g *grpcTransport) CreateRealm(ctx context.Context, in *pb.CreateRealmReque
 		return nil, protocol.ErrInvalidArgument()
 	}
 
-	realm, err := g.svc.createRealm(ctx, realmCreate{
-		name: protocol.NormalizeText(in.Create.DisplayName),
-		who:  whoami{},
+	realm, owner, err := g.svc.createRealm(ctx, realmCreate{
+		name:  protocol.NormalizeText(in.GetCreate().GetDisplayName()),
+		owner: in.GetCreate().GetOwnerEmail(),
 	})
 	if err != nil {
-		g.log.ErrorContext(ctx, "failed to create realm", "error", err)
-		return nil, protocol.ErrInternal()
+		return nil, g.statusError(ctx, "failed to create realm", err)
 	}
 
-	return newCreateRealmResponse(realm), nil
+	return newCreateRealmResponse(realm, owner), nil
+}
+
+// GetRealm
+func (g *grpcTransport) GetRealm(ctx context.Context, in *pb.GetRealmRequest) (*pb.GetRealmResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	realm, err := g.svc.getRealm(in.Hash)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to get realm", err)
+	}
+
+	return &pb.GetRealmResponse{
+		Realm: transformRealm(realm),
+	}, nil
+}
+
+// ListRealms
+func (g *grpcTransport) ListRealms(ctx context.Context, in *pb.ListRealmsRequest) (*pb.ListRealmsResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	caller, _ := ctx.Value(interceptors.User).(string)
+	realms, cursor, err := g.svc.listRealms(ctx, caller, in.Cursor, int(in.Limit))
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to list realms", err)
+	}
+
+	out := make([]*pb.Realm, 0, len(realms))
+	for _, r := range realms {
+		out = append(out, transformRealm(r))
+	}
+
+	return &pb.ListRealmsResponse{
+		Realms:     out,
+		NextCursor: cursor,
+	}, nil
+}
+
+// UpdateRealm
+func (g *grpcTransport) UpdateRealm(ctx context.Context, in *pb.UpdateRealmRequest) (*pb.UpdateRealmResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	realm, err := g.svc.updateRealm(ctx, realmUpdate{
+		hash: in.Hash,
+		name: protocol.NormalizeText(in.DisplayName),
+	})
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to update realm", err)
+	}
+
+	return &pb.UpdateRealmResponse{
+		Realm: transformRealm(realm),
+	}, nil
+}
+
+// DeleteRealm
+func (g *grpcTransport) DeleteRealm(ctx context.Context, in *pb.DeleteRealmRequest) (*pb.DeleteRealmResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	err := g.svc.deleteRealm(ctx, in.Hash)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to delete realm", err)
+	}
+
+	return &pb.DeleteRealmResponse{}, nil
 }
 
 // CreateUser
@@ -56,24 +129,373 @@ func (g *grpcTransport) CreateUser(ctx context.Context, in *pb.CreateUserRequest
 		email: in.Email,
 	})
 	if err != nil {
-		g.log.ErrorContext(ctx, "failed to create user", "error", err)
-		return nil, protocol.ErrInternal()
+		return nil, g.statusError(ctx, "failed to create user", err)
 	}
 
 	return newCreateUserResponse(user), nil
 }
 
-func newCreateRealmResponse(r realm) *pb.CreateRealmResponse {
+// GetUser
+func (g *grpcTransport) GetUser(ctx context.Context, in *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	user, err := g.svc.getUser(in.Hash)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to get user", err)
+	}
+
+	return &pb.GetUserResponse{
+		User: transformUser(user),
+	}, nil
+}
+
+// ListUsers
+func (g *grpcTransport) ListUsers(ctx context.Context, in *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	users, cursor, err := g.svc.listUsers(in.RealmHash, in.Cursor, int(in.Limit))
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to list users", err)
+	}
+
+	out := make([]*pb.User, 0, len(users))
+	for _, u := range users {
+		out = append(out, transformUser(u))
+	}
+
+	return &pb.ListUsersResponse{
+		Users:      out,
+		NextCursor: cursor,
+	}, nil
+}
+
+// UpdateUser
+func (g *grpcTransport) UpdateUser(ctx context.Context, in *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	user, err := g.svc.updateUser(ctx, userUpdate{
+		hash:  in.Hash,
+		email: in.Email,
+	})
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to update user", err)
+	}
+
+	return &pb.UpdateUserResponse{
+		User: transformUser(user),
+	}, nil
+}
+
+// DeleteUser
+func (g *grpcTransport) DeleteUser(ctx context.Context, in *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	err := g.svc.deleteUser(ctx, in.Hash)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to delete user", err)
+	}
+
+	return &pb.DeleteUserResponse{}, nil
+}
+
+// CreateGroup
+func (g *grpcTransport) CreateGroup(ctx context.Context, in *pb.CreateGroupRequest) (*pb.CreateGroupResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	group, err := g.svc.createGroup(ctx, groupCreate{
+		realm: in.RealmHash,
+		name:  protocol.NormalizeText(in.Name),
+	})
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to create group", err)
+	}
+
+	return &pb.CreateGroupResponse{
+		Group: transformGroup(group),
+	}, nil
+}
+
+// GetGroup
+func (g *grpcTransport) GetGroup(ctx context.Context, in *pb.GetGroupRequest) (*pb.GetGroupResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	group, err := g.svc.getGroup(in.Hash)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to get group", err)
+	}
+
+	return &pb.GetGroupResponse{
+		Group: transformGroup(group),
+	}, nil
+}
+
+// ListGroups
+func (g *grpcTransport) ListGroups(ctx context.Context, in *pb.ListGroupsRequest) (*pb.ListGroupsResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	groups, cursor, err := g.svc.listGroups(in.RealmHash, in.Cursor, int(in.Limit))
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to list groups", err)
+	}
+
+	out := make([]*pb.Group, 0, len(groups))
+	for _, gr := range groups {
+		out = append(out, transformGroup(gr))
+	}
+
+	return &pb.ListGroupsResponse{
+		Groups:     out,
+		NextCursor: cursor,
+	}, nil
+}
+
+// UpdateGroup
+func (g *grpcTransport) UpdateGroup(ctx context.Context, in *pb.UpdateGroupRequest) (*pb.UpdateGroupResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	group, err := g.svc.updateGroup(ctx, groupUpdate{
+		hash: in.Hash,
+		name: protocol.NormalizeText(in.Name),
+	})
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to update group", err)
+	}
+
+	return &pb.UpdateGroupResponse{
+		Group: transformGroup(group),
+	}, nil
+}
+
+// DeleteGroup
+func (g *grpcTransport) DeleteGroup(ctx context.Context, in *pb.DeleteGroupRequest) (*pb.DeleteGroupResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	err := g.svc.deleteGroup(ctx, in.Hash)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to delete group", err)
+	}
+
+	return &pb.DeleteGroupResponse{}, nil
+}
+
+// AddMember
+func (g *grpcTransport) AddMember(ctx context.Context, in *pb.AddMemberRequest) (*pb.AddMemberResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	token, err := g.svc.addMember(ctx, in.GroupHash, in.MemberHash)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to add member", err)
+	}
+
+	return &pb.AddMemberResponse{
+		ConsistencyToken: token,
+	}, nil
+}
+
+// RemoveMember
+func (g *grpcTransport) RemoveMember(ctx context.Context, in *pb.RemoveMemberRequest) (*pb.RemoveMemberResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	token, err := g.svc.removeMember(ctx, in.GroupHash, in.MemberHash)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to remove member", err)
+	}
+
+	return &pb.RemoveMemberResponse{
+		ConsistencyToken: token,
+	}, nil
+}
+
+// AssignRole
+func (g *grpcTransport) AssignRole(ctx context.Context, in *pb.AssignRoleRequest) (*pb.AssignRoleResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	token, err := g.svc.assignRole(ctx, in.RealmHash, newRoleAssignment(in.Assignment))
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to assign role", err)
+	}
+
+	return &pb.AssignRoleResponse{
+		ConsistencyToken: token,
+	}, nil
+}
+
+// RevokeRole
+func (g *grpcTransport) RevokeRole(ctx context.Context, in *pb.RevokeRoleRequest) (*pb.RevokeRoleResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	token, err := g.svc.revokeRole(ctx, in.RealmHash, newRoleAssignment(in.Assignment))
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to revoke role", err)
+	}
+
+	return &pb.RevokeRoleResponse{
+		ConsistencyToken: token,
+	}, nil
+}
+
+// ListRoles
+func (g *grpcTransport) ListRoles(ctx context.Context, in *pb.ListRolesRequest) (*pb.ListRolesResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	roles, err := g.svc.listRoles(in.RealmHash)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to list roles", err)
+	}
+
+	out := make([]*pb.RoleAssignment, 0, len(roles))
+	for _, r := range roles {
+		out = append(out, &pb.RoleAssignment{
+			Role:        r.role,
+			SubjectHash: r.subject,
+		})
+	}
+
+	return &pb.ListRolesResponse{
+		Assignments: out,
+	}, nil
+}
+
+// Check
+func (g *grpcTransport) Check(ctx context.Context, in *pb.CheckRequest) (*pb.CheckResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	d, token, err := g.svc.ac.check(ctx, in.Subject, in.Relation, in.Object, in.ConsistencyToken)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to check relation", err)
+	}
+
+	return &pb.CheckResponse{
+		Allowed:          d.Allowed,
+		ConsistencyToken: token,
+		PolicyVersion:    d.PolicyVersion,
+	}, nil
+}
+
+// WriteRelationship
+func (g *grpcTransport) WriteRelationship(ctx context.Context, in *pb.WriteRelationshipRequest) (*pb.WriteRelationshipResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	r := in.Relationship
+	token, err := g.svc.writeRelationship(r.Object, r.Relation, r.Subject)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to write relationship", err)
+	}
+
+	return &pb.WriteRelationshipResponse{
+		ConsistencyToken: token,
+	}, nil
+}
+
+// DeleteRelationship
+func (g *grpcTransport) DeleteRelationship(ctx context.Context, in *pb.DeleteRelationshipRequest) (*pb.DeleteRelationshipResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	r := in.Relationship
+	token, err := g.svc.deleteRelationship(r.Object, r.Relation, r.Subject)
+	if err != nil {
+		return nil, g.statusError(ctx, "failed to delete relationship", err)
+	}
+
+	return &pb.DeleteRelationshipResponse{
+		ConsistencyToken: token,
+	}, nil
+}
+
+// statusError status of a service error, unexpected errors are logged
+func (g *grpcTransport) statusError(ctx context.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return protocol.ErrNotFound()
+	case errors.Is(err, errVertexExists):
+		return protocol.ErrAlreadyExists()
+	case errors.Is(err, errUnknownRelation), errors.Is(err, errInvalidSubject),
+		errors.Is(err, errInvalidToken), errors.Is(err, errInvalidID),
+		errors.Is(err, errInvalidCursor), errors.Is(err, errOtherRealm),
+		errors.Is(err, errManaged):
+		return protocol.ErrInvalidArgument()
+	case errors.Is(err, errTokenAhead), errors.Is(err, errDepthExceeded):
+		return protocol.ErrFailedPrecondition()
+	}
+
+	g.log.ErrorContext(ctx, msg, "error", err)
+	return protocol.ErrInternal()
+}
+
+func newCreateRealmResponse(r realm, owner user) *pb.CreateRealmResponse {
 	return &pb.CreateRealmResponse{
-		Realm: &pb.Realm{
-			Hash:        r.hash,
-			DisplayName: r.name,
-		},
+		Realm: transformRealm(r),
+		Owner: transformUser(owner),
 	}
 }
 
 func newCreateUserResponse(u user) *pb.CreateUserResponse {
 	return &pb.CreateUserResponse{
 		Hash: u.hash,
+		User: transformUser(u),
+	}
+}
+
+func newRoleAssignment(in *pb.RoleAssignment) roleAssignment {
+	return roleAssignment{
+		role:    in.Role,
+		subject: in.SubjectHash,
+	}
+}
+
+func transformRealm(r realm) *pb.Realm {
+	return &pb.Realm{
+		Hash:        r.hash,
+		DisplayName: r.name,
+	}
+}
+
+func transformUser(u user) *pb.User {
+	return &pb.User{
+		Hash:      u.hash,
+		RealmHash: u.realm,
+		Email:     u.email,
+	}
+}
+
+func transformGroup(gr group) *pb.Group {
+	return &pb.Group{
+		Hash:      gr.hash,
+		RealmHash: gr.realm,
+		Name:      gr.name,
 	}
 }
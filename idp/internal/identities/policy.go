@@ -0,0 +1,326 @@
+package identities
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// resource types and relations written by the service
+const (
+	resourceRealm = "REALM"
+	resourceGroup = "GROUP"
+	resourceUser  = "USER"
+
+	relationParent = "parent"
+	relationMember = "member"
+	relationOwner  = "owner"
+
+	// permissions checked by the resolver
+	permissionManage = "manage"
+	permissionView   = "view"
+)
+
+const (
+	// usersetSeparator subject type and the relation
+	// its members hold, GROUP#member
+	usersetSeparator = "#"
+	// arrowSeparator tupleset relation and the relation
+	// checked on the objects it points to, parent->admin
+	arrowSeparator = "->"
+)
+
+var (
+	errInvalidPolicy = errors.New("invalid policy")
+
+	nameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+	// requiredRelations relations the service writes,
+	// resource relation and subject type
+	requiredRelations = [][3]string{
+		{resourceRealm, relationMember, resourceUser},
+		{resourceRealm, relationOwner, resourceUser},
+		{resourceUser, relationParent, resourceRealm},
+	}
+
+	// requiredPermissions permissions the resolver checks,
+	// resource and permission
+	requiredPermissions = [][2]string{
+		{resourceRealm, permissionManage},
+		{resourceRealm, permissionView},
+		{resourceUser, permissionManage},
+		{resourceUser, permissionView},
+		{resourceGroup, permissionManage},
+		{resourceGroup, permissionView},
+	}
+
+	//go:embed policy.json
+	defaultPolicyFile []byte
+	defaultPolicy     = mustParsePolicy(defaultPolicyFile)
+)
+
+// policyFile declarative access policy
+//
+// relations are written as edges object -relation-> subject,
+// permissions are computed from relations and never written
+type policyFile struct {
+	Version   string                  `json:"version"`
+	Resources map[string]resourceFile `json:"resources"`
+}
+
+type resourceFile struct {
+	Relations map[string]relationFile `json:"relations"`
+	// Permissions terms whose subjects hold the permission
+	Permissions map[string][]string `json:"permissions"`
+}
+
+type relationFile struct {
+	// Subjects resource types of direct subjects, TYPE#relation
+	// when the members of the subject hold the relation
+	Subjects []string `json:"subjects"`
+	// Includes terms whose subjects hold the relation too,
+	// a relation of the object or tupleset->relation
+	Includes []string `json:"includes,omitempty"`
+}
+
+// policy validated access policy
+type policy struct {
+	version   string
+	digest    [sha256.Size]byte
+	resources map[string]namespace
+}
+
+// namespace relations and permissions of one resource type
+type namespace map[string]*rewrite
+
+// rewrite subjects of a relation or permission
+type rewrite struct {
+	// subjects userset relation by direct subject type,
+	// empty for the subject itself, nil for permissions
+	subjects map[string]string
+	// includes relations of the same object whose subjects
+	// hold this relation too, viewer includes editor
+	includes []string
+	// parents relations of the objects reached over an edge
+	// whose subjects hold this relation too
+	parents []tupleToUserset
+}
+
+// tupleToUserset follow tupleset edges and check relation
+// on the object they point to
+type tupleToUserset struct {
+	tupleset string
+	relation string
+}
+
+// LoadPolicy read and validate the policy at path
+func LoadPolicy(path string) (*policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	return parsePolicy(b)
+}
+
+func mustParsePolicy(b []byte) *policy {
+	p, err := parsePolicy(b)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// parsePolicy decode and validate a policy file
+func parsePolicy(b []byte) (*policy, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+
+	var f policyFile
+	err := dec.Decode(&f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidPolicy, err)
+	}
+	if f.Version == "" {
+		return nil, fmt.Errorf("%w: missing version", errInvalidPolicy)
+	}
+
+	p := &policy{
+		version:   f.Version,
+		digest:    sha256.Sum256(b),
+		resources: make(map[string]namespace, len(f.Resources)),
+	}
+
+	for resource, rf := range f.Resources {
+		if !nameRe.MatchString(resource) {
+			return nil, fmt.Errorf("%w: resource type %q", errInvalidPolicy, resource)
+		}
+
+		ns := make(namespace)
+		for name, r := range rf.Relations {
+			rw := &rewrite{subjects: make(map[string]string)}
+			for _, s := range r.Subjects {
+				subject, userset, _ := strings.Cut(s, usersetSeparator)
+				rw.subjects[subject] = userset
+			}
+			rw.addTerms(r.Includes)
+			ns[name] = rw
+		}
+		for name, terms := range rf.Permissions {
+			if _, ok := ns[name]; ok {
+				return nil, fmt.Errorf("%w: %s#%s is a relation and a permission", errInvalidPolicy, resource, name)
+			}
+			rw := &rewrite{}
+			rw.addTerms(terms)
+			ns[name] = rw
+		}
+		p.resources[resource] = ns
+	}
+
+	err = p.validate()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidPolicy, err)
+	}
+
+	return p, nil
+}
+
+// addTerms parse relation and tupleset->relation terms,
+// names are checked once the policy is complete
+func (rw *rewrite) addTerms(terms []string) {
+	for _, term := range terms {
+		tupleset, relation, ok := strings.Cut(term, arrowSeparator)
+		if !ok {
+			rw.includes = append(rw.includes, term)
+			continue
+		}
+		rw.parents = append(rw.parents, tupleToUserset{
+			tupleset: tupleset,
+			relation: relation,
+		})
+	}
+}
+
+// validate every name a policy refers to is defined
+func (p *policy) validate() error {
+	for resource, ns := range p.resources {
+		for name, rw := range ns {
+			if !nameRe.MatchString(name) {
+				return fmt.Errorf("relation %s#%q", resource, name)
+			}
+
+			if rw.subjects != nil && len(rw.subjects) == 0 {
+				return fmt.Errorf("relation %s#%s has no subjects", resource, name)
+			}
+			for subject, userset := range rw.subjects {
+				if _, ok := p.resources[subject]; !ok {
+					return fmt.Errorf("relation %s#%s: unknown subject type %q", resource, name, subject)
+				}
+				if userset != "" && p.relation(subject, userset) == nil {
+					return fmt.Errorf("relation %s#%s: unknown subject relation %s#%s", resource, name, subject, userset)
+				}
+			}
+
+			for _, include := range rw.includes {
+				if ns[include] == nil {
+					return fmt.Errorf("%s#%s: unknown relation %q", resource, name, include)
+				}
+			}
+
+			for _, parent := range rw.parents {
+				tupleset := ns[parent.tupleset]
+				if tupleset == nil || tupleset.subjects == nil {
+					return fmt.Errorf("%s#%s: unknown tupleset relation %q", resource, name, parent.tupleset)
+				}
+
+				// one subject type defining the relation is enough,
+				// the others never grant it
+				defined := false
+				for subject := range tupleset.subjects {
+					defined = defined || p.relation(subject, parent.relation) != nil
+				}
+				if !defined {
+					return fmt.Errorf("%s#%s: no subject of %s defines %q", resource, name, parent.tupleset, parent.relation)
+				}
+			}
+		}
+	}
+
+	for _, r := range requiredRelations {
+		rw := p.relation(r[0], r[1])
+		if rw == nil || rw.subjects == nil {
+			return fmt.Errorf("missing relation %s#%s", r[0], r[1])
+		}
+		if _, ok := rw.subjects[r[2]]; !ok {
+			return fmt.Errorf("relation %s#%s does not allow subject %s", r[0], r[1], r[2])
+		}
+	}
+	for _, r := range requiredPermissions {
+		if p.relation(r[0], r[1]) == nil {
+			return fmt.Errorf("missing permission %s#%s", r[0], r[1])
+		}
+	}
+
+	return nil
+}
+
+// relation rewrite of a relation or permission on resource,
+// nil when undefined
+func (p *policy) relation(resource, relation string) *rewrite {
+	return p.resources[resource][relation]
+}
+
+// WatchPolicy reload the policy at path every interval
+// until ctx is done
+//
+// a changed file must declare a new version, invalid
+// files are logged and the active policy is kept
+func (ac *accessControl) WatchPolicy(ctx context.Context, logger *slog.Logger, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p, err := LoadPolicy(path)
+			if err != nil {
+				logger.ErrorContext(ctx, "failed to load policy", "path", path, "error", err)
+				continue
+			}
+
+			previous := ac.policy.Load()
+			if p.digest == previous.digest {
+				continue
+			}
+
+			err = ac.setPolicy(p)
+			if err != nil {
+				logger.ErrorContext(ctx, "failed to reload policy", "path", path, "error", err)
+				continue
+			}
+			logger.InfoContext(ctx, "policy reloaded", "previous", previous.version, "version", p.version)
+		}
+	}
+}
+
+// setPolicy activate p, checks in progress finish
+// with the policy they started with
+func (ac *accessControl) setPolicy(p *policy) error {
+	active := ac.policy.Load()
+	if active != nil && active.version == p.version && active.digest != p.digest {
+		return fmt.Errorf("%w: policy changed without a new version %s", errInvalidPolicy, p.version)
+	}
+
+	ac.policy.Store(p)
+	return nil
+}
@@ -0,0 +1,153 @@
+package identities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPolicy minimal policy with the required relations and
+// permissions, documents get viewers directly or from their folder
+const testPolicy = `{
+  "version": "%s",
+  "resources": {
+    "REALM": {
+      "relations": {"owner": {"subjects": ["USER"]}, "member": {"subjects": ["USER"]}},
+      "permissions": {"manage": ["owner"], "view": ["member"]}
+    },
+    "USER": {
+      "relations": {"parent": {"subjects": ["REALM"]}},
+      "permissions": {"manage": ["parent->member"], "view": ["parent->member"]}
+    },
+    "GROUP": {
+      "relations": {"parent": {"subjects": ["REALM"]}},
+      "permissions": {"manage": ["parent->member"], "view": ["parent->member"]}
+    },
+    "FOLDER": {"relations": {"viewer": {"subjects": ["USER"]}}},
+    "DOCUMENT": {
+      "relations": {
+        "folder": {"subjects": ["FOLDER"]},
+        "viewer": {"subjects": ["USER"]%s}
+      },
+      "permissions": {"view": ["viewer"]}
+    }
+  }
+}`
+
+func policyOf(version, viewerIncludes string) string {
+	return fmt.Sprintf(testPolicy, version, viewerIncludes)
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := parsePolicy([]byte(policyOf("t1", `, "includes": ["folder->viewer"]`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.version != "t1" {
+		t.Fatalf("unexpected version %s expected t1", p.version)
+	}
+
+	tests := map[string]string{
+		"missing version":     policyOf("", ""),
+		"unknown include":     policyOf("t1", `, "includes": ["editor"]`),
+		"unknown tupleset":    policyOf("t1", `, "includes": ["parent->viewer"]`),
+		"undefined arrow":     policyOf("t1", `, "includes": ["folder->owner"]`),
+		"unknown subject":     strings.Replace(policyOf("t1", ""), `"subjects": ["FOLDER"]`, `"subjects": ["DRIVE"]`, 1),
+		"unknown userset":     strings.Replace(policyOf("t1", ""), `"subjects": ["FOLDER"]`, `"subjects": ["FOLDER#owner"]`, 1),
+		"duplicate name":      strings.Replace(policyOf("t1", ""), `{"view": ["viewer"]}`, `{"viewer": ["folder"]}`, 1),
+		"missing required":    strings.Replace(policyOf("t1", ""), `"member":`, `"members":`, 1),
+		"missing permission":  strings.Replace(policyOf("t1", ""), `"manage": ["owner"], `, "", 1),
+		"unknown field":       strings.Replace(policyOf("t1", ""), `"version"`, `"versions"`, 1),
+		"invalid relation":    strings.Replace(policyOf("t1", ""), `"folder":`, `"fol der":`, 1),
+		"relation no subject": strings.Replace(policyOf("t1", ""), `"subjects": ["FOLDER"]`, `"subjects": []`, 1),
+	}
+	for name, policy := range tests {
+		if _, err = parsePolicy([]byte(policy)); !errors.Is(err, errInvalidPolicy) {
+			t.Fatalf("%s: unexpected error %v expected %v", name, err, errInvalidPolicy)
+		}
+	}
+}
+
+func TestWatchPolicy(t *testing.T) {
+	ac := newTestAccessControl(t)
+	for _, v := range []*vertex{
+		{id: "reports", resource: "FOLDER"},
+		{id: "q3", resource: "DOCUMENT"},
+	} {
+		if err := ac.g.addVertex(v); err != nil {
+			t.Fatalf("failed to add vertex: %v", err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+	write := func(policy string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+			t.Fatalf("failed to write policy: %v", err)
+		}
+	}
+
+	write(policyOf("t1", ""))
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ac.setPolicy(p); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range [][3]string{
+		{"reports", "viewer", "alice"},
+		{"q3", "folder", "reports"},
+	} {
+		if _, err = ac.writeRelationship(r[0], r[1], r[2]); err != nil {
+			t.Fatalf("failed to write relationship %v: %v", r, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ac.WatchPolicy(ctx, slog.New(slog.DiscardHandler), path, time.Millisecond*10)
+
+	view := func(expected bool, version string) {
+		t.Helper()
+		d, err := ac.Check(ctx, "alice", "view", "q3")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Allowed != expected || d.PolicyVersion != version {
+			t.Fatalf("unexpected decision %+v expected %t at %s", d, expected, version)
+		}
+	}
+	waitVersion := func(version string) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second * 5)
+		for ac.policy.Load().version != version {
+			if time.Now().After(deadline) {
+				t.Fatalf("policy %s not loaded", version)
+			}
+			time.Sleep(time.Millisecond * 10)
+		}
+	}
+
+	view(false, "t1")
+
+	// a changed rule without a new version is never loaded
+	write(policyOf("t1", `, "includes": ["folder->viewer"]`))
+	time.Sleep(time.Millisecond * 50)
+	view(false, "t1")
+
+	// invalid policies keep the active one
+	write(policyOf("t2", `, "includes": ["folder->owner"]`))
+	time.Sleep(time.Millisecond * 50)
+	view(false, "t1")
+
+	write(policyOf("t2", `, "includes": ["folder->viewer"]`))
+	waitVersion("t2")
+	view(true, "t2")
+}
@@ -0,0 +1,79 @@
+package identities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "github.com/trevatk/tbd/lib/protocol/identities/v1"
+	"github.com/trevatk/tbd/lib/protocol/interceptors"
+)
+
+var (
+	errUnknownMethod = errors.New("method has no access rule")
+)
+
+// interface compliance
+var _ interceptors.Resolver = Resolve
+
+// Resolve permission and object a request requires of its caller
+//
+// reads require view and writes manage on the realm, user or group
+// the request names, methods without an object are open to any
+// authenticated caller
+func Resolve(_ context.Context, fullMethod string, req any) (string, string, error) {
+	switch in := req.(type) {
+	case *pb.CreateRealmRequest, *pb.ListRealmsRequest:
+		return interceptors.Authenticated, fullMethod, nil
+
+	case *pb.GetRealmRequest:
+		return permissionView, in.GetHash(), nil
+	case *pb.UpdateRealmRequest:
+		return permissionManage, in.GetHash(), nil
+	case *pb.DeleteRealmRequest:
+		return permissionManage, in.GetHash(), nil
+
+	case *pb.CreateUserRequest:
+		return permissionManage, in.GetRealmHash(), nil
+	case *pb.GetUserRequest:
+		return permissionView, in.GetHash(), nil
+	case *pb.ListUsersRequest:
+		return permissionView, in.GetRealmHash(), nil
+	case *pb.UpdateUserRequest:
+		return permissionManage, in.GetHash(), nil
+	case *pb.DeleteUserRequest:
+		return permissionManage, in.GetHash(), nil
+
+	case *pb.CreateGroupRequest:
+		return permissionManage, in.GetRealmHash(), nil
+	case *pb.GetGroupRequest:
+		return permissionView, in.GetHash(), nil
+	case *pb.ListGroupsRequest:
+		return permissionView, in.GetRealmHash(), nil
+	case *pb.UpdateGroupRequest:
+		return permissionManage, in.GetHash(), nil
+	case *pb.DeleteGroupRequest:
+		return permissionManage, in.GetHash(), nil
+
+	case *pb.AddMemberRequest:
+		return permissionManage, in.GetGroupHash(), nil
+	case *pb.RemoveMemberRequest:
+		return permissionManage, in.GetGroupHash(), nil
+
+	case *pb.AssignRoleRequest:
+		return permissionManage, in.GetRealmHash(), nil
+	case *pb.RevokeRoleRequest:
+		return permissionManage, in.GetRealmHash(), nil
+	case *pb.ListRolesRequest:
+		return permissionView, in.GetRealmHash(), nil
+
+	case *pb.CheckRequest:
+		return permissionView, in.GetObject(), nil
+	case *pb.WriteRelationshipRequest:
+		return permissionManage, in.GetRelationship().GetObject(), nil
+	case *pb.DeleteRelationshipRequest:
+		return permissionManage, in.GetRelationship().GetObject(), nil
+	}
+
+	return "", "", fmt.Errorf("%w: %s", errUnknownMethod, fullMethod)
+}
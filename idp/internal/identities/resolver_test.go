@@ -0,0 +1,93 @@
+package identities
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "github.com/trevatk/tbd/lib/protocol/identities/v1"
+	"github.com/trevatk/tbd/lib/protocol/interceptors"
+)
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestService(t)
+
+	// alice owns acme and manages it through the admin role
+	acme, alice, err := s.createRealm(ctx, realmCreate{name: "acme", owner: "alice@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := s.createUser(ctx, userCreate{realm: acme.hash, email: "bob@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eng, err := s.createGroup(ctx, groupCreate{realm: acme.hash, name: "eng"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// members view the realm, admins manage it
+	tests := map[string]struct {
+		req   any
+		alice bool
+		bob   bool
+	}{
+		"GetRealm":           {req: &pb.GetRealmRequest{Hash: acme.hash}, alice: true, bob: true},
+		"UpdateRealm":        {req: &pb.UpdateRealmRequest{Hash: acme.hash}, alice: true},
+		"DeleteRealm":        {req: &pb.DeleteRealmRequest{Hash: acme.hash}, alice: true},
+		"CreateUser":         {req: &pb.CreateUserRequest{RealmHash: acme.hash}, alice: true},
+		"GetUser":            {req: &pb.GetUserRequest{Hash: bob.hash}, alice: true},
+		"ListUsers":          {req: &pb.ListUsersRequest{RealmHash: acme.hash}, alice: true, bob: true},
+		"UpdateUser":         {req: &pb.UpdateUserRequest{Hash: bob.hash}, alice: true},
+		"DeleteUser":         {req: &pb.DeleteUserRequest{Hash: bob.hash}, alice: true},
+		"CreateGroup":        {req: &pb.CreateGroupRequest{RealmHash: acme.hash}, alice: true},
+		"GetGroup":           {req: &pb.GetGroupRequest{Hash: eng.hash}, alice: true, bob: true},
+		"ListGroups":         {req: &pb.ListGroupsRequest{RealmHash: acme.hash}, alice: true, bob: true},
+		"UpdateGroup":        {req: &pb.UpdateGroupRequest{Hash: eng.hash}, alice: true},
+		"DeleteGroup":        {req: &pb.DeleteGroupRequest{Hash: eng.hash}, alice: true},
+		"AddMember":          {req: &pb.AddMemberRequest{GroupHash: eng.hash}, alice: true},
+		"RemoveMember":       {req: &pb.RemoveMemberRequest{GroupHash: eng.hash}, alice: true},
+		"AssignRole":         {req: &pb.AssignRoleRequest{RealmHash: acme.hash}, alice: true},
+		"RevokeRole":         {req: &pb.RevokeRoleRequest{RealmHash: acme.hash}, alice: true},
+		"ListRoles":          {req: &pb.ListRolesRequest{RealmHash: acme.hash}, alice: true, bob: true},
+		"Check":              {req: &pb.CheckRequest{Object: acme.hash}, alice: true, bob: true},
+		"WriteRelationship":  {req: &pb.WriteRelationshipRequest{Relationship: &pb.Relationship{Object: acme.hash}}, alice: true},
+		"DeleteRelationship": {req: &pb.DeleteRelationshipRequest{Relationship: &pb.Relationship{Object: acme.hash}}, alice: true},
+	}
+
+	for _, m := range pb.IdentitiesService_ServiceDesc.Methods {
+		method := "/" + pb.IdentitiesService_ServiceDesc.ServiceName + "/" + m.MethodName
+		if m.MethodName == "CreateRealm" || m.MethodName == "ListRealms" {
+			continue
+		}
+
+		tt, ok := tests[m.MethodName]
+		if !ok {
+			t.Fatalf("method %s not tested", m.MethodName)
+		}
+		relation, object, err := Resolve(ctx, method, tt.req)
+		if err != nil {
+			t.Fatalf("%s: %v", m.MethodName, err)
+		}
+		for subject, expected := range map[string]bool{alice.hash: tt.alice, bob.hash: tt.bob} {
+			d, err := s.ac.Check(ctx, subject, relation, object)
+			if err != nil {
+				t.Fatalf("%s: %v", m.MethodName, err)
+			}
+			if d.Allowed != expected {
+				t.Fatalf("%s: unexpected decision %t expected %t", m.MethodName, d.Allowed, expected)
+			}
+		}
+	}
+
+	for _, req := range []any{&pb.CreateRealmRequest{}, &pb.ListRealmsRequest{}} {
+		relation, _, err := Resolve(ctx, "", req)
+		if err != nil || relation != interceptors.Authenticated {
+			t.Fatalf("unexpected relation %s error %v expected %s", relation, err, interceptors.Authenticated)
+		}
+	}
+	if _, _, err = Resolve(ctx, "/unknown", &pb.Realm{}); !errors.Is(err, errUnknownMethod) {
+		t.Fatalf("unexpected error %v expected %v", err, errUnknownMethod)
+	}
+}
@@ -2,16 +2,41 @@ package identities
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
-type whoami struct {
-	P []byte // public key
-}
+const (
+	defaultListLimit = 100
+
+	attrDisplayName = "display_name"
+	attrEmail       = "email"
+	attrName        = "name"
+)
+
+var (
+	errInvalidCursor = errors.New("invalid cursor")
+	errOtherRealm    = errors.New("subject belongs to another realm")
+	errManaged       = errors.New("relation is managed by the service")
+)
 
 type realmCreate struct {
 	name string
-	who  whoami
+	// owner email of the user created as owner of the realm
+	owner string
+}
+
+type realmUpdate struct {
+	hash string
+	name string
 }
 
 type realm struct {
@@ -24,64 +49,777 @@ type userCreate struct {
 	email string
 }
 
+type userUpdate struct {
+	hash  string
+	email string
+}
+
 type user struct {
+	hash  string
+	realm string
+	email string
+}
+
+type groupCreate struct {
+	realm string
+	name  string
+}
+
+type groupUpdate struct {
 	hash string
+	name string
+}
+
+type group struct {
+	hash  string
+	realm string
+	name  string
+}
+
+type roleAssignment struct {
+	role    string
+	subject string
 }
 
+// service identities service
+//
+// events are staged in the outbox by the write of their mutation
+// and emitted by the relay, a mutation succeeds once written
+// whether or not its events were delivered yet
 type service struct {
-	g *graph
+	g      *graph
+	ac     *accessControl
+	events emitter
+
+	// pending wakes the relay after a mutation
+	pending chan struct{}
 }
 
 // NewService return new access service implementation
-func NewService(graph *graph) *service {
+func NewService(graph *graph, ac *accessControl, events emitter) *service {
 	return &service{
-		g: graph,
+		g:       graph,
+		ac:      ac,
+		events:  events,
+		pending: make(chan struct{}, 1),
 	}
 }
 
-func (s *service) createRealm(_ context.Context, create realmCreate) (realm, error) {
-	// TODO
-	// verify who has permissions
-	// to create realm
+// createRealm create a realm with the user owning it,
+// the owner is a member of the realm and manages it
+func (s *service) createRealm(_ context.Context, create realmCreate) (realm, user, error) {
+	id, ownerID := uuid.NewString(), uuid.NewString()
+	now := time.Now().UTC()
 
-	_, _ = s.g.getVertex("")
+	err := s.g.addVertices([]*vertex{
+		{
+			id:       id,
+			resource: resourceRealm,
+			edges: []*edge{
+				{relationship: relationOwner, to: ownerID},
+				{relationship: relationMember, to: ownerID},
+			},
+			attributes: map[string]interface{}{attrDisplayName: create.name},
+		},
+		{
+			id:         ownerID,
+			resource:   resourceUser,
+			key:        userKey(id, create.owner),
+			edges:      []*edge{{relationship: relationParent, to: id}},
+			attributes: map[string]interface{}{attrEmail: create.owner},
+		},
+	}, nil,
+		record{Key: realmEventPrefix + id, Event: event{Type: eventRealmCreated, Subject: id, Timestamp: now}},
+		record{Key: userEventPrefix + ownerID, Event: event{Type: eventUserCreated, Subject: ownerID, Realm: id, Timestamp: now}},
+	)
+	if err != nil {
+		return realm{}, user{}, fmt.Errorf("failed to add realm vertex: %w", err)
+	}
+	s.notify()
 
-	attrs := make(map[string]interface{})
-	if err := s.g.addVertex(&vertex{
-		id:         create.name,
-		resource:   "REALM",
-		edges:      []*edge{},
-		attributes: attrs,
-	}); err != nil {
-		return realm{}, fmt.Errorf("failed to add vertex: %w", err)
+	return realm{
+		hash: id,
+		name: create.name,
+	}, user{
+		hash:  ownerID,
+		realm: id,
+		email: create.owner,
+	}, nil
+}
+
+func (s *service) getRealm(id string) (realm, error) {
+	v, err := s.vertexOf(id, resourceRealm)
+	if err != nil {
+		return realm{}, err
 	}
+	return newRealm(v), nil
+}
 
-	// TODO
-	// emit events to zero
-	// - decision
-	// - realm creation
+// listRealms page of up to limit realms after cursor in id order,
+// the returned cursor is empty on the last page
+// listRealms page of the realms subject may view
+func (s *service) listRealms(ctx context.Context, subject, cursor string, limit int) ([]realm, string, error) {
+	var checkErr error
+	ids, next, err := page(cursor, limit, func(after string, fn func(string) bool) error {
+		return s.g.listVertices(resourceRealm, after, func(id string) bool {
+			d, err := s.ac.Check(ctx, subject, permissionView, id)
+			if err != nil {
+				checkErr = err
+				return false
+			}
+			return !d.Allowed || fn(id)
+		})
+	})
+	if err == nil {
+		err = checkErr
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	realms := make([]realm, 0, len(ids))
+	for _, id := range ids {
+		v, err := s.g.getVertex(id)
+		if err != nil {
+			return nil, "", err
+		}
+		realms = append(realms, newRealm(v))
+	}
 
-	return realm{}, nil
+	return realms, next, nil
 }
 
-func (s *service) createUser(_ context.Context, create userCreate) (user, error) {
-	if err := s.g.addVertex(&vertex{
-		id:         create.email + "_REALM-USER",
-		resource:   "USER",
-		edges:      make([]*edge, 0),
-		attributes: map[string]interface{}{},
-	}); err != nil {
-		return user{}, fmt.Errorf("failed to add user vertex: %w", err)
+func (s *service) updateRealm(ctx context.Context, update realmUpdate) (realm, error) {
+	v, err := s.vertexOf(update.hash, resourceRealm)
+	if err != nil {
+		return realm{}, err
 	}
 
-	if err := s.g.addEdge(create.realm, &edge{
-		to:           create.email + "_REALM-USER",
-		relationship: "RESOURCE",
-	}); err != nil {
-		return user{}, fmt.Errorf("failed to add user edge to realm vertex: %w", err)
+	attrs := maps.Clone(v.attributes)
+	attrs[attrDisplayName] = update.name
+	v, err = s.g.updateVertex(v.id, v.key, attrs, record{
+		Key:   realmEventPrefix + v.id,
+		Event: event{Type: eventRealmUpdated, Subject: v.id, Timestamp: time.Now().UTC()},
+	})
+	if err != nil {
+		return realm{}, fmt.Errorf("failed to update realm: %w", err)
 	}
+	s.notify()
+
+	return newRealm(v), nil
+}
+
+// deleteRealm delete realm id with its users and groups
+func (s *service) deleteRealm(ctx context.Context, id string) error {
+	_, err := s.vertexOf(id, resourceRealm)
+	if err != nil {
+		return err
+	}
+	return s.delete(ctx, id)
+}
+
+func (s *service) createUser(ctx context.Context, create userCreate) (user, error) {
+	_, err := s.vertexOf(create.realm, resourceRealm)
+	if err != nil {
+		return user{}, fmt.Errorf("realm: %w", err)
+	}
+
+	// the user and its membership of the realm are one write
+	id := uuid.NewString()
+	err = s.g.addVertices([]*vertex{{
+		id:         id,
+		resource:   resourceUser,
+		key:        userKey(create.realm, create.email),
+		edges:      []*edge{{relationship: relationParent, to: create.realm}},
+		attributes: map[string]interface{}{attrEmail: create.email},
+	}}, []link{{
+		from: create.realm,
+		edge: &edge{relationship: relationMember, to: id},
+	}}, record{
+		Key:   userEventPrefix + id,
+		Event: event{Type: eventUserCreated, Subject: id, Realm: create.realm, Timestamp: time.Now().UTC()},
+	})
+	if err != nil {
+		return user{}, fmt.Errorf("failed to add user vertex: %w", err)
+	}
+	s.notify()
 
 	return user{
-		hash: create.email + "_REALM-USER",
+		hash:  id,
+		realm: create.realm,
+		email: create.email,
+	}, nil
+}
+
+func (s *service) getUser(id string) (user, error) {
+	v, err := s.vertexOf(id, resourceUser)
+	if err != nil {
+		return user{}, err
+	}
+	return newUser(v), nil
+}
+
+// listUsers page of up to limit users of realm after cursor
+// in id order, the returned cursor is empty on the last page
+func (s *service) listUsers(realmID, cursor string, limit int) ([]user, string, error) {
+	vs, next, err := s.listChildren(realmID, resourceUser, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	users := make([]user, 0, len(vs))
+	for _, v := range vs {
+		users = append(users, newUser(v))
+	}
+	return users, next, nil
+}
+
+func (s *service) updateUser(ctx context.Context, update userUpdate) (user, error) {
+	v, err := s.vertexOf(update.hash, resourceUser)
+	if err != nil {
+		return user{}, err
+	}
+
+	attrs := maps.Clone(v.attributes)
+	attrs[attrEmail] = update.email
+	v, err = s.g.updateVertex(v.id, userKey(realmOf(v), update.email), attrs, record{
+		Key: userEventPrefix + v.id,
+		Event: event{
+			Type:      eventUserUpdated,
+			Subject:   v.id,
+			Realm:     realmOf(v),
+			Timestamp: time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return user{}, fmt.Errorf("failed to update user: %w", err)
+	}
+	s.notify()
+
+	return newUser(v), nil
+}
+
+// deleteUser delete user id with its memberships and roles
+func (s *service) deleteUser(ctx context.Context, id string) error {
+	_, err := s.vertexOf(id, resourceUser)
+	if err != nil {
+		return err
+	}
+	return s.delete(ctx, id)
+}
+
+func (s *service) createGroup(ctx context.Context, create groupCreate) (group, error) {
+	_, err := s.vertexOf(create.realm, resourceRealm)
+	if err != nil {
+		return group{}, fmt.Errorf("realm: %w", err)
+	}
+
+	id := uuid.NewString()
+	err = s.g.addVertex(&vertex{
+		id:         id,
+		resource:   resourceGroup,
+		key:        groupKey(create.realm, create.name),
+		edges:      []*edge{{relationship: relationParent, to: create.realm}},
+		attributes: map[string]interface{}{attrName: create.name},
+	}, record{
+		Key:   groupEventPrefix + id,
+		Event: event{Type: eventGroupCreated, Subject: id, Realm: create.realm, Timestamp: time.Now().UTC()},
+	})
+	if err != nil {
+		return group{}, fmt.Errorf("failed to add group vertex: %w", err)
+	}
+	s.notify()
+
+	return group{
+		hash:  id,
+		realm: create.realm,
+		name:  create.name,
 	}, nil
 }
+
+func (s *service) getGroup(id string) (group, error) {
+	v, err := s.vertexOf(id, resourceGroup)
+	if err != nil {
+		return group{}, err
+	}
+	return newGroup(v), nil
+}
+
+// listGroups page of up to limit groups of realm after cursor
+// in id order, the returned cursor is empty on the last page
+func (s *service) listGroups(realmID, cursor string, limit int) ([]group, string, error) {
+	vs, next, err := s.listChildren(realmID, resourceGroup, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	groups := make([]group, 0, len(vs))
+	for _, v := range vs {
+		groups = append(groups, newGroup(v))
+	}
+	return groups, next, nil
+}
+
+func (s *service) updateGroup(ctx context.Context, update groupUpdate) (group, error) {
+	v, err := s.vertexOf(update.hash, resourceGroup)
+	if err != nil {
+		return group{}, err
+	}
+
+	attrs := maps.Clone(v.attributes)
+	attrs[attrName] = update.name
+	v, err = s.g.updateVertex(v.id, groupKey(realmOf(v), update.name), attrs, record{
+		Key: groupEventPrefix + v.id,
+		Event: event{
+			Type:      eventGroupUpdated,
+			Subject:   v.id,
+			Realm:     realmOf(v),
+			Timestamp: time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return group{}, fmt.Errorf("failed to update group: %w", err)
+	}
+	s.notify()
+
+	return newGroup(v), nil
+}
+
+// deleteGroup delete group id, its members keep their accounts
+func (s *service) deleteGroup(ctx context.Context, id string) error {
+	_, err := s.vertexOf(id, resourceGroup)
+	if err != nil {
+		return err
+	}
+	return s.delete(ctx, id)
+}
+
+// addMember add user or group member to group groupID
+// and return a consistency token including it
+func (s *service) addMember(ctx context.Context, groupID, member string) (string, error) {
+	g, err := s.vertexOf(groupID, resourceGroup)
+	if err != nil {
+		return "", err
+	}
+	err = s.sameRealm(realmOf(g), member)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := s.ac.writeRelationship(groupID, relationMember, member, record{
+		Key: groupEventPrefix + groupID + "/members/" + member,
+		Event: event{
+			Type:      eventMemberAdded,
+			Subject:   member,
+			Realm:     realmOf(g),
+			Group:     groupID,
+			Timestamp: time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	s.notify()
+
+	return token, nil
+}
+
+// removeMember remove member from group groupID and return
+// a consistency token without it
+func (s *service) removeMember(ctx context.Context, groupID, member string) (string, error) {
+	g, err := s.vertexOf(groupID, resourceGroup)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := s.ac.deleteRelationship(groupID, relationMember, member, record{
+		Key: groupEventPrefix + groupID + "/members/" + member,
+		Event: event{
+			Type:      eventMemberRemoved,
+			Subject:   member,
+			Realm:     realmOf(g),
+			Group:     groupID,
+			Timestamp: time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	s.notify()
+
+	return token, nil
+}
+
+// assignRole grant realm relation role to a user or group of
+// the realm and return a consistency token including it
+//
+// roles are the realm relations of the active policy
+func (s *service) assignRole(ctx context.Context, realmID string, a roleAssignment) (string, error) {
+	_, err := s.vertexOf(realmID, resourceRealm)
+	if err != nil {
+		return "", err
+	}
+	err = s.sameRealm(realmID, a.subject)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := s.ac.writeRelationship(realmID, a.role, a.subject, record{
+		Key: realmEventPrefix + realmID + "/roles/" + a.role + "/" + a.subject,
+		Event: event{
+			Type:      eventRoleAssigned,
+			Subject:   a.subject,
+			Realm:     realmID,
+			Role:      a.role,
+			Timestamp: time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	s.notify()
+
+	return token, nil
+}
+
+// revokeRole remove realm relation role from its subject and
+// return a consistency token without it
+func (s *service) revokeRole(ctx context.Context, realmID string, a roleAssignment) (string, error) {
+	_, err := s.vertexOf(realmID, resourceRealm)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := s.ac.deleteRelationship(realmID, a.role, a.subject, record{
+		Key: realmEventPrefix + realmID + "/roles/" + a.role + "/" + a.subject,
+		Event: event{
+			Type:      eventRoleRevoked,
+			Subject:   a.subject,
+			Realm:     realmID,
+			Role:      a.role,
+			Timestamp: time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	s.notify()
+
+	return token, nil
+}
+
+// listRoles every relation granted on realm
+func (s *service) listRoles(realmID string) ([]roleAssignment, error) {
+	v, err := s.vertexOf(realmID, resourceRealm)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]roleAssignment, 0, len(v.edges))
+	for _, e := range v.edges {
+		roles = append(roles, roleAssignment{
+			role:    e.relationship,
+			subject: e.to,
+		})
+	}
+	return roles, nil
+}
+
+// delete remove vertex id with every vertex whose parent it is,
+// edges from or to a removed vertex are removed with it
+func (s *service) delete(_ context.Context, id string) error {
+	_, err := s.g.removeVertex(id, relationParent, deletedRecords)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", id, err)
+	}
+	s.notify()
+
+	return nil
+}
+
+// deletedRecords deleted events of removed realms, users and groups
+func deletedRecords(removed []*vertex) []record {
+	now := time.Now().UTC()
+
+	records := make([]record, 0, len(removed))
+	for _, v := range removed {
+		var key, typ string
+		switch v.resource {
+		case resourceRealm:
+			key, typ = realmEventPrefix, eventRealmDeleted
+		case resourceUser:
+			key, typ = userEventPrefix, eventUserDeleted
+		case resourceGroup:
+			key, typ = groupEventPrefix, eventGroupDeleted
+		default:
+			continue
+		}
+
+		records = append(records, record{
+			Key:   key + v.id,
+			Event: event{Type: typ, Subject: v.id, Realm: realmOf(v), Timestamp: now},
+		})
+	}
+	return records
+}
+
+// notify wake the relay, a relay already woken
+// publishes the records of this mutation too
+func (s *service) notify() {
+	select {
+	case s.pending <- struct{}{}:
+	default:
+	}
+}
+
+// RelayEvents publish the outbox after every mutation and at
+// least every interval until ctx is done, events failing to
+// publish are logged and retried
+func (s *service) RelayEvents(ctx context.Context, logger *slog.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.publish(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.ErrorContext(ctx, "failed to publish events", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.pending:
+		}
+	}
+}
+
+// publish emit the records of the outbox in write order and
+// delete each once emitted, the first failure stops publishing
+// so events are never reordered
+//
+// a record emitted but not deleted is emitted again, events are
+// records of the keyvalue service and replaced when repeated
+func (s *service) publish(ctx context.Context) error {
+	type staged struct {
+		key string
+		r   record
+	}
+
+	// the outbox is read before emitting
+	// so the iterator is never left open
+	var records []staged
+	it := s.g.store.Range(outboxPrefix, outboxEnd)
+	defer it.Close()
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return fmt.Errorf("failed to read outbox: %w", err)
+		}
+
+		var r record
+		err = json.Unmarshal(v, &r)
+		if err != nil {
+			return fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		records = append(records, staged{key: k, r: r})
+	}
+
+	for _, st := range records {
+		err := s.events.emit(ctx, st.r.Key, st.r.Event)
+		if err != nil {
+			return fmt.Errorf("failed to emit %s: %w", st.r.Event.Type, err)
+		}
+
+		err = s.g.store.Delete(st.key)
+		if err != nil {
+			return fmt.Errorf("failed to delete outbox record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// listChildren page of vertices of resource type whose parent
+// is realm realmID
+func (s *service) listChildren(realmID, resource, cursor string, limit int) ([]*vertex, string, error) {
+	_, err := s.vertexOf(realmID, resourceRealm)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var vs []*vertex
+	ids, next, err := page(cursor, limit, func(after string, fn func(string) bool) error {
+		var getErr error
+		err := s.g.incoming(realmID, relationParent, after, func(_, from string) bool {
+			var v *vertex
+			v, getErr = s.g.getVertex(from)
+			if getErr != nil {
+				return false
+			}
+			if v.resource != resource {
+				return true
+			}
+			vs = append(vs, v)
+			return fn(from)
+		})
+		if err != nil {
+			return err
+		}
+		return getErr
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return vs[:len(ids)], next, nil
+}
+
+// vertexOf vertex id of resource type, not found for
+// vertices of another type
+func (s *service) vertexOf(id, resource string) (*vertex, error) {
+	v, err := s.g.getVertex(id)
+	if err != nil {
+		return nil, err
+	}
+	if v.resource != resource {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotFound, strings.ToLower(resource), id)
+	}
+	return v, nil
+}
+
+// writeRelationship add edge object -relation-> subject between
+// vertices of one realm, parent edges are written by the service
+func (s *service) writeRelationship(object, relation, subject string) (string, error) {
+	err := s.validateRelationship(object, relation, subject)
+	if err != nil {
+		return "", err
+	}
+	return s.ac.writeRelationship(object, relation, subject)
+}
+
+// deleteRelationship remove edge object -relation-> subject
+// between vertices of one realm, parent edges are removed by
+// the service
+func (s *service) deleteRelationship(object, relation, subject string) (string, error) {
+	err := s.validateRelationship(object, relation, subject)
+	if err != nil {
+		return "", err
+	}
+	return s.ac.deleteRelationship(object, relation, subject)
+}
+
+// validateRelationship reject service managed relations
+// and subjects outside the realm of object
+func (s *service) validateRelationship(object, relation, subject string) error {
+	if relation == relationParent {
+		return fmt.Errorf("%w: %s", errManaged, relation)
+	}
+
+	o, err := s.g.getVertex(object)
+	if err != nil {
+		return err
+	}
+	realmID := realmOf(o)
+	if o.resource == resourceRealm {
+		realmID = o.id
+	}
+
+	if subject == realmID {
+		return nil
+	}
+	return s.sameRealm(realmID, subject)
+}
+
+// sameRealm subject is a user or group of realm realmID
+func (s *service) sameRealm(realmID, subject string) error {
+	v, err := s.g.getVertex(subject)
+	if err != nil {
+		return fmt.Errorf("subject: %w", err)
+	}
+	if realmOf(v) != realmID {
+		return fmt.Errorf("%w: %s", errOtherRealm, subject)
+	}
+	return nil
+}
+
+// page up to limit ids walked after the id in cursor,
+// the returned cursor is empty on the last page
+func page(cursor string, limit int, walk func(after string, fn func(id string) bool) error) ([]string, string, error) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	var after string
+	if cursor != "" {
+		b, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || validateID(string(b)) != nil {
+			return nil, "", errInvalidCursor
+		}
+		after = string(b)
+	}
+
+	ids := make([]string, 0, limit+1)
+	err := walk(after, func(id string) bool {
+		ids = append(ids, id)
+		return len(ids) <= limit
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(ids) <= limit {
+		return ids, "", nil
+	}
+
+	ids = ids[:limit]
+	return ids, base64.RawURLEncoding.EncodeToString([]byte(ids[limit-1])), nil
+}
+
+// realmOf realm a user or group belongs to
+func realmOf(v *vertex) string {
+	for _, e := range v.edges {
+		if e.relationship == relationParent {
+			return e.to
+		}
+	}
+	return ""
+}
+
+// userKey users are unique by email within a realm
+func userKey(realmID, email string) string {
+	return realmID + "/" + strings.ToLower(email)
+}
+
+// groupKey groups are unique by name within a realm
+func groupKey(realmID, name string) string {
+	return realmID + "/" + name
+}
+
+func attribute(v *vertex, name string) string {
+	s, _ := v.attributes[name].(string)
+	return s
+}
+
+func newRealm(v *vertex) realm {
+	return realm{
+		hash: v.id,
+		name: attribute(v, attrDisplayName),
+	}
+}
+
+func newUser(v *vertex) user {
+	return user{
+		hash:  v.id,
+		realm: realmOf(v),
+		email: attribute(v, attrEmail),
+	}
+}
+
+func newGroup(v *vertex) group {
+	return group{
+		hash:  v.id,
+		realm: realmOf(v),
+		name:  attribute(v, attrName),
+	}
+}
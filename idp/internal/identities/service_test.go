@@ -0,0 +1,498 @@
+package identities
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+	"github.com/trevatk/tbd/lib/protocol/interceptors"
+)
+
+// recorder emitter keeping every event, failing while err is set
+type recorder struct {
+	events []event
+	err    error
+}
+
+func (r *recorder) emit(_ context.Context, _ string, e event) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, e)
+	return nil
+}
+
+func (r *recorder) types(typ string) []string {
+	var subjects []string
+	for _, e := range r.events {
+		if e.Type == typ {
+			subjects = append(subjects, e.Subject)
+		}
+	}
+	slices.Sort(subjects)
+	return subjects
+}
+
+func newTestService(t *testing.T) (*service, *recorder) {
+	t.Helper()
+
+	lsm, err := keyvalue.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { _ = lsm.Close() })
+
+	g := NewGraph(lsm)
+	r := &recorder{}
+	return NewService(g, NewAccessControl(g), r), r
+}
+
+func TestRealms(t *testing.T) {
+	s, _ := newTestService(t)
+	ctx := context.Background()
+
+	created := make([]string, 0, 3)
+	owners := make([]string, 0, 3)
+	for _, name := range []string{"acme", "globex", "initech"} {
+		r, owner, err := s.createRealm(ctx, realmCreate{name: name, owner: "owner@" + name + ".io"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.hash == "" || r.name != name {
+			t.Fatalf("unexpected realm %+v", r)
+		}
+		created = append(created, r.hash)
+		owners = append(owners, owner.hash)
+	}
+
+	// owners only list their own realm
+	for i, owner := range owners {
+		realms, _, err := s.listRealms(ctx, owner, "", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(realms) != 1 || realms[0].hash != created[i] {
+			t.Fatalf("unexpected realms %+v expected %s", realms, created[i])
+		}
+	}
+	if realms, _, err := s.listRealms(ctx, interceptors.Anon, "", 0); err != nil || len(realms) != 0 {
+		t.Fatalf("unexpected realms %+v error %v", realms, err)
+	}
+
+	// a member of every realm pages through all of them
+	auditor := owners[0]
+	for _, r := range created[1:] {
+		if _, err := s.ac.writeRelationship(r, relationMember, auditor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	slices.Sort(created)
+
+	realms, cursor, err := s.listRealms(ctx, auditor, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(realms) != 2 || cursor == "" {
+		t.Fatalf("unexpected page %d realms cursor %q", len(realms), cursor)
+	}
+	last, cursor, err := s.listRealms(ctx, auditor, cursor, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(last) != 1 || cursor != "" {
+		t.Fatalf("unexpected page %d realms cursor %q", len(last), cursor)
+	}
+	for i, r := range append(realms, last...) {
+		if r.hash != created[i] {
+			t.Fatalf("unexpected realm %s expected %s", r.hash, created[i])
+		}
+	}
+
+	if _, _, err = s.listRealms(ctx, auditor, "not a cursor", 2); !errors.Is(err, errInvalidCursor) {
+		t.Fatalf("unexpected error %v expected %v", err, errInvalidCursor)
+	}
+
+	updated, err := s.updateRealm(ctx, realmUpdate{hash: created[0], name: "renamed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := s.getRealm(created[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != updated || r.name != "renamed" {
+		t.Fatalf("unexpected realm %+v expected %+v", r, updated)
+	}
+}
+
+func TestUsers(t *testing.T) {
+	s, _ := newTestService(t)
+	ctx := context.Background()
+
+	acme, owner, err := s.createRealm(ctx, realmCreate{name: "acme", owner: "owner@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	globex, _, err := s.createRealm(ctx, realmCreate{name: "globex", owner: "owner@globex.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice, err := s.createUser(ctx, userCreate{realm: acme.hash, email: "alice@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.createUser(ctx, userCreate{realm: acme.hash, email: "Alice@acme.io"}); !errors.Is(err, errVertexExists) {
+		t.Fatalf("unexpected error %v expected %v", err, errVertexExists)
+	}
+	if _, err = s.createUser(ctx, userCreate{realm: globex.hash, email: "alice@acme.io"}); err != nil {
+		t.Fatalf("failed to create user of another realm: %v", err)
+	}
+	if _, err = s.createUser(ctx, userCreate{realm: alice.hash, email: "bob@acme.io"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrNotFound)
+	}
+
+	// groups of the realm are not listed as users
+	if _, err = s.createGroup(ctx, groupCreate{realm: acme.hash, name: "eng"}); err != nil {
+		t.Fatal(err)
+	}
+	users, _, err := s.listUsers(acme.hash, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || !slices.Contains(users, alice) || !slices.Contains(users, owner) {
+		t.Fatalf("unexpected users %+v", users)
+	}
+
+	updated, err := s.updateUser(ctx, userUpdate{hash: alice.hash, email: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.hash != alice.hash || updated.realm != acme.hash || updated.email != "alice@example.com" {
+		t.Fatalf("unexpected user %+v", updated)
+	}
+
+	// the previous email is free once changed
+	if _, err = s.createUser(ctx, userCreate{realm: acme.hash, email: "alice@acme.io"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = s.getUser(acme.hash); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrNotFound)
+	}
+}
+
+func TestMembership(t *testing.T) {
+	s, _ := newTestService(t)
+	ctx := context.Background()
+
+	acme, _, err := s.createRealm(ctx, realmCreate{name: "acme", owner: "owner@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	globex, _, err := s.createRealm(ctx, realmCreate{name: "globex", owner: "owner@globex.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, err := s.createUser(ctx, userCreate{realm: acme.hash, email: "alice@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := s.createUser(ctx, userCreate{realm: globex.hash, email: "bob@globex.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eng, err := s.createGroup(ctx, groupCreate{realm: acme.hash, name: "eng"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.createGroup(ctx, groupCreate{realm: acme.hash, name: "eng"}); !errors.Is(err, errVertexExists) {
+		t.Fatalf("unexpected error %v expected %v", err, errVertexExists)
+	}
+
+	check := func(relation, object string, token string, expected bool) {
+		t.Helper()
+		d, _, err := s.ac.check(ctx, alice.hash, relation, object, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Allowed != expected {
+			t.Fatalf("unexpected check %s %s %t expected %t", relation, object, d.Allowed, expected)
+		}
+	}
+
+	token, err := s.addMember(ctx, eng.hash, alice.hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(relationMember, eng.hash, token, true)
+
+	if _, err = s.addMember(ctx, eng.hash, bob.hash); !errors.Is(err, errOtherRealm) {
+		t.Fatalf("unexpected error %v expected %v", err, errOtherRealm)
+	}
+
+	// members of eng manage the realm through its role
+	check("manage", acme.hash, "", false)
+	token, err = s.assignRole(ctx, acme.hash, roleAssignment{role: "admin", subject: eng.hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("manage", acme.hash, token, true)
+
+	roles, err := s.listRoles(acme.hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(roles, roleAssignment{role: "admin", subject: eng.hash}) ||
+		!slices.Contains(roles, roleAssignment{role: relationMember, subject: alice.hash}) {
+		t.Fatalf("unexpected roles %+v", roles)
+	}
+
+	if _, err = s.assignRole(ctx, acme.hash, roleAssignment{role: "manage", subject: alice.hash}); !errors.Is(err, errUnknownRelation) {
+		t.Fatalf("unexpected error %v expected %v", err, errUnknownRelation)
+	}
+	if _, err = s.assignRole(ctx, acme.hash, roleAssignment{role: "admin", subject: bob.hash}); !errors.Is(err, errOtherRealm) {
+		t.Fatalf("unexpected error %v expected %v", err, errOtherRealm)
+	}
+
+	token, err = s.removeMember(ctx, eng.hash, alice.hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("manage", acme.hash, token, false)
+
+	token, err = s.revokeRole(ctx, acme.hash, roleAssignment{role: "admin", subject: eng.hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.revokeRole(ctx, acme.hash, roleAssignment{role: "admin", subject: eng.hash}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrNotFound)
+	}
+	check(relationMember, eng.hash, token, false)
+}
+
+func TestDeleteCascade(t *testing.T) {
+	s, events := newTestService(t)
+	ctx := context.Background()
+
+	acme, _, err := s.createRealm(ctx, realmCreate{name: "acme", owner: "owner@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	globex, _, err := s.createRealm(ctx, realmCreate{name: "globex", owner: "owner@globex.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := make([]string, 0, 2)
+	for _, email := range []string{"alice@acme.io", "bob@acme.io"} {
+		u, err := s.createUser(ctx, userCreate{realm: acme.hash, email: email})
+		if err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, u.hash)
+	}
+	eng, err := s.createGroup(ctx, groupCreate{realm: acme.hash, name: "eng"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range users {
+		if _, err = s.addMember(ctx, eng.hash, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	carol, err := s.createUser(ctx, userCreate{realm: globex.hash, email: "carol@globex.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a deleted user leaves the groups it was a member of
+	if err = s.deleteUser(ctx, users[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.getUser(users[1]); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrNotFound)
+	}
+	g, err := s.g.getVertex(eng.hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range g.edges {
+		if e.to == users[1] {
+			t.Fatalf("group kept edge %+v to deleted user", e)
+		}
+	}
+
+	if err = s.deleteRealm(ctx, acme.hash); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new graph over the store sees none of the realm
+	s.g = NewGraph(s.g.store)
+	for _, id := range []string{acme.hash, users[0], eng.hash} {
+		if _, err = s.g.getVertex(id); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("unexpected error %v expected %v for %s", err, ErrNotFound, id)
+		}
+	}
+	for _, bounds := range [][2]string{{edgePrefix, "edge`"}, {redgePrefix, "redge`"}} {
+		it := s.g.store.Range(bounds[0], bounds[1])
+		for it.HasNext() {
+			k, _, err := it.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, id := range []string{acme.hash, users[0], eng.hash} {
+				if strings.Contains(k, id) {
+					t.Fatalf("edge %q of deleted vertex %s left behind", k, id)
+				}
+			}
+		}
+	}
+
+	if _, err = s.getUser(carol.hash); err != nil {
+		t.Fatalf("user of another realm deleted: %v", err)
+	}
+	realms := make([]string, 0)
+	err = s.g.listVertices(resourceRealm, "", func(id string) bool {
+		realms = append(realms, id)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(realms) != 1 || realms[0] != globex.hash {
+		t.Fatalf("unexpected realms %v", realms)
+	}
+
+	if err = s.publish(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// alice, bob and the owner of acme
+	if deleted := events.types(eventUserDeleted); len(deleted) != 3 {
+		t.Fatalf("unexpected user deleted events %v", deleted)
+	}
+	if deleted := events.types(eventGroupDeleted); len(deleted) != 1 || deleted[0] != eng.hash {
+		t.Fatalf("unexpected group deleted events %v", deleted)
+	}
+	if deleted := events.types(eventRealmDeleted); len(deleted) != 1 || deleted[0] != acme.hash {
+		t.Fatalf("unexpected realm deleted events %v", deleted)
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	s, events := newTestService(t)
+	ctx := context.Background()
+
+	// mutations are written while the emitter fails
+	events.err = errors.New("unavailable")
+	acme, owner, err := s.createRealm(ctx, realmCreate{name: "acme", owner: "owner@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, err := s.createUser(ctx, userCreate{realm: acme.hash, email: "alice@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.publish(ctx); !errors.Is(err, events.err) {
+		t.Fatalf("unexpected error %v expected %v", err, events.err)
+	}
+
+	// the user and its membership are one write
+	d, err := s.ac.Check(ctx, alice.hash, relationMember, acme.hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Allowed {
+		t.Fatal("created user is not a member of its realm")
+	}
+
+	events.err = nil
+	if err = s.publish(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expected := []event{
+		{Type: eventRealmCreated, Subject: acme.hash},
+		{Type: eventUserCreated, Subject: owner.hash, Realm: acme.hash},
+		{Type: eventUserCreated, Subject: alice.hash, Realm: acme.hash},
+	}
+	if len(events.events) != len(expected) {
+		t.Fatalf("unexpected events %+v", events.events)
+	}
+	for i, e := range events.events {
+		e.Timestamp = expected[i].Timestamp
+		if e != expected[i] {
+			t.Fatalf("unexpected event %+v expected %+v", e, expected[i])
+		}
+	}
+
+	// published events are never emitted again
+	if err = s.publish(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(events.events) != len(expected) {
+		t.Fatalf("unexpected %d events expected %d", len(events.events), len(expected))
+	}
+}
+
+func TestRelationships(t *testing.T) {
+	s, _ := newTestService(t)
+	ctx := context.Background()
+
+	acme, _, err := s.createRealm(ctx, realmCreate{name: "acme", owner: "owner@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	globex, _, err := s.createRealm(ctx, realmCreate{name: "globex", owner: "owner@globex.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, err := s.createUser(ctx, userCreate{realm: acme.hash, email: "alice@acme.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eng, err := s.createGroup(ctx, groupCreate{realm: acme.hash, name: "eng"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		object   string
+		relation string
+		subject  string
+		err      error
+	}{
+		{name: "same realm", object: eng.hash, relation: relationMember, subject: alice.hash},
+		{name: "realm member", object: acme.hash, relation: relationMember, subject: eng.hash},
+		{name: "parent", object: alice.hash, relation: relationParent, subject: globex.hash, err: errManaged},
+		{name: "other realm", object: globex.hash, relation: relationMember, subject: alice.hash, err: errOtherRealm},
+		{name: "unknown object", object: "unknown", relation: relationMember, subject: alice.hash, err: ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.writeRelationship(tt.object, tt.relation, tt.subject)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("unexpected write error %v expected %v", err, tt.err)
+			}
+			_, err = s.deleteRelationship(tt.object, tt.relation, tt.subject)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("unexpected delete error %v expected %v", err, tt.err)
+			}
+		})
+	}
+
+	// alice stays in acme
+	users, _, err := s.listUsers(globex.hash, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range users {
+		if u.hash == alice.hash {
+			t.Fatal("user planted in another realm")
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+	"github.com/trevatk/tbd/lib/protocol"
+	"github.com/trevatk/tbd/lib/setup"
+
+	pbvault "github.com/trevatk/tbd/lib/protocol/vault/v1"
+)
+
+// Options key value store options from config
+func Options(cfg setup.KeyValue) ([]keyvalue.Option, error) {
+	compression, err := keyvalue.ParseCompression(cfg.Compression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse compression: %w", err)
+	}
+
+	opts := []keyvalue.Option{
+		keyvalue.WithSync(cfg.Sync),
+		keyvalue.WithBlockSize(cfg.BlockSize),
+		keyvalue.WithCompression(compression),
+		keyvalue.WithRetention(cfg.Retention),
+		keyvalue.WithCompactionTrigger(cfg.CompactionTables),
+	}
+
+	wrapper, err := keyWrapper(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize key wrapper: %w", err)
+	} else if wrapper != nil {
+		opts = append(opts, keyvalue.WithEncryption(wrapper))
+	}
+
+	return opts, nil
+}
+
+// keyWrapper key encryption key from the vault service or
+// config, nil when encryption at rest is not configured
+func keyWrapper(cfg setup.KeyValue) (keyvalue.KeyWrapper, error) {
+	if cfg.VaultAddr != "" {
+		conn, err := protocol.NewConn(cfg.VaultAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to vault: %w", err)
+		}
+		return keyvalue.NewVaultKeyWrapper(pbvault.NewVaultServiceClient(conn), cfg.EncryptionKeyID), nil
+	}
+
+	if cfg.EncryptionKey == "" {
+		return nil, nil
+	}
+
+	current, err := parseKEK(cfg.EncryptionKeyID, cfg.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make([]keyvalue.KEK, 0)
+	for _, entry := range strings.Split(cfg.PreviousEncryptionKeys, ",") {
+		if entry == "" {
+			continue
+		}
+
+		id, key, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.New("previous encryption keys must be id:base64 pairs")
+		}
+
+		kek, err := parseKEK(id, key)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, kek)
+	}
+
+	return keyvalue.NewStaticKeyWrapper(current, previous...)
+}
+
+func parseKEK(id, key string) (keyvalue.KEK, error) {
+	kbytes, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return keyvalue.KEK{}, fmt.Errorf("failed to decode encryption key %s: %w", id, err)
+	}
+
+	return keyvalue.KEK{ID: id, Key: kbytes}, nil
+}
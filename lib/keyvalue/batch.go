@@ -0,0 +1,211 @@
+package keyvalue
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
+)
+
+type batchOp struct {
+	key    string
+	value  []byte
+	indice map[string]string
+	ttl    int64
+	delete bool
+}
+
+// Batch group of writes committed atomically
+// as a single write ahead log record
+type Batch struct {
+	ops []batchOp
+}
+
+// NewBatch return new empty batch
+func NewBatch() *Batch {
+	return &Batch{
+		ops: make([]batchOp, 0),
+	}
+}
+
+// Put queue record with secondary indice
+func (b *Batch) Put(key string, value []byte, indice map[string]string, ttl int64) {
+	b.ops = append(b.ops, batchOp{
+		key:    key,
+		value:  value,
+		indice: indice,
+		ttl:    ttl,
+	})
+}
+
+// Delete queue record removal
+func (b *Batch) Delete(key string) {
+	b.ops = append(b.ops, batchOp{
+		key:    key,
+		delete: true,
+	})
+}
+
+// Len number of queued writes
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+// Validate check every key and index of the batch can be
+// written, Write rejects the batch with the same error
+func (b *Batch) Validate() error {
+	for _, op := range b.ops {
+		if isReservedKey(op.key) {
+			return ErrInvalidKey
+		}
+
+		for name, v := range op.indice {
+			if name == "" || strings.Contains(name, separator) || strings.Contains(v, separator) {
+				return ErrInvalidIndex
+			}
+		}
+	}
+	return nil
+}
+
+// Write commit batch
+//
+// every entry of the batch shares one sequence number
+// so snapshots observe either all of it or none of it
+//
+// concurrent writers are serialised
+func (l *LSM) Write(b *Batch) error {
+
+	err := b.Validate()
+	if err != nil {
+		return err
+	}
+
+	if b.Len() == 0 {
+		return nil
+	}
+
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+
+	l.mu.RLock()
+	seq, flushErr := l.seq+1, l.flushErr
+	l.mu.RUnlock()
+
+	if flushErr != nil {
+		return fmt.Errorf("background flush: %w", flushErr)
+	}
+
+	entries, err := l.resolve(b, seq)
+	if err != nil {
+		return fmt.Errorf("lsm.resolve: %w", err)
+	}
+
+	if l.memtable.size.Load() >= l.flushToDisk {
+		err = l.rotate()
+		if err != nil {
+			return fmt.Errorf("lsm.rotate: %w", err)
+		}
+	}
+
+	batch := &pb.Batch{
+		Seq:     seq,
+		Entries: entries,
+	}
+
+	wal := l.memtable.wals[len(l.memtable.wals)-1]
+	err = wal.appendBatch(batch)
+	if err != nil {
+		return fmt.Errorf("wal.appendBatch: %w", err)
+	}
+
+	err = l.apply(batch)
+	if err != nil {
+		return fmt.Errorf("lsm.apply: %w", err)
+	}
+
+	l.publish(batch)
+
+	return nil
+}
+
+// resolve expand batch operations into record and index entries
+//
+// later writes to the same key in the batch replace earlier ones
+func (l *LSM) resolve(b *Batch, seq uint64) ([]*pb.KeyValue, error) {
+	var (
+		entries = make([]*pb.KeyValue, 0, len(b.ops))
+		pos     = make(map[string]int)
+		// indice of keys already written in this batch
+		pending = make(map[string]map[string]string)
+	)
+
+	add := func(entry *pb.KeyValue) {
+		entry.Seq = seq
+		if i, ok := pos[entry.Key]; ok {
+			entries[i] = entry
+			return
+		}
+		pos[entry.Key] = len(entries)
+		entries = append(entries, entry)
+	}
+
+	for _, op := range b.ops {
+
+		prev, ok := pending[op.key]
+		if !ok {
+			keyvalue, err := l.get(op.key, seq-1)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return nil, fmt.Errorf("lsm.get: %w", err)
+			}
+			prev = keyvalue.GetIndice()
+		}
+
+		if op.delete {
+			add(&pb.KeyValue{
+				Key:       op.key,
+				Tombstone: true,
+			})
+			for _, entry := range indexEntries(op.key, prev, nil) {
+				add(entry)
+			}
+			pending[op.key] = nil
+			continue
+		}
+
+		add(&pb.KeyValue{
+			Key:    op.key,
+			Value:  op.value,
+			Ttl:    op.ttl,
+			Indice: op.indice,
+		})
+		for _, entry := range indexEntries(op.key, prev, op.indice) {
+			add(entry)
+		}
+		pending[op.key] = op.indice
+	}
+
+	return entries, nil
+}
+
+// apply insert batch entries into the active memtable
+// then publish its sequence number to readers
+//
+// callers must hold writeMu
+func (l *LSM) apply(batch *pb.Batch) error {
+	for _, entry := range batch.Entries {
+		pbbytes, err := marshalKeyValue(entry)
+		if err != nil {
+			return fmt.Errorf("marshalKeyValue: %w", err)
+		}
+
+		l.memtable.put(entry.Key, entry.Seq, pbbytes)
+	}
+
+	l.mu.Lock()
+	l.seq = max(l.seq, batch.Seq)
+	l.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package keyvalue
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
+)
+
+const (
+	checkpointDirPerm  = 0o700
+	checkpointFilePerm = 0o600
+
+	// log segments may be retired by a running store
+	// while an out of process checkpoint copies them
+	checkpointAttempts = 3
+)
+
+// Checkpoint write a consistent copy of the store to dir
+//
+// sorted string tables are immutable and hard linked, write ahead
+// log segments are copied. writers and the flusher are paused for
+// the duration so the copy reflects a single sequence number
+func (l *LSM) Checkpoint(dir string) error {
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+
+	l.flushMu.Lock()
+	defer l.flushMu.Unlock()
+
+	return checkpoint(l.sstDir, l.manifest(), dir, l.keys)
+}
+
+// CheckpointDir write a consistent copy of the store in src to dst
+//
+// safe to use against the data directory of a running
+// store from another process
+func CheckpointDir(src, dst string) error {
+	var err error
+	for range checkpointAttempts {
+		var manifest *pb.Manifest
+		manifest, err = readManifest(src)
+		if err != nil {
+			return fmt.Errorf("failed to read manifest: %w", err)
+		}
+
+		err = checkpoint(src, manifest, dst, nil)
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		// a segment was retired mid copy, start over
+		// from the manifest that replaced it
+		_ = os.RemoveAll(dst)
+	}
+
+	return fmt.Errorf("checkpoint retries exhausted: %w", err)
+}
+
+func checkpoint(src string, manifest *pb.Manifest, dst string, keys *keyring) error {
+	dst = filepath.Clean(dst)
+
+	err := os.Mkdir(dst, checkpointDirPerm)
+	if err != nil {
+		return fmt.Errorf("os.Mkdir: %w", err)
+	}
+
+	out := &pb.Manifest{
+		NextSstable: manifest.NextSstable,
+		NextWal:     manifest.NextWal,
+		Keys:        manifest.Keys,
+		ActiveKey:   manifest.ActiveKey,
+	}
+
+	for _, sst := range manifest.Sstables {
+		file, err := linkFile(filepath.Join(src, sst.Name), filepath.Join(dst, sst.Name))
+		if err != nil {
+			return fmt.Errorf("failed to link %s: %w", sst.Name, err)
+		}
+		out.Sstables = append(out.Sstables, file)
+	}
+
+	for _, wal := range manifest.Wals {
+		file, err := copyFile(filepath.Join(src, wal.Name), filepath.Join(dst, wal.Name))
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %w", wal.Name, err)
+		}
+		out.Wals = append(out.Wals, file)
+	}
+
+	out.Seq, err = checkpointSeq(dst, out, keys)
+	if err != nil {
+		return fmt.Errorf("checkpointSeq: %w", err)
+	}
+
+	return writeManifest(dst, out)
+}
+
+// VerifyCheckpoint check every file of a checkpoint
+// against the checksums recorded in its manifest
+func VerifyCheckpoint(dir string) (*pb.Manifest, error) {
+	manifest, err := readManifest(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	for _, file := range append(manifest.Sstables, manifest.Wals...) {
+		size, sum, err := checksum(filepath.Join(dir, file.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to checksum %s: %w", file.Name, err)
+		}
+
+		if size != file.Size || !bytes.Equal(sum, file.Checksum) {
+			return nil, fmt.Errorf("%s: %w", file.Name, ErrChecksum)
+		}
+	}
+
+	for _, sst := range manifest.Sstables {
+		err = verifyFile(filepath.Join(dir, sst.Name))
+		if errors.Is(err, ErrNoKey) {
+			// sealed tables are covered by the file checksum
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", sst.Name, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// Restore verify the checkpoint in src and copy it
+// into dst which must not exist
+func Restore(src, dst string) error {
+	manifest, err := VerifyCheckpoint(src)
+	if err != nil {
+		return err
+	}
+
+	dst = filepath.Clean(dst)
+	err = os.Mkdir(dst, checkpointDirPerm)
+	if err != nil {
+		return fmt.Errorf("os.Mkdir: %w", err)
+	}
+
+	restored := proto.Clone(manifest).(*pb.Manifest)
+	for _, file := range append(restored.Sstables, restored.Wals...) {
+		copied, err := copyFile(filepath.Join(src, file.Name), filepath.Join(dst, file.Name))
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %w", file.Name, err)
+		}
+
+		if !bytes.Equal(copied.Checksum, file.Checksum) {
+			return fmt.Errorf("%s: %w", file.Name, ErrChecksum)
+		}
+	}
+
+	return writeManifest(dst, restored)
+}
+
+// checkpointSeq highest sequence number held by the checkpoint files
+//
+// keys may be nil, tables and log records of encrypted stores
+// carry their sequence numbers in plaintext
+func checkpointSeq(dir string, manifest *pb.Manifest, keys *keyring) (uint64, error) {
+	var seq uint64
+
+	for _, sst := range manifest.Sstables {
+		f, err := os.Open(filepath.Join(dir, sst.Name))
+		if err != nil {
+			return 0, fmt.Errorf("os.Open: %w", err)
+		}
+
+		n, err := tableSeq(f, keys)
+		_ = f.Close()
+		if err != nil {
+			return 0, fmt.Errorf("tableSeq: %w", err)
+		}
+		seq = max(seq, n)
+	}
+
+	for _, w := range manifest.Wals {
+		f, err := os.Open(filepath.Join(dir, w.Name))
+		if err != nil {
+			return 0, fmt.Errorf("os.Open: %w", err)
+		}
+
+		n, err := newWAL(f, w.Name, false, keys).maxSeq()
+		_ = f.Close()
+		if err != nil {
+			return 0, fmt.Errorf("wal.maxSeq: %w", err)
+		}
+		seq = max(seq, n)
+	}
+
+	return seq, nil
+}
+
+// linkFile hard link src to dst falling back to a copy
+// when both are not on the same file system
+func linkFile(src, dst string) (*pb.ManifestFile, error) {
+	err := os.Link(src, dst)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	} else if err != nil {
+		return copyFile(src, dst)
+	}
+
+	size, sum, err := checksum(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ManifestFile{
+		Name:     filepath.Base(dst),
+		Size:     size,
+		Checksum: sum,
+	}, nil
+}
+
+// copyFile copy src into a new file at dst
+//
+// only the bytes present when the copy starts are copied, a write
+// ahead log record torn by a concurrent append is dropped on replay
+func copyFile(src, dst string) (*pb.ManifestFile, error) {
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = in.Close() }()
+
+	stat, err := in.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("file.Stat: %w", err)
+	}
+
+	out, err := os.OpenFile(filepath.Clean(dst), os.O_CREATE|os.O_EXCL|os.O_WRONLY, checkpointFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile: %w", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, h), io.LimitReader(in, stat.Size()))
+	if err != nil {
+		return nil, fmt.Errorf("io.Copy: %w", err)
+	}
+
+	err = out.Sync()
+	if err != nil {
+		return nil, fmt.Errorf("file.Sync: %w", err)
+	}
+
+	return &pb.ManifestFile{
+		Name:     filepath.Base(dst),
+		Size:     size,
+		Checksum: h.Sum(nil),
+	}, nil
+}
+
+func checksum(path string) (int64, []byte, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return 0, nil, fmt.Errorf("os.Open: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, nil, fmt.Errorf("io.Copy: %w", err)
+	}
+
+	return size, h.Sum(nil), nil
+}
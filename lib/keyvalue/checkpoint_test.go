@@ -0,0 +1,77 @@
+package keyvalue_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+const (
+	checkpointTestDir = "testfiles_checkpoint"
+)
+
+func TestCheckpointRestore(t *testing.T) {
+	assert := assert.New(t)
+
+	err := os.MkdirAll(filepath.Join(checkpointTestDir, "data"), os.ModePerm)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		t.Fatalf("failed to create working directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(checkpointTestDir) }()
+
+	lsm, err := keyvalue.New(filepath.Join(checkpointTestDir, "data"))
+	if err != nil {
+		t.Fatalf("failed to create lsm: %v", err)
+	}
+
+	// enough writes to flush at least one table
+	for i := 0; i < 200; i++ {
+		assert.NoError(lsm.Put(fmt.Sprintf("key_%d", i), make([]byte, 64), nil, -1))
+	}
+	assert.NoError(lsm.Put("before", []byte("checkpoint"), nil, -1))
+
+	backup := filepath.Join(checkpointTestDir, "backup")
+	assert.NoError(lsm.Checkpoint(backup))
+
+	assert.NoError(lsm.Put("after", []byte("checkpoint"), nil, -1))
+	assert.NoError(lsm.Close())
+
+	_, err = keyvalue.VerifyCheckpoint(backup)
+	assert.NoError(err)
+
+	restored := filepath.Join(checkpointTestDir, "restored")
+	assert.NoError(keyvalue.Restore(backup, restored))
+
+	lsm, err = keyvalue.New(restored)
+	if err != nil {
+		t.Fatalf("failed to open restored lsm: %v", err)
+	}
+
+	vbytes, err := lsm.Get("before")
+	assert.NoError(err)
+	assert.Equal("checkpoint", string(vbytes))
+
+	_, err = lsm.Get("key_0")
+	assert.NoError(err)
+
+	_, err = lsm.Get("after")
+	assert.ErrorIs(err, keyvalue.ErrNotFound)
+	assert.NoError(lsm.Close())
+
+	t.Run("corrupt", func(t *testing.T) {
+		manifest, err := keyvalue.VerifyCheckpoint(backup)
+		assert.NoError(err)
+
+		fp := filepath.Join(backup, manifest.Wals[0].Name)
+		assert.NoError(os.WriteFile(fp, []byte("bit rot"), 0o600))
+
+		err = keyvalue.Restore(backup, filepath.Join(checkpointTestDir, "corrupt"))
+		assert.ErrorIs(err, keyvalue.ErrChecksum)
+	})
+}
@@ -0,0 +1,209 @@
+package keyvalue
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Compact merge every sorted string table into one
+//
+// versions no live snapshot can read are discarded, blocks
+// are rewritten with the configured compression and the active
+// data key so data sealed with a rotated key is re-encrypted
+func (l *LSM) Compact() error {
+	err := l.compactTables()
+	if err != nil {
+		return err
+	}
+
+	return l.pruneKeys()
+}
+
+func (l *LSM) compactTables() error {
+	l.flushMu.Lock()
+	defer l.flushMu.Unlock()
+
+	// tables only change while flushMu is held
+	l.mu.RLock()
+	tables, n := l.sstables[:len(l.sstables):len(l.sstables)], l.sstNum
+	horizon := l.horizon()
+	l.mu.RUnlock()
+
+	if len(tables) == 0 {
+		return nil
+	}
+
+	t, err := l.writeTable(n, func(w *tableWriter) error {
+		return mergeTables(w, tables, horizon)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to merge tables: %w", err)
+	}
+
+	l.mu.Lock()
+	l.sstables = []*table{t}
+	l.sstNum++
+	l.mu.Unlock()
+
+	err = l.saveManifest()
+	if err != nil {
+		return fmt.Errorf("lsm.saveManifest: %w", err)
+	}
+
+	// open views may still read the merged tables, their
+	// files are closed once the last view is released
+	for _, old := range tables {
+		err = os.Remove(old.f.Name())
+		if err != nil {
+			return fmt.Errorf("os.Remove: %w", err)
+		}
+
+		err = old.release()
+		if err != nil {
+			return fmt.Errorf("file.Close: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// mergeTables write the records of tables in key order, newest version first
+//
+// versions newer than horizon are kept for the snapshots reading
+// them, of the older ones only the newest is visible to any reader
+// and it is dropped as well when it is a tombstone, every table is
+// merged so no older version is left for it to shadow
+func mergeTables(w *tableWriter, tables []*table, horizon uint64) error {
+	cursors := make([]*sstableCursor, 0, len(tables))
+	for _, t := range tables {
+		c, err := newSSTableCursor(t)
+		if err != nil {
+			return fmt.Errorf("newSSTableCursor: %w", err)
+		}
+		cursors = append(cursors, c)
+	}
+
+	var (
+		last string
+		// shadowed versions of last at or below horizon
+		shadowed bool
+	)
+	for {
+		var next *sstableCursor
+		for _, c := range cursors {
+			if !c.valid() {
+				continue
+			}
+
+			if next == nil {
+				next = c
+				continue
+			}
+
+			// tables are ordered oldest first, newer
+			// tables win versions with the same sequence
+			cmp := strings.Compare(c.key(), next.key())
+			if cmp < 0 || cmp == 0 && c.seq() >= next.seq() {
+				next = c
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		if next.key() != last {
+			last, shadowed = next.key(), false
+		}
+
+		keep := true
+		if next.seq() <= horizon {
+			keep = !shadowed
+			shadowed = true
+
+			if keep {
+				keyvalue, err := unmarshalKeyValue(next.payload())
+				if err != nil {
+					return fmt.Errorf("unmarshalKeyValue: %w", err)
+				}
+				keep = !keyvalue.Tombstone
+			}
+		}
+
+		if keep {
+			err := w.add(next.key(), next.seq(), next.payload())
+			if err != nil {
+				return fmt.Errorf("tableWriter.add: %w", err)
+			}
+		}
+
+		err := next.advance()
+		if err != nil {
+			return fmt.Errorf("sstableCursor.advance: %w", err)
+		}
+	}
+}
+
+// RotateKey seal every following write with a new data key
+//
+// data sealed with previous keys stays readable
+// until it is re-encrypted by Compact
+func (l *LSM) RotateKey() error {
+	if l.keys == nil {
+		return ErrNotEncrypted
+	}
+
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+
+	err := l.keys.rotate()
+	if err != nil {
+		return fmt.Errorf("failed to rotate data key: %w", err)
+	}
+
+	return l.saveManifest()
+}
+
+// pruneKeys drop data keys no longer sealing any table or log segment
+func (l *LSM) pruneKeys() error {
+	if l.keys == nil {
+		return nil
+	}
+
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+
+	l.flushMu.Lock()
+	defer l.flushMu.Unlock()
+
+	used := make(map[uint32]struct{})
+
+	l.mu.RLock()
+	for _, t := range l.sstables {
+		if t.keyIDs == nil {
+			// opened from disk, keys unknown until compacted
+			l.mu.RUnlock()
+			return nil
+		}
+
+		for id := range t.keyIDs {
+			used[id] = struct{}{}
+		}
+	}
+
+	wals := l.retained[:len(l.retained):len(l.retained)]
+	for _, mt := range append(l.immutable[:len(l.immutable):len(l.immutable)], l.memtable) {
+		wals = append(wals, mt.wals...)
+	}
+	for _, wal := range wals {
+		for id := range wal.keyIDs {
+			used[id] = struct{}{}
+		}
+	}
+	l.mu.RUnlock()
+
+	l.keys.retain(used)
+
+	return l.saveManifest()
+}
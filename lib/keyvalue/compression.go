@@ -0,0 +1,81 @@
+package keyvalue
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// zstdCodec encoder and decoder are safe for concurrent
+// use with EncodeAll and DecodeAll and shared by every store
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// compress block returning the compression actually applied
+//
+// blocks that do not shrink are stored uncompressed
+func compress(c Compression, block []byte) (Compression, []byte, error) {
+	var buf []byte
+	switch c {
+	case NoCompression:
+	case Snappy:
+		buf = snappy.Encode(nil, block)
+	case Zstd:
+		enc, _, err := zstdCodec()
+		if err != nil {
+			return NoCompression, nil, fmt.Errorf("zstd: %w", err)
+		}
+		buf = enc.EncodeAll(block, nil)
+	default:
+		return NoCompression, nil, fmt.Errorf("unsupported compression %s", c)
+	}
+
+	if buf == nil || len(buf) >= len(block) {
+		return NoCompression, append([]byte(nil), block...), nil
+	}
+
+	return c, buf, nil
+}
+
+func decompress(c Compression, block []byte) ([]byte, error) {
+	switch c {
+	case NoCompression:
+		return block, nil
+	case Snappy:
+		buf, err := snappy.Decode(nil, block)
+		if err != nil {
+			return nil, fmt.Errorf("snappy.Decode: %w", err)
+		}
+		return buf, nil
+	case Zstd:
+		_, dec, err := zstdCodec()
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+
+		buf, err := dec.DecodeAll(block, nil)
+		if err != nil {
+			return nil, fmt.Errorf("zstd.DecodeAll: %w", err)
+		}
+		return buf, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %s", c)
+	}
+}
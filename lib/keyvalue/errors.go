@@ -4,4 +4,25 @@ import "errors"
 
 var (
 	ErrNotFound = errors.New("key not found")
-)
\ No newline at end of file
+
+	// ErrInvalidKey key uses the reserved prefix
+	ErrInvalidKey = errors.New("invalid key")
+	// ErrInvalidIndex index name or value contains the reserved separator
+	ErrInvalidIndex = errors.New("invalid index")
+	// ErrChecksum file contents do not match the recorded checksum
+	ErrChecksum = errors.New("checksum mismatch")
+	// ErrCorrupt table records are not in key order
+	ErrCorrupt = errors.New("corrupt table")
+	// ErrNoKey data or key encryption key is not available
+	ErrNoKey = errors.New("encryption key not found")
+	// ErrEncrypted store is encrypted and opened without a key wrapper
+	ErrEncrypted = errors.New("store is encrypted")
+	// ErrNotEncrypted store was opened without encryption
+	ErrNotEncrypted = errors.New("store is not encrypted")
+	// ErrWatcherLagged watcher fell too far behind the writers
+	ErrWatcherLagged = errors.New("watcher lagged")
+	// ErrTruncated changes from the requested sequence number are no longer retained
+	ErrTruncated = errors.New("sequence number no longer retained")
+	// ErrClosed store is closed
+	ErrClosed = errors.New("store closed")
+)
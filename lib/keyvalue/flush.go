@@ -0,0 +1,240 @@
+package keyvalue
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// rotate swap the active memtable for an empty one with a
+// new write ahead log segment and hand it to the flusher
+//
+// callers must hold writeMu
+func (l *LSM) rotate() error {
+	wal, err := l.openWAL()
+	if err != nil {
+		return fmt.Errorf("lsm.openWAL: %w", err)
+	}
+
+	mt := newMemTable()
+	mt.wals = append(mt.wals, wal)
+
+	l.mu.Lock()
+	l.immutable = append(l.immutable, l.memtable)
+	l.memtable = mt
+	l.mu.Unlock()
+
+	err = l.saveManifest()
+	if err != nil {
+		return fmt.Errorf("lsm.saveManifest: %w", err)
+	}
+
+	select {
+	case l.flushCh <- struct{}{}:
+	default:
+		// flusher already signalled
+	}
+
+	return nil
+}
+
+// flusher background loop writing immutable memtables to disk
+func (l *LSM) flusher() {
+	defer l.wg.Done()
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-l.flushCh:
+			for l.flushImmutable() {
+			}
+			l.maybeCompact()
+		}
+	}
+}
+
+// maybeCompact merge the sorted string tables once
+// the compaction trigger is reached
+func (l *LSM) maybeCompact() {
+	l.mu.RLock()
+	tables, failed := len(l.sstables), l.flushErr != nil
+	l.mu.RUnlock()
+
+	if l.compactAt == 0 || tables < l.compactAt || failed {
+		return
+	}
+
+	select {
+	case <-l.done:
+		return
+	default:
+	}
+
+	err := l.Compact()
+	if err != nil {
+		l.setFlushErr(fmt.Errorf("compaction: %w", err))
+	}
+}
+
+// flushImmutable write the oldest immutable memtable to a
+// sorted string table, reports whether one was flushed
+//
+// no lock is held while writing so readers are never blocked
+func (l *LSM) flushImmutable() bool {
+	l.flushMu.Lock()
+	defer l.flushMu.Unlock()
+
+	l.mu.RLock()
+	if len(l.immutable) == 0 || l.flushErr != nil {
+		l.mu.RUnlock()
+		return false
+	}
+	mt, n := l.immutable[0], l.sstNum
+	l.mu.RUnlock()
+
+	f, err := l.compact(mt, n)
+	if err != nil {
+		l.setFlushErr(err)
+		return false
+	}
+
+	for _, wal := range mt.wals {
+		if err := wal.f.Close(); err != nil {
+			l.setFlushErr(fmt.Errorf("file.Close: %w", err))
+			return false
+		}
+	}
+
+	// publish table and retire memtable together
+	// so every version is visible exactly once
+	l.mu.Lock()
+	l.sstables = append(l.sstables, f)
+	l.immutable = l.immutable[1:]
+	l.sstNum++
+	l.retained = append(l.retained, mt.wals...)
+	expired := l.retained[:max(len(l.retained)-l.retention, 0)]
+	l.retained = l.retained[len(expired):]
+	l.mu.Unlock()
+
+	// log segments are only removed once the manifest
+	// no longer references them
+	err = l.saveManifest()
+	if err != nil {
+		l.setFlushErr(err)
+		return false
+	}
+
+	for _, wal := range expired {
+		if err := wal.remove(); err != nil {
+			l.setFlushErr(err)
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l *LSM) setFlushErr(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flushErr = err
+}
+
+// compact flush memtable to sorted string table number n
+func (l *LSM) compact(mt *memtable, n uint64) (*table, error) {
+	return l.writeTable(n, func(w *tableWriter) error {
+		it := mt.newMemTableIterator()
+
+		for it.hasNext() {
+
+			n := it.next()
+			err := w.add(n.key, n.seq, n.payload)
+			if err != nil {
+				return fmt.Errorf("tableWriter.add: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// writeTable create sorted string table number n from the records added by fn
+func (l *LSM) writeTable(n uint64, fn func(*tableWriter) error) (*table, error) {
+
+	fp := filepath.Join(l.sstDir, fmt.Sprintf("sstable_%d.data", n))
+	f, err := os.OpenFile(fp, os.O_CREATE|os.O_RDWR|os.O_TRUNC|os.O_APPEND, dataFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile: %w", err)
+	}
+
+	w := newTableWriter(f, l.blockSize, l.compression, l.keys)
+	err = fn(w)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	err = w.finish()
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("tableWriter.finish: %w", err)
+	}
+
+	t, err := openTable(f, l.keys)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("openTable: %w", err)
+	}
+	t.keyIDs = w.keyIDs
+
+	return t, nil
+}
+
+// Close stop the background flusher, persist pending
+// immutable memtables and release file handles
+//
+// only the first call closes the store, later calls return ErrClosed
+func (l *LSM) Close() error {
+	err := ErrClosed
+	l.closeOnce.Do(func() {
+		err = l.close()
+	})
+	return err
+}
+
+func (l *LSM) close() error {
+	// the flusher takes writeMu while compacting
+	close(l.done)
+	l.wg.Wait()
+
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+
+	l.closeWatchers()
+
+	for l.flushImmutable() {
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.flushErr != nil {
+		return fmt.Errorf("background flush: %w", l.flushErr)
+	}
+
+	for _, wal := range l.memtable.wals {
+		_ = wal.f.Close()
+	}
+
+	// iterators neither exhausted nor closed keep
+	// their tables open until they are released
+	for _, t := range l.sstables {
+		err := t.release()
+		if err != nil {
+			return fmt.Errorf("file.Close: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package keyvalue
+
+import (
+	"strings"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
+)
+
+// secondary index entries share the primary keyspace
+// under a reserved prefix so they are flushed and compacted
+// in the same sorted string table as the record they point to
+//
+// \x00idx\x00<name>\x00<value>\x00<primary key>
+const (
+	reservedPrefix = "\x00"
+	indexPrefix    = reservedPrefix + "idx" + separator
+	separator      = "\x00"
+	// firstUserKey lower bound of keys outside the reserved prefix
+	firstUserKey = "\x01"
+)
+
+func isReservedKey(key string) bool {
+	return strings.HasPrefix(key, reservedPrefix)
+}
+
+func indexValuePrefix(name, value string) string {
+	return indexPrefix + name + separator + value + separator
+}
+
+func indexKey(name, value, key string) string {
+	return indexValuePrefix(name, value) + key
+}
+
+// indexEntries returns the index records required to move
+// a primary key from its previous indice to the next indice
+func indexEntries(key string, prev, next map[string]string) []*pb.KeyValue {
+	entries := make([]*pb.KeyValue, 0, len(prev)+len(next))
+
+	for name, value := range prev {
+		if v, ok := next[name]; ok && v == value {
+			continue
+		}
+		entries = append(entries, &pb.KeyValue{
+			Key:       indexKey(name, value, key),
+			Tombstone: true,
+		})
+	}
+
+	for name, value := range next {
+		entries = append(entries, &pb.KeyValue{
+			Key: indexKey(name, value, key),
+		})
+	}
+
+	return entries
+}
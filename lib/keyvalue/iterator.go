@@ -1,37 +1,140 @@
 package keyvalue
 
 import (
-	"bufio"
-	"os"
+	"errors"
+	"strings"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
 )
 
-// Iterator
+// cursor sorted source of encoded key values
+type cursor interface {
+	valid() bool
+	key() string
+	seq() uint64
+	payload() []byte
+	advance() error
+}
+
+type memtableCursor struct {
+	it *memtableIterator
+	n  *node
+	ok bool
+}
+
+func newMemTableCursor(it *memtableIterator) *memtableCursor {
+	c := &memtableCursor{it: it}
+	_ = c.advance()
+	return c
+}
+
+func (c *memtableCursor) valid() bool {
+	return c.ok
+}
+
+func (c *memtableCursor) key() string {
+	return c.n.key
+}
+
+func (c *memtableCursor) seq() uint64 {
+	return c.n.seq
+}
+
+func (c *memtableCursor) payload() []byte {
+	return c.n.payload
+}
+
+func (c *memtableCursor) advance() error {
+	c.ok = c.it.hasNext()
+	if c.ok {
+		c.n = c.it.next()
+	}
+	return nil
+}
+
+// iterator merges cursors returning the most
+// recent version of each key visible at seq
 type iterator struct {
-	lsm            *LSM
-	currentIndex   int
-	currentSSTable *os.File
-	scanner        *bufio.Scanner
+	cursors []cursor
+	prefix  string
+	// end exclusive upper bound, empty when unbounded
+	end  string
+	seq  uint64
+	skip func(key string) bool
+
+	// view released once the iterator is exhausted or closed
+	view view
+
+	current *pb.KeyValue
+	err     error
+	done    bool
 }
 
+func newIterator(cursors []cursor, prefix string, seq uint64, skip func(string) bool) *iterator {
+	return &iterator{
+		cursors: cursors,
+		prefix:  prefix,
+		seq:     seq,
+		skip:    skip,
+	}
+}
 
 // HasNext
 func (it *iterator) HasNext() bool {
+	for !it.done {
+		lowest := -1
+		for i, c := range it.cursors {
+			if !c.valid() {
+				continue
+			}
+			if lowest == -1 || strings.Compare(c.key(), it.cursors[lowest].key()) < 0 {
+				lowest = i
+			}
+		}
 
-	n := it.scanner.Scan()
-	if n && it.currentIndex != len(it.lsm.sstables) {
-		return true
-	}
-
-	// scanner will not return EOF
-	// so if unable to scan then
-	// open new scanner with next sstable in slice
-	if !n {
-		if it.currentIndex+1 > len(it.lsm.sstables) {
+		if lowest == -1 || !strings.HasPrefix(it.cursors[lowest].key(), it.prefix) ||
+			(it.end != "" && it.cursors[lowest].key() >= it.end) {
+			it.finish()
 			return false
 		}
 
-		it.currentIndex += 1
-		it.scanner = bufio.NewScanner(it.lsm.sstables[it.currentIndex])
+		key := it.cursors[lowest].key()
+
+		// pick the newest visible version across cursors
+		// and move every cursor past the key
+		var (
+			payload []byte
+			best    uint64
+			found   bool
+		)
+		for _, c := range it.cursors[lowest:] {
+			for c.valid() && c.key() == key {
+				if c.seq() <= it.seq && (!found || c.seq() > best) {
+					payload = c.payload()
+					best = c.seq()
+					found = true
+				}
+
+				if err := c.advance(); err != nil {
+					return it.fail(err)
+				}
+			}
+		}
+
+		if !found || (it.skip != nil && it.skip(key)) {
+			continue
+		}
+
+		keyvalue, err := unmarshalKeyValue(payload)
+		if err != nil {
+			return it.fail(err)
+		}
+
+		if keyvalue.Tombstone {
+			continue
+		}
+
+		it.current = keyvalue
 		return true
 	}
 
@@ -40,19 +143,98 @@ func (it *iterator) HasNext() bool {
 
 // Next
 func (it *iterator) Next() (key string, value []byte, err error) {
+	if it.err != nil {
+		return "", nil, it.err
+	}
 
-	keyvalue, err := unmarshalKeyValue(it.scanner.Bytes())
-	if err != nil {
-		return
+	if it.current == nil {
+		return "", nil, errors.New("iterator exhausted")
 	}
 
-	err = it.scanner.Err()
-	if err != nil {
+	return it.current.Key, it.current.Value, nil
+}
+
+func (it *iterator) fail(err error) bool {
+	it.err = err
+	it.finish()
+	// report true so the caller receives the error from Next
+	return true
+}
+
+// Close release the tables of the iterator, later
+// calls to HasNext report no more records
+func (it *iterator) Close() {
+	it.finish()
+}
+
+// finish stop the iterator and release its view
+func (it *iterator) finish() {
+	if it.done {
 		return
 	}
+	it.done = true
+	it.view.release()
+}
+
+// indexIterator resolves secondary index entries
+// to the primary records they point to
+type indexIterator struct {
+	// view primary records are read from, the current view
+	// may no longer hold the versions visible at seq
+	view        view
+	it          *iterator
+	seq         uint64
+	name, value string
+
+	current *pb.KeyValue
+	err     error
+}
+
+// HasNext
+func (it *indexIterator) HasNext() bool {
+	for it.err == nil && it.it.HasNext() {
+		idxKey, _, err := it.it.Next()
+		if err != nil {
+			it.err = err
+			return true
+		}
+
+		keyvalue, err := it.view.get(strings.TrimPrefix(idxKey, it.it.prefix), it.seq)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		} else if err != nil {
+			it.err = err
+			it.it.finish()
+			return true
+		}
+
+		// guard against index entries that
+		// no longer match the primary record
+		if v, ok := keyvalue.Indice[it.name]; !ok || v != it.value {
+			continue
+		}
+
+		it.current = keyvalue
+		return true
+	}
 
-	key = keyvalue.Key
-	value = keyvalue.Value
+	return false
+}
 
-	return
-}
\ No newline at end of file
+// Next
+func (it *indexIterator) Next() (key string, value []byte, err error) {
+	if it.err != nil {
+		return "", nil, it.err
+	}
+
+	if it.current == nil {
+		return "", nil, errors.New("iterator exhausted")
+	}
+
+	return it.current.Key, it.current.Value, nil
+}
+
+// Close release the tables of the iterator
+func (it *indexIterator) Close() {
+	it.it.finish()
+}
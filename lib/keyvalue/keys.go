@@ -0,0 +1,285 @@
+package keyvalue
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
+)
+
+// envelope encryption
+//
+// blocks and log records are sealed with AES-256-GCM under a data
+// encryption key. data keys are stored in the manifest wrapped by a
+// key encryption key that never touches the disk
+//
+// | data key id uint32 | nonce | ciphertext |
+const (
+	dataKeySize = 32
+	keyIDLen    = 4
+)
+
+// KeyWrapper wraps data encryption keys with a key encryption key
+type KeyWrapper interface {
+	// KeyID key encryption key used to wrap new data keys
+	KeyID() string
+	// Wrap encrypt data key returning the id of the key encryption key used
+	Wrap(dek []byte) (string, []byte, error)
+	// Unwrap decrypt data key wrapped by key encryption key id
+	Unwrap(id string, wrapped []byte) ([]byte, error)
+}
+
+// KEK key encryption key
+type KEK struct {
+	ID  string
+	Key []byte
+}
+
+// staticKeyWrapper wraps data keys with locally supplied key encryption keys
+type staticKeyWrapper struct {
+	current KEK
+	kek     map[string]cipher.AEAD
+}
+
+// NewStaticKeyWrapper wrap data keys with current, previous keys
+// are only used to unwrap data keys written before a rotation
+func NewStaticKeyWrapper(current KEK, previous ...KEK) (KeyWrapper, error) {
+	w := &staticKeyWrapper{
+		current: current,
+		kek:     make(map[string]cipher.AEAD),
+	}
+
+	for _, k := range append(previous, current) {
+		aead, err := newAEAD(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("key encryption key %s: %w", k.ID, err)
+		}
+		w.kek[k.ID] = aead
+	}
+
+	return w, nil
+}
+
+// KeyID
+func (w *staticKeyWrapper) KeyID() string {
+	return w.current.ID
+}
+
+// Wrap
+func (w *staticKeyWrapper) Wrap(dek []byte) (string, []byte, error) {
+	aead := w.kek[w.current.ID]
+	return w.current.ID, aeadSeal(aead, nil, dek, []byte(w.current.ID)), nil
+}
+
+// Unwrap
+func (w *staticKeyWrapper) Unwrap(id string, wrapped []byte) ([]byte, error) {
+	aead, ok := w.kek[id]
+	if !ok {
+		return nil, fmt.Errorf("key encryption key %s: %w", id, ErrNoKey)
+	}
+
+	return aeadOpen(aead, wrapped, []byte(id))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cipher.NewGCM: %w", err)
+	}
+
+	return aead, nil
+}
+
+// aeadSeal append nonce and ciphertext of plaintext to dst
+func aeadSeal(aead cipher.AEAD, dst, plaintext, aad []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	_, _ = rand.Read(nonce)
+
+	dst = append(dst, nonce...)
+	return aead.Seal(dst, nonce, plaintext, aad)
+}
+
+func aeadOpen(aead cipher.AEAD, buf, aad []byte) ([]byte, error) {
+	if len(buf) < aead.NonceSize() {
+		return nil, fmt.Errorf("short ciphertext: %w", ErrChecksum)
+	}
+
+	plaintext, err := aead.Open(nil, buf[:aead.NonceSize()], buf[aead.NonceSize():], aad)
+	if err != nil {
+		return nil, fmt.Errorf("aead.Open: %w", ErrChecksum)
+	}
+
+	return plaintext, nil
+}
+
+// keyring unwrapped data encryption keys of a store
+type keyring struct {
+	wrapper KeyWrapper
+
+	mu      sync.RWMutex
+	active  uint32
+	aead    map[uint32]cipher.AEAD
+	wrapped map[uint32]*pb.DataKey
+}
+
+// loadKeyring unwrap the data keys listed in the manifest
+//
+// data keys wrapped by a retired key encryption key are
+// rewrapped with the current one, a store without keys
+// gets its first data key
+func loadKeyring(wrapper KeyWrapper, manifest *pb.Manifest) (*keyring, error) {
+	k := &keyring{
+		wrapper: wrapper,
+		active:  manifest.ActiveKey,
+		aead:    make(map[uint32]cipher.AEAD),
+		wrapped: make(map[uint32]*pb.DataKey),
+	}
+
+	for _, dk := range manifest.Keys {
+		dek, err := wrapper.Unwrap(dk.KekId, dk.Wrapped)
+		if err != nil {
+			return nil, fmt.Errorf("unwrap data key %d: %w", dk.Id, err)
+		}
+
+		if dk.KekId != wrapper.KeyID() {
+			err = k.add(dk.Id, dek)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		aead, err := newAEAD(dek)
+		if err != nil {
+			return nil, err
+		}
+		k.aead[dk.Id] = aead
+		k.wrapped[dk.Id] = dk
+	}
+
+	if len(manifest.Keys) == 0 {
+		return k, k.rotate()
+	}
+
+	return k, nil
+}
+
+// add wrap dek with the current key encryption key
+func (k *keyring) add(id uint32, dek []byte) error {
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return err
+	}
+
+	kekID, wrapped, err := k.wrapper.Wrap(dek)
+	if err != nil {
+		return fmt.Errorf("wrap data key %d: %w", id, err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.aead[id] = aead
+	k.wrapped[id] = &pb.DataKey{
+		Id:      id,
+		KekId:   kekID,
+		Wrapped: wrapped,
+	}
+
+	return nil
+}
+
+// rotate generate a new data key used for every following write
+func (k *keyring) rotate() error {
+	dek := make([]byte, dataKeySize)
+	_, err := rand.Read(dek)
+	if err != nil {
+		return fmt.Errorf("rand.Read: %w", err)
+	}
+
+	k.mu.RLock()
+	id := k.active + 1
+	k.mu.RUnlock()
+
+	err = k.add(id, dek)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.active = id
+	k.mu.Unlock()
+
+	return nil
+}
+
+// retain drop data keys not in use
+func (k *keyring) retain(used map[uint32]struct{}) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for id := range k.aead {
+		if _, ok := used[id]; !ok && id != k.active {
+			delete(k.aead, id)
+			delete(k.wrapped, id)
+		}
+	}
+}
+
+// encrypt seal plaintext with the active data key
+func (k *keyring) encrypt(plaintext, aad []byte) (uint32, []byte) {
+	k.mu.RLock()
+	id, aead := k.active, k.aead[k.active]
+	k.mu.RUnlock()
+
+	buf := binary.BigEndian.AppendUint32(make([]byte, 0, keyIDLen), id)
+	return id, aeadSeal(aead, buf, plaintext, aad)
+}
+
+// decrypt open ciphertext sealed by encrypt
+func (k *keyring) decrypt(buf, aad []byte) ([]byte, error) {
+	if k == nil {
+		return nil, ErrNoKey
+	}
+
+	if len(buf) < keyIDLen {
+		return nil, fmt.Errorf("short ciphertext: %w", ErrChecksum)
+	}
+
+	id := binary.BigEndian.Uint32(buf)
+
+	k.mu.RLock()
+	aead, ok := k.aead[id]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("data key %d: %w", id, ErrNoKey)
+	}
+
+	return aeadOpen(aead, buf[keyIDLen:], aad)
+}
+
+// manifest wrapped keys to persist
+func (k *keyring) manifest(m *pb.Manifest) {
+	if k == nil {
+		return
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	m.ActiveKey = k.active
+	for id := uint32(0); id <= k.active; id++ {
+		if dk, ok := k.wrapped[id]; ok {
+			m.Keys = append(m.Keys, dk)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package keyvalue_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+const (
+	encryptionTestDir = "testfiles_encryption"
+	secret            = "top secret audit transaction"
+)
+
+func newKEK(t *testing.T, id string) keyvalue.KEK {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return keyvalue.KEK{ID: id, Key: key}
+}
+
+func newKeyWrapper(t *testing.T, current keyvalue.KEK, previous ...keyvalue.KEK) keyvalue.KeyWrapper {
+	w, err := keyvalue.NewStaticKeyWrapper(current, previous...)
+	if err != nil {
+		t.Fatalf("failed to create key wrapper: %v", err)
+	}
+	return w
+}
+
+func TestEncryption(t *testing.T) {
+	assert := assert.New(t)
+
+	err := os.Mkdir(encryptionTestDir, os.ModePerm)
+	if err != nil {
+		t.Fatalf("failed to create working directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(encryptionTestDir) }()
+
+	v1 := newKEK(t, "v1")
+	opts := []keyvalue.Option{
+		keyvalue.WithEncryption(newKeyWrapper(t, v1)),
+		keyvalue.WithMemtableSize(1024),
+	}
+
+	lsm, err := keyvalue.New(encryptionTestDir, opts...)
+	if err != nil {
+		t.Fatalf("failed to create lsm: %v", err)
+	}
+
+	for i := range 100 {
+		assert.NoError(lsm.Put(fmt.Sprintf("key_%03d", i), []byte(secret), nil, -1))
+	}
+	assert.NoError(lsm.Close())
+
+	entries, err := os.ReadDir(encryptionTestDir)
+	assert.NoError(err)
+	for _, entry := range entries {
+		buf, err := os.ReadFile(filepath.Join(encryptionTestDir, entry.Name()))
+		assert.NoError(err)
+		assert.False(bytes.Contains(buf, []byte(secret)), "%s holds plaintext", entry.Name())
+		assert.False(bytes.Contains(buf, []byte("key_042")), "%s holds plaintext keys", entry.Name())
+
+		stat, err := entry.Info()
+		assert.NoError(err)
+		assert.Equal(os.FileMode(0o600), stat.Mode().Perm(), entry.Name())
+	}
+
+	t.Run("without key", func(t *testing.T) {
+		_, err := keyvalue.New(encryptionTestDir)
+		if !errors.Is(err, keyvalue.ErrEncrypted) {
+			t.Fatalf("expected encrypted store error, got %v", err)
+		}
+	})
+
+	// key encryption key rotation rewraps data keys on open
+	v2 := newKEK(t, "v2")
+	lsm, err = keyvalue.New(encryptionTestDir,
+		keyvalue.WithEncryption(newKeyWrapper(t, v2, v1)),
+		keyvalue.WithMemtableSize(1024),
+	)
+	if err != nil {
+		t.Fatalf("failed to open lsm with rotated key encryption key: %v", err)
+	}
+
+	// data key rotation re-encrypts tables on compaction
+	assert.NoError(lsm.RotateKey())
+	assert.NoError(lsm.Put("after", []byte("rotation"), nil, -1))
+	assert.NoError(lsm.Compact())
+	assert.NoError(lsm.Verify())
+
+	vbytes, err := lsm.Get("key_042")
+	assert.NoError(err)
+	assert.Equal(secret, string(vbytes))
+	assert.NoError(lsm.Close())
+
+	lsm, err = keyvalue.New(encryptionTestDir, keyvalue.WithEncryption(newKeyWrapper(t, v2)))
+	if err != nil {
+		t.Fatalf("failed to open lsm without retired key encryption key: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	vbytes, err = lsm.Get("after")
+	assert.NoError(err)
+	assert.Equal("rotation", string(vbytes))
+}
@@ -1,199 +1,474 @@
 package keyvalue
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 
 	"google.golang.org/protobuf/proto"
 
 	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
 )
 
+const (
+	dataFilePerm = 0o600
+	dataDirPerm  = 0o700
+)
+
+// Store key value store
 type Store interface {
 	Get(string) ([]byte, error)
 	Put(string, []byte, map[string]string, int64) error
+	Delete(string) error
+	Write(*Batch) error
+	GetByIndex(string, string) Iterator
+	GetByIndexRange(string, string, string, string) Iterator
 	Iterator() Iterator
+	Range(string, string) Iterator
 }
 
-type Iterator interface{}
+// Iterator key value store iterator
+//
+// an iterator holds the tables it reads open until it is
+// exhausted or closed, callers stopping early must Close it
+type Iterator interface {
+	HasNext() bool
+	Next() (string, []byte, error)
+	Close()
+}
 
-// LSM
+// LSM log structured merge tree
+//
+// safe for concurrent use, writes are serialised while
+// readers work from an immutable view of the tables and
+// never wait on memtables being flushed to disk
 type LSM struct {
-	indice      []*os.File
 	sstDir      string
-	sstables    []*os.File
-	memtable    *memtable
-	wal         *WAL
 	flushToDisk int64
+	sync        bool
+	compression Compression
+	blockSize   int
+	retention   int
+	compactAt   int
+	wrapper     KeyWrapper
+	// keys data encryption keys, nil when unencrypted
+	keys *keyring
+
+	// writeMu serialises writers
+	writeMu sync.Mutex
+	// flushMu held while a memtable is flushed
+	flushMu sync.Mutex
+	// manifestMu serialises manifest updates
+	manifestMu sync.Mutex
+
+	// mu guards the fields below and is only held
+	// long enough to swap memtables or publish tables
+	mu        sync.RWMutex
+	memtable  *memtable
+	immutable []*memtable // oldest first, waiting to be flushed
+	retained  []*WAL      // oldest first, flushed and kept for subscribers
+	sstables  []*table
+	seq       uint64 // last committed sequence number
+	sstNum    uint64
+	walNum    uint64
+	flushErr  error
+	// snapshots live snapshot count by sequence number
+	snapshots map[uint64]int
+
+	// watchMu guards watchers
+	watchMu  sync.Mutex
+	watchers map[*Watcher]struct{}
+
+	flushCh   chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
 }
 
 var _ Store = (*LSM)(nil)
 
-func New(dir string) (*LSM, error) {
+// New open or create store in dir
+func New(dir string, opts ...Option) (*LSM, error) {
 	filePath := filepath.Clean(dir)
 	lsm := &LSM{
-		indice:      make([]*os.File, 0),
-		sstables:    make([]*os.File, 0),
+		sstables:    make([]*table, 0),
+		immutable:   make([]*memtable, 0),
+		retained:    make([]*WAL, 0),
+		snapshots:   make(map[uint64]int),
 		memtable:    newMemTable(),
 		sstDir:      filePath,
-		flushToDisk: 10000,
+		flushToDisk: defaultMemtableSize,
+		sync:        true,
+		compression: NoCompression,
+		blockSize:   defaultBlockSize,
+		watchers:    make(map[*Watcher]struct{}),
+		flushCh:     make(chan struct{}, 1),
+		done:        make(chan struct{}),
 	}
 
-	entries, err := os.ReadDir(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("os.ReadDir: %v", err)
+	for _, opt := range opts {
+		opt(lsm)
 	}
 
-	if len(entries) == 0 {
+	manifest, err := readManifest(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		manifest, err = scanManifest(filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
 
-		// create required files
+	switch {
+	case lsm.wrapper != nil:
+		lsm.keys, err = loadKeyring(lsm.wrapper, manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load data keys: %w", err)
+		}
+	case len(manifest.Keys) > 0:
+		return nil, ErrEncrypted
+	}
 
-		sstFilePath := filepath.Join(filePath, "sstable_0.data")
-		f, err := os.OpenFile(sstFilePath, os.O_CREATE|os.O_APPEND|os.O_RDWR, os.ModePerm)
+	for _, sst := range manifest.Sstables {
+		fp := filepath.Join(filePath, sst.Name)
+		f, err := os.OpenFile(fp, os.O_RDONLY, 0)
 		if err != nil {
-			return nil, fmt.Errorf("os.OpenFile: %v", err)
+			return nil, fmt.Errorf("failed to open %s file %w", fp, err)
 		}
-		lsm.sstables = append(lsm.sstables, f)
 
-		idxFilePath := filepath.Join(filePath, "index_0.log")
-		f, err = os.OpenFile(idxFilePath, os.O_CREATE|os.O_APPEND|os.O_RDWR, os.ModePerm)
+		t, err := openTable(f, lsm.keys)
 		if err != nil {
-			return nil, fmt.Errorf("os.OpenFile: %v", err)
+			return nil, fmt.Errorf("failed to read %s table %w", fp, err)
 		}
-		lsm.indice = append(lsm.indice, f)
+		lsm.sstables = append(lsm.sstables, t)
+	}
 
-		walFilePath := filepath.Join(filePath, "wal.log")
-		f, err = os.OpenFile(walFilePath, os.O_CREATE|os.O_APPEND|os.O_RDWR, os.ModePerm)
+	for _, w := range manifest.Wals {
+		fp := filepath.Join(filePath, w.Name)
+		f, err := os.OpenFile(fp, os.O_CREATE|os.O_APPEND|os.O_RDWR, dataFilePerm)
 		if err != nil {
-			return nil, fmt.Errorf("os.OpenFile: %v", err)
+			return nil, fmt.Errorf("failed to open %s file %w", fp, err)
 		}
-		lsm.wal = newWAL(f)
+		lsm.memtable.wals = append(lsm.memtable.wals, newWAL(f, fp, lsm.sync, lsm.keys))
+	}
 
-		return lsm, nil
+	for _, w := range manifest.Retained {
+		wal, err := openRetained(filepath.Join(filePath, w.Name), lsm.keys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open retained %s: %w", w.Name, err)
+		}
+		lsm.retained = append(lsm.retained, wal)
 	}
 
-	for _, entry := range entries {
+	lsm.sstNum = manifest.NextSstable
+	lsm.walNum = manifest.NextWal
 
-		if entry.IsDir() {
-			continue
+	err = lsm.recover()
+	if err != nil {
+		return nil, fmt.Errorf("lsm.recover: %w", err)
+	}
+
+	if len(lsm.memtable.wals) == 0 {
+		wal, err := lsm.openWAL()
+		if err != nil {
+			return nil, fmt.Errorf("lsm.openWAL: %w", err)
 		}
+		lsm.memtable.wals = append(lsm.memtable.wals, wal)
+	}
 
-		if strings.HasPrefix("sstable_", entry.Name()) {
+	err = lsm.saveManifest()
+	if err != nil {
+		return nil, fmt.Errorf("lsm.saveManifest: %w", err)
+	}
 
-			filePath := filepath.Join(filePath, entry.Name())
-			f, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_RDWR, os.ModePerm)
-			if err != nil {
-				return nil, fmt.Errorf("failed to open %s file %v", filePath, err)
-			}
-			lsm.sstables = append(lsm.sstables, f)
+	lsm.wg.Add(1)
+	go lsm.flusher()
 
-		} else if strings.HasPrefix("index_", entry.Name()) {
+	return lsm, nil
+}
 
-			filePath := filepath.Join(filePath, entry.Name())
-			f, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_RDWR, os.ModePerm)
-			if err != nil {
-				return nil, fmt.Errorf("failed to open %s file %v", filePath, err)
-			}
-			lsm.indice = append(lsm.indice, f)
+// recover restore the last sequence number and
+// replay batches not yet flushed to a sorted string table
+func (l *LSM) recover() error {
+	for _, sstable := range l.sstables {
+		seq, err := sstableMaxSeq(sstable)
+		if err != nil {
+			return fmt.Errorf("sstableMaxSeq: %w", err)
+		}
+		l.seq = max(l.seq, seq)
+	}
 
+	for _, wal := range l.memtable.wals {
+		err := wal.replay(l.apply)
+		if err != nil {
+			return fmt.Errorf("wal.replay: %w", err)
 		}
 	}
 
-	return lsm, nil
+	return nil
+}
+
+// openWAL create the next write ahead log segment
+func (l *LSM) openWAL() (*WAL, error) {
+	l.mu.Lock()
+	n := l.walNum
+	l.walNum++
+	l.mu.Unlock()
+
+	fp := filepath.Join(l.sstDir, fmt.Sprintf("wal_%d.log", n))
+	f, err := os.OpenFile(fp, os.O_CREATE|os.O_APPEND|os.O_RDWR, dataFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile: %w", err)
+	}
+	return newWAL(f, fp, l.sync, l.keys), nil
+}
+
+// view tables visible to a reader, released
+// once the reader is done with it
+type view struct {
+	memtables []*memtable // newest first
+	sstables  []*table
+	seq       uint64
+}
+
+func (l *LSM) current() view {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	memtables := make([]*memtable, 0, len(l.immutable)+1)
+	memtables = append(memtables, l.memtable)
+	for i := len(l.immutable) - 1; i >= 0; i-- {
+		memtables = append(memtables, l.immutable[i])
+	}
+
+	for _, t := range l.sstables {
+		t.ref()
+	}
+
+	return view{
+		memtables: memtables,
+		sstables:  l.sstables[:len(l.sstables):len(l.sstables)],
+		seq:       l.seq,
+	}
 }
 
-// Iterator
+// release the tables of the view, their files are closed
+// once no longer listed by the store or read by another view
+func (v view) release() {
+	for _, t := range v.sstables {
+		_ = t.release()
+	}
+}
+
+// Iterator over all records in key order
 func (l *LSM) Iterator() Iterator {
+	v := l.current()
+	return l.newIterator(v, "", v.seq, isReservedKey)
+}
+
+// Scan iterator over records with key prefix in key order
+func (l *LSM) Scan(prefix string) Iterator {
+	v := l.current()
+	return l.newIterator(v, prefix, v.seq, isReservedKey)
+}
 
-	s := bufio.NewScanner(l.sstables[0])
+// GetByIndex iterator over records with secondary index name set to value
+func (l *LSM) GetByIndex(name, value string) Iterator {
+	v := l.current()
+	return l.newIndexIterator(v, name, value, v.seq)
+}
 
-	return &iterator{
-		lsm:            l,
-		currentSSTable: l.sstables[0],
-		currentIndex:   0,
-		scanner:        s,
+// Snapshot consistent view of all writes committed so far
+//
+// the versions it reads are kept until it is released
+func (l *LSM) Snapshot() *Snapshot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.snapshots[l.seq]++
+	return &Snapshot{
+		lsm: l,
+		seq: l.seq,
 	}
 }
 
-// Put
-func (l *LSM) Put(key string, value []byte, indice map[string]string, ttl int64) error {
+func (l *LSM) releaseSnapshot(seq uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	entry := &pb.KeyValue{
-		Key:   key,
-		Value: value,
-		Ttl:   ttl,
+	l.snapshots[seq]--
+	if l.snapshots[seq] <= 0 {
+		delete(l.snapshots, seq)
 	}
+}
+
+// horizon oldest sequence number a reader may still read at,
+// older versions shadowed at the horizon are never read again
+//
+// callers must hold mu
+func (l *LSM) horizon() uint64 {
+	h := l.seq
+	for seq := range l.snapshots {
+		h = min(h, seq)
+	}
+	return h
+}
 
-	b64bytes, err := marshalKeyValue(entry)
+// Range iterator over records with keys in [start, end) in
+// key order, an empty end is unbounded
+func (l *LSM) Range(start, end string) Iterator {
+	v := l.current()
+	return l.newRangeIterator(v, "", max(start, firstUserKey), end, v.seq, isReservedKey)
+}
+
+// GetByIndexRange iterator over records with secondary index name set
+// to value and keys in [start, end), an empty end is unbounded
+func (l *LSM) GetByIndexRange(name, value, start, end string) Iterator {
+	v := l.current()
+	return l.newIndexRangeIterator(v, name, value, start, end, v.seq)
+}
+
+func (l *LSM) newIterator(v view, prefix string, seq uint64, skip func(string) bool) *iterator {
+	return l.newRangeIterator(v, prefix, prefix, "", seq, skip)
+}
+
+// newRangeIterator iterator over keys with prefix from start up to end
+func (l *LSM) newRangeIterator(v view, prefix, start, end string, seq uint64, skip func(string) bool) *iterator {
+	cursors, err := v.cursors(max(prefix, start))
+	it := newIterator(cursors, prefix, seq, skip)
+	it.end = end
+	it.view = v
 	if err != nil {
-		return fmt.Errorf("marshalKeyValue: %v", err)
+		it.fail(err)
 	}
+	return it
+}
 
-	if int64(l.memtable.size) >= l.flushToDisk {
-		// compaction
-		err = l.compact()
-		if err != nil {
-			return fmt.Errorf("lsm.compact: %v", err)
-		}
-		l.memtable.flush()
+func (l *LSM) newIndexIterator(v view, name, value string, seq uint64) *indexIterator {
+	return l.newIndexRangeIterator(v, name, value, "", "", seq)
+}
+
+func (l *LSM) newIndexRangeIterator(v view, name, value, start, end string, seq uint64) *indexIterator {
+	prefix := indexValuePrefix(name, value)
+	if end != "" {
+		end = prefix + end
 	}
 
-	l.memtable.put(key, b64bytes)
+	return &indexIterator{
+		view:  v,
+		it:    l.newRangeIterator(v, prefix, prefix+start, end, seq, nil),
+		seq:   seq,
+		name:  name,
+		value: value,
+	}
+}
+
+// Put set record and maintain its secondary indice
+func (l *LSM) Put(key string, value []byte, indice map[string]string, ttl int64) error {
+
+	b := NewBatch()
+	b.Put(key, value, indice, ttl)
 
 	if ttl > 0 {
 		// add to timeout channel
 	}
 
-	return nil
+	return l.Write(b)
+}
+
+// Delete remove record and its secondary indice
+func (l *LSM) Delete(key string) error {
+
+	b := NewBatch()
+	b.Delete(key)
+
+	return l.Write(b)
 }
 
 // Get
 func (l *LSM) Get(key string) ([]byte, error) {
 
-	vbytes, err := l.memtable.get(key)
-	if err != nil && errors.Is(ErrNotFound, err) {
-		// do nothing
-	} else if err != nil {
-		return nil, fmt.Errorf("memtable.get: %v", err)
+	v := l.current()
+	defer v.release()
+
+	keyvalue, err := v.get(key, v.seq)
+	if err != nil {
+		return nil, err
 	}
 
-	if vbytes != nil {
+	return keyvalue.Value, nil
+}
+
+// get newest version of key visible at seq
+func (l *LSM) get(key string, seq uint64) (*pb.KeyValue, error) {
+	v := l.current()
+	defer v.release()
 
-		keyvalue, err := unmarshalKeyValue(vbytes)
-		if err != nil {
-			return nil, fmt.Errorf("unmarshalKeyValue: %v", err)
+	return v.get(key, seq)
+}
+
+func (v view) get(key string, seq uint64) (*pb.KeyValue, error) {
+
+	var vbytes []byte
+	for _, mt := range v.memtables {
+		payload, err := mt.get(key, seq)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("memtable.get: %w", err)
+		} else if err == nil {
+			vbytes = payload
+			break
 		}
+	}
 
-		return keyvalue.Value, nil
+	// newest sorted string table shadows older
+	for i := len(v.sstables) - 1; vbytes == nil && i >= 0; i-- {
+		payload, err := sstableGet(v.sstables[i], key, seq)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("sstableGet: %w", err)
+		}
+		vbytes = payload
 	}
 
-	for _, sstable := range l.sstables {
+	if vbytes == nil {
+		return nil, ErrNotFound
+	}
+
+	keyvalue, err := unmarshalKeyValue(vbytes)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalKeyValue: %w", err)
+	}
 
-		s := bufio.NewScanner(sstable)
+	if keyvalue.Tombstone {
+		return nil, ErrNotFound
+	}
 
-		for s.Scan() {
+	return keyvalue, nil
+}
 
-			keyvalue, err := unmarshalKeyValue(s.Bytes())
-			if err != nil {
-				return nil, fmt.Errorf("unmarshalKeyValue: %v", err)
-			}
+// cursors return sources positioned at start ordered newest to oldest
+func (v view) cursors(start string) ([]cursor, error) {
+	cursors := make([]cursor, 0, len(v.memtables)+len(v.sstables))
+	for _, mt := range v.memtables {
+		cursors = append(cursors, newMemTableCursor(mt.seek(start)))
+	}
 
-			if strings.Compare(key, keyvalue.Key) == 0 {
-				return keyvalue.Value, nil
-			}
+	for i := len(v.sstables) - 1; i >= 0; i-- {
+		c, err := newSSTableCursor(v.sstables[i])
+		if err != nil {
+			return nil, fmt.Errorf("newSSTableCursor: %w", err)
 		}
-	}
 
-	return nil, ErrNotFound
-}
+		err = c.seek(start)
+		if err != nil {
+			return nil, fmt.Errorf("sstableCursor.seek: %w", err)
+		}
 
-// Snapshot
-func (l *LSM) Snapshot() ([]byte, error) {
-	return nil, nil
+		cursors = append(cursors, c)
+	}
+	return cursors, nil
 }
 
 func (l *LSM) ExpirationCh() (chan interface{}, error) {
@@ -204,35 +479,18 @@ func (l *LSM) ExpirationCh() (chan interface{}, error) {
 	return ch, nil
 }
 
-// TODO:
-// filter existing records only use the latest value
-func (l *LSM) compact() error {
-
-	fp := filepath.Join(l.sstDir, fmt.Sprintf("sstable_%d.data", len(l.sstables)))
-	f, err := os.OpenFile(fp, os.O_CREATE|os.O_RDWR|os.O_APPEND, os.ModePerm)
-	if err != nil {
-		return fmt.Errorf("os.OpenFile: %v", err)
-	}
-
-	it := l.memtable.newMemTableIterator()
-
-	for it.hasNext() {
-
-		n := it.next()
-		_, err = f.Write(n.payload)
-		if err != nil {
-			return fmt.Errorf("file.Write: %v", err)
-		}
+// fileNumber parse the numeric suffix of data files
+func fileNumber(name string) int {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	i := strings.LastIndex(base, "_")
+	if i == -1 {
+		return -1
 	}
-
-	err = f.Sync()
+	n, err := strconv.Atoi(base[i+1:])
 	if err != nil {
-		return fmt.Errorf("file.Sync: %v", err)
+		return -1
 	}
-
-	l.sstables = append(l.sstables, f)
-
-	return nil
+	return n
 }
 
 func marshalKeyValue(keyvalue *pb.KeyValue) ([]byte, error) {
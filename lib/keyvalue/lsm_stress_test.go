@@ -0,0 +1,111 @@
+package keyvalue_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+const (
+	stressDir     = "testfiles_stress"
+	stressWriters = 4
+	stressReaders = 4
+	stressWrites  = 100
+)
+
+// TestConcurrentReadWrite run with -race
+//
+// writers commit batches of a counter key and its mirror so every
+// reader observing a snapshot must see both keys at the same value
+// while memtables are flushed underneath it
+func TestConcurrentReadWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	err := os.Mkdir(stressDir, os.ModePerm)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		t.Fatalf("failed to create working directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(stressDir) }()
+
+	lsm, err := keyvalue.New(stressDir)
+	if err != nil {
+		t.Fatalf("failed to create lsm: %v", err)
+	}
+
+	var (
+		writers sync.WaitGroup
+		readers sync.WaitGroup
+		stop    = make(chan struct{})
+		payload = make([]byte, 128)
+	)
+
+	for w := 0; w < stressWriters; w++ {
+		writers.Add(1)
+		go func(w int) {
+			defer writers.Done()
+			for i := 0; i < stressWrites; i++ {
+				value := []byte(strconv.Itoa(w*stressWrites + i))
+
+				b := keyvalue.NewBatch()
+				b.Put("counter", value, map[string]string{"writer": strconv.Itoa(w)}, -1)
+				b.Put("mirror", value, nil, -1)
+				b.Put(fmt.Sprintf("filler_%d_%d", w, i), payload, nil, -1)
+				assert.NoError(lsm.Write(b))
+			}
+		}(w)
+	}
+
+	for r := 0; r < stressReaders; r++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				snapshot := lsm.Snapshot()
+				counter, cerr := snapshot.Get("counter")
+				mirror, merr := snapshot.Get("mirror")
+				snapshot.Release()
+				if errors.Is(cerr, keyvalue.ErrNotFound) {
+					assert.ErrorIs(merr, keyvalue.ErrNotFound)
+					continue
+				}
+				assert.NoError(cerr)
+				assert.NoError(merr)
+				assert.Equal(string(counter), string(mirror))
+
+				it := lsm.GetByIndex("writer", "0")
+				for it.HasNext() {
+					_, _, err := it.Next()
+					assert.NoError(err)
+				}
+			}
+		}()
+	}
+
+	writers.Wait()
+	close(stop)
+	readers.Wait()
+
+	it := lsm.Iterator()
+	count := 0
+	for it.HasNext() {
+		_, _, err := it.Next()
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(stressWriters*stressWrites+2, count)
+
+	assert.NoError(lsm.Close())
+}
@@ -2,8 +2,12 @@ package keyvalue_test
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/protobuf/proto"
@@ -60,10 +64,443 @@ func (suite *LSMSuite) TestGet() {
 	assert.Equal("helloworld", string(vbytes))
 }
 
+func (suite *LSMSuite) TestGetByIndex() {
+
+	assert := suite.Assert()
+
+	err := suite.lsm.Put("user_1", []byte("alice"), map[string]string{"email": "alice@example.com"}, -1)
+	assert.NoError(err)
+
+	err = suite.lsm.Put("user_2", []byte("bob"), map[string]string{"email": "bob@example.com"}, -1)
+	assert.NoError(err)
+
+	// push both records into a sorted string table
+	for i := 0; i < 500; i++ {
+		err = suite.lsm.Put(fmt.Sprintf("filler_%d", i), []byte("filler"), nil, -1)
+		assert.NoError(err)
+	}
+
+	it := suite.lsm.GetByIndex("email", "alice@example.com")
+	assert.True(it.HasNext())
+	key, value, err := it.Next()
+	assert.NoError(err)
+	assert.Equal("user_1", key)
+	assert.Equal("alice", string(value))
+	assert.False(it.HasNext())
+
+	// moving the index value removes the stale entry
+	err = suite.lsm.Put("user_2", []byte("bob"), map[string]string{"email": "robert@example.com"}, -1)
+	assert.NoError(err)
+
+	it = suite.lsm.GetByIndex("email", "bob@example.com")
+	assert.False(it.HasNext())
+
+	it = suite.lsm.GetByIndex("email", "robert@example.com")
+	assert.True(it.HasNext())
+	key, _, err = it.Next()
+	assert.NoError(err)
+	assert.Equal("user_2", key)
+
+	err = suite.lsm.Put("\x00idx", []byte("reserved"), nil, -1)
+	assert.ErrorIs(err, keyvalue.ErrInvalidKey)
+}
+
+func (suite *LSMSuite) TestBatch() {
+
+	assert := suite.Assert()
+
+	b := keyvalue.NewBatch()
+	b.Put("block_1", []byte("block"), map[string]string{"height": "1"}, -1)
+	b.Put("tx_1", []byte("tx"), map[string]string{"block": "block_1"}, -1)
+	b.Delete("tx_0")
+	assert.NoError(suite.lsm.Write(b))
+
+	vbytes, err := suite.lsm.Get("tx_1")
+	assert.NoError(err)
+	assert.Equal("tx", string(vbytes))
+
+	err = suite.lsm.Delete("tx_1")
+	assert.NoError(err)
+
+	_, err = suite.lsm.Get("tx_1")
+	assert.ErrorIs(err, keyvalue.ErrNotFound)
+	assert.False(suite.lsm.GetByIndex("block", "block_1").HasNext())
+}
+
+func (suite *LSMSuite) TestSnapshot() {
+
+	assert := suite.Assert()
+
+	err := suite.lsm.Put("snapshot", []byte("v1"), map[string]string{"version": "1"}, -1)
+	assert.NoError(err)
+
+	snapshot := suite.lsm.Snapshot()
+	defer snapshot.Release()
+
+	err = suite.lsm.Put("snapshot", []byte("v2"), map[string]string{"version": "2"}, -1)
+	assert.NoError(err)
+
+	vbytes, err := snapshot.Get("snapshot")
+	assert.NoError(err)
+	assert.Equal("v1", string(vbytes))
+
+	it := snapshot.GetByIndex("version", "1")
+	assert.True(it.HasNext())
+	_, vbytes, err = it.Next()
+	assert.NoError(err)
+	assert.Equal("v1", string(vbytes))
+
+	vbytes, err = suite.lsm.Get("snapshot")
+	assert.NoError(err)
+	assert.Equal("v2", string(vbytes))
+}
+
+func (suite *LSMSuite) TestRange() {
+
+	assert := suite.Assert()
+
+	b := keyvalue.NewBatch()
+	for _, key := range []string{"range_1", "range_2", "range_3", "range_4"} {
+		b.Put(key, []byte(key), map[string]string{"kind": "range"}, -1)
+	}
+	assert.NoError(suite.lsm.Write(b))
+
+	keys := func(it keyvalue.Iterator) []string {
+		ks := make([]string, 0)
+		for it.HasNext() {
+			k, _, err := it.Next()
+			assert.NoError(err)
+			ks = append(ks, k)
+		}
+		return ks
+	}
+
+	assert.Equal([]string{"range_2", "range_3"}, keys(suite.lsm.Range("range_2", "range_4")))
+	assert.Equal([]string{"range_3", "range_4"}, keys(suite.lsm.Range("range_3", "range_5")))
+	assert.Equal([]string{"range_1"}, keys(suite.lsm.GetByIndexRange("kind", "range", "", "range_2")))
+	assert.Equal([]string{"range_4"}, keys(suite.lsm.GetByIndexRange("kind", "range", "range_4", "")))
+
+	snapshot := suite.lsm.Snapshot()
+	defer snapshot.Release()
+	assert.NoError(suite.lsm.Delete("range_3"))
+
+	assert.Equal([]string{"range_2", "range_3"}, keys(snapshot.Range("range_2", "range_4")))
+	assert.Equal([]string{"range_2"}, keys(suite.lsm.GetByIndexRange("kind", "range", "range_2", "range_4")))
+}
+
 func (suite *LSMSuite) TearDownSuite() {
+	suite.Assert().NoError(suite.lsm.Close())
 	os.RemoveAll("testfiles")
 }
 
 func TestLSMSuite(t *testing.T) {
 	suite.Run(t, new(LSMSuite))
 }
+
+func TestReplay(t *testing.T) {
+
+	dir := "testfiles_replay"
+	err := os.Mkdir(dir, os.ModePerm)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		t.Fatalf("failed to create working directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	lsm, err := keyvalue.New(dir)
+	if err != nil {
+		t.Fatalf("failed to create lsm: %v", err)
+	}
+
+	b := keyvalue.NewBatch()
+	b.Put("hello", []byte("world"), map[string]string{"greeting": "hello"}, -1)
+	b.Put("goodbye", []byte("world"), nil, -1)
+	if err = lsm.Write(b); err != nil {
+		t.Fatalf("failed to write batch: %v", err)
+	}
+
+	// reopen without flushing the active memtable
+	if err = lsm.Close(); err != nil {
+		t.Fatalf("failed to close lsm: %v", err)
+	}
+	if err = lsm.Close(); !errors.Is(err, keyvalue.ErrClosed) {
+		t.Fatalf("unexpected error %v expected %v", err, keyvalue.ErrClosed)
+	}
+
+	lsm, err = keyvalue.New(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen lsm: %v", err)
+	}
+
+	vbytes, err := lsm.Get("goodbye")
+	if err != nil {
+		t.Fatalf("failed to get replayed key: %v", err)
+	}
+	if string(vbytes) != "world" {
+		t.Fatalf("unexpected value %s", vbytes)
+	}
+
+	if !lsm.GetByIndex("greeting", "hello").HasNext() {
+		t.Fatal("expected replayed index entry")
+	}
+
+	if err = lsm.Close(); err != nil {
+		t.Fatalf("failed to close lsm: %v", err)
+	}
+}
+
+func TestBlocks(t *testing.T) {
+
+	dir := "testfiles_blocks"
+	err := os.Mkdir(dir, os.ModePerm)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		t.Fatalf("failed to create working directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	opts := []keyvalue.Option{
+		keyvalue.WithBlockSize(64),
+		keyvalue.WithMemtableSize(512),
+		keyvalue.WithSync(false),
+	}
+
+	lsm, err := keyvalue.New(dir, opts...)
+	if err != nil {
+		t.Fatalf("failed to create lsm: %v", err)
+	}
+
+	for i := range 200 {
+		key := fmt.Sprintf("key_%03d", i)
+		if err = lsm.Put(key, []byte(key), nil, -1); err != nil {
+			t.Fatalf("failed to put %s: %v", key, err)
+		}
+	}
+
+	if err = lsm.Close(); err != nil {
+		t.Fatalf("failed to close lsm: %v", err)
+	}
+
+	lsm, err = keyvalue.New(dir, opts...)
+	if err != nil {
+		t.Fatalf("failed to reopen lsm: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	for _, i := range []int{0, 63, 127, 199} {
+		key := fmt.Sprintf("key_%03d", i)
+		vbytes, err := lsm.Get(key)
+		if err != nil {
+			t.Fatalf("failed to get %s: %v", key, err)
+		}
+		if string(vbytes) != key {
+			t.Fatalf("unexpected value %s for %s", vbytes, key)
+		}
+	}
+
+	var count int
+	it := lsm.Iterator()
+	for it.HasNext() {
+		if _, _, err = it.Next(); err != nil {
+			t.Fatalf("failed to iterate: %v", err)
+		}
+		count++
+	}
+	if count != 200 {
+		t.Fatalf("expected 200 records, got %d", count)
+	}
+}
+
+func TestCompactDiscardsVersions(t *testing.T) {
+
+	dir := t.TempDir()
+	lsm, err := keyvalue.New(dir, keyvalue.WithMemtableSize(512), keyvalue.WithSync(false))
+	if err != nil {
+		t.Fatalf("failed to create lsm: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	put := func(key, value string) {
+		t.Helper()
+		if err := lsm.Put(key, []byte(value), nil, -1); err != nil {
+			t.Fatalf("failed to put %s: %v", key, err)
+		}
+	}
+	tableSize := func() int64 {
+		t.Helper()
+		files, err := filepath.Glob(filepath.Join(dir, "sstable_*.data"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var size int64
+		for _, f := range files {
+			stat, err := os.Stat(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			size += stat.Size()
+		}
+		return size
+	}
+	get := func(key string) (string, error) {
+		vbytes, err := lsm.Get(key)
+		return string(vbytes), err
+	}
+
+	put("pinned", "v0")
+	put("gone", "v0")
+	snapshot := lsm.Snapshot()
+
+	put("pinned", "v1")
+	if err = lsm.Delete("gone"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	for i := range 200 {
+		put("counter", fmt.Sprintf("%0128d", i))
+	}
+
+	if err = lsm.Compact(); err != nil {
+		t.Fatalf("failed to compact: %v", err)
+	}
+
+	// the snapshot still reads the versions it was taken at
+	for _, key := range []string{"pinned", "gone"} {
+		vbytes, err := snapshot.Get(key)
+		if err != nil || string(vbytes) != "v0" {
+			t.Fatalf("unexpected snapshot read %s %q %v", key, vbytes, err)
+		}
+	}
+	snapshot.Release()
+
+	pinned := tableSize()
+	if err = lsm.Compact(); err != nil {
+		t.Fatalf("failed to compact: %v", err)
+	}
+	if size := tableSize(); size*4 > pinned {
+		t.Fatalf("compaction kept shadowed versions, %d bytes from %d", size, pinned)
+	}
+
+	if v, err := get("pinned"); err != nil || v != "v1" {
+		t.Fatalf("unexpected pinned %q %v", v, err)
+	}
+	if v, err := get("counter"); err != nil || v != fmt.Sprintf("%0128d", 199) {
+		t.Fatalf("unexpected counter %q %v", v, err)
+	}
+	if _, err = get("gone"); !errors.Is(err, keyvalue.ErrNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, keyvalue.ErrNotFound)
+	}
+}
+
+func TestCompactClosesTables(t *testing.T) {
+
+	if _, err := os.Stat("/proc/self/fd"); err != nil {
+		t.Skip("open files are not listed")
+	}
+
+	dir := t.TempDir()
+	lsm, err := keyvalue.New(dir, keyvalue.WithMemtableSize(512), keyvalue.WithSync(false))
+	if err != nil {
+		t.Fatalf("failed to create lsm: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	for i := range 100 {
+		key := fmt.Sprintf("key_%03d", i)
+		if err = lsm.Put(key, []byte(key), nil, -1); err != nil {
+			t.Fatalf("failed to put %s: %v", key, err)
+		}
+	}
+
+	// an open iterator keeps reading the merged tables
+	it := lsm.Iterator()
+	if err = lsm.Compact(); err != nil {
+		t.Fatalf("failed to compact: %v", err)
+	}
+
+	var count int
+	for it.HasNext() {
+		if _, _, err = it.Next(); err != nil {
+			t.Fatalf("failed to iterate: %v", err)
+		}
+		count++
+	}
+	if count != 100 {
+		t.Fatalf("expected 100 records, got %d", count)
+	}
+
+	// iterators stopped early release the tables when closed
+	scan := lsm.Scan("key_")
+	index := lsm.GetByIndex("missing", "value")
+	if !scan.HasNext() || index.HasNext() {
+		t.Fatal("unexpected iterator state")
+	}
+	if err = lsm.Compact(); err != nil {
+		t.Fatalf("failed to compact: %v", err)
+	}
+	scan.Close()
+	index.Close()
+	if scan.HasNext() {
+		t.Fatal("closed iterator has next")
+	}
+
+	if err = lsm.Compact(); err != nil {
+		t.Fatalf("failed to compact: %v", err)
+	}
+
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fd := range fds {
+		target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name()))
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(target, dir) && strings.HasSuffix(target, "(deleted)") {
+			t.Fatalf("merged table %s left open", target)
+		}
+	}
+}
+
+func TestCompactionTrigger(t *testing.T) {
+
+	dir := t.TempDir()
+	lsm, err := keyvalue.New(dir,
+		keyvalue.WithMemtableSize(512),
+		keyvalue.WithSync(false),
+		keyvalue.WithCompactionTrigger(2),
+	)
+	if err != nil {
+		t.Fatalf("failed to create lsm: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	for i := range 200 {
+		key := fmt.Sprintf("key_%03d", i%10)
+		if err = lsm.Put(key, []byte(key), nil, -1); err != nil {
+			t.Fatalf("failed to put %s: %v", key, err)
+		}
+	}
+
+	// every flush reaching the trigger is followed by a compaction
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		tables, err := filepath.Glob(filepath.Join(dir, "sstable_*.data"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tables) < 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d tables left uncompacted", len(tables))
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	if err = lsm.Verify(); err != nil {
+		t.Fatalf("failed to verify: %v", err)
+	}
+	vbytes, err := lsm.Get("key_009")
+	if err != nil || string(vbytes) != "key_009" {
+		t.Fatalf("unexpected value %q %v", vbytes, err)
+	}
+}
@@ -0,0 +1,162 @@
+package keyvalue
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
+)
+
+// manifest lists the live files of a store
+//
+// it is replaced atomically with a rename whenever a table is
+// published or a log segment is added so the directory can be
+// opened or copied without seeing a half flushed table
+const manifestFile = "MANIFEST"
+
+func readManifest(dir string) (*pb.Manifest, error) {
+	pbbytes, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	var manifest pb.Manifest
+	err = proto.Unmarshal(pbbytes, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+func writeManifest(dir string, manifest *pb.Manifest) error {
+	pbbytes, err := proto.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	tmp := filepath.Join(dir, manifestFile+".tmp")
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, dataFilePerm)
+	if err != nil {
+		return fmt.Errorf("os.OpenFile: %w", err)
+	}
+
+	_, err = f.Write(pbbytes)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	err = os.Rename(tmp, filepath.Join(dir, manifestFile))
+	if err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	return syncDir(dir)
+}
+
+// scanManifest build a manifest from the files of a store
+// created before manifests were introduced
+func scanManifest(dir string) (*pb.Manifest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadDir: %w", err)
+	}
+
+	// files must be loaded oldest to newest
+	sort.Slice(entries, func(i, j int) bool {
+		return fileNumber(entries[i].Name()) < fileNumber(entries[j].Name())
+	})
+
+	manifest := &pb.Manifest{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		n := uint64(max(fileNumber(entry.Name()), 0))
+
+		switch {
+		case strings.HasPrefix(entry.Name(), "sstable_"):
+			manifest.Sstables = append(manifest.Sstables, &pb.ManifestFile{Name: entry.Name()})
+			manifest.NextSstable = max(manifest.NextSstable, n+1)
+		case strings.HasPrefix(entry.Name(), "wal"):
+			manifest.Wals = append(manifest.Wals, &pb.ManifestFile{Name: entry.Name()})
+			manifest.NextWal = max(manifest.NextWal, n+1)
+		}
+	}
+
+	return manifest, nil
+}
+
+// manifest describe the live files of the store
+func (l *LSM) manifest() *pb.Manifest {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	manifest := &pb.Manifest{
+		Sstables:    make([]*pb.ManifestFile, 0, len(l.sstables)),
+		Wals:        make([]*pb.ManifestFile, 0),
+		NextSstable: l.sstNum,
+		NextWal:     l.walNum,
+	}
+
+	for _, t := range l.sstables {
+		manifest.Sstables = append(manifest.Sstables, &pb.ManifestFile{
+			Name: filepath.Base(t.f.Name()),
+		})
+	}
+
+	// replay order is oldest memtable first
+	for _, mt := range append(l.immutable[:len(l.immutable):len(l.immutable)], l.memtable) {
+		for _, wal := range mt.wals {
+			manifest.Wals = append(manifest.Wals, &pb.ManifestFile{
+				Name: filepath.Base(wal.path),
+			})
+		}
+	}
+
+	for _, wal := range l.retained {
+		manifest.Retained = append(manifest.Retained, &pb.ManifestFile{
+			Name: filepath.Base(wal.path),
+		})
+	}
+
+	l.keys.manifest(manifest)
+
+	return manifest
+}
+
+// saveManifest persist the current manifest
+func (l *LSM) saveManifest() error {
+	l.manifestMu.Lock()
+	defer l.manifestMu.Unlock()
+
+	return writeManifest(l.sstDir, l.manifest())
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(filepath.Clean(dir))
+	if err != nil {
+		return fmt.Errorf("os.Open: %w", err)
+	}
+	defer func() { _ = d.Close() }()
+
+	err = d.Sync()
+	if err != nil && !errors.Is(err, os.ErrInvalid) {
+		return fmt.Errorf("dir.Sync: %w", err)
+	}
+
+	return nil
+}
@@ -1,19 +1,30 @@
 package keyvalue
 
 import (
-	"errors"
 	"strings"
+	"sync/atomic"
 )
 
 type node struct {
 	key     string
+	seq     uint64
 	payload []byte
-	next    *node
+	next    atomic.Pointer[node]
 }
 
+// memtable sorted linked list of encoded key values
+//
+// nodes are ordered by key ascending then sequence
+// descending so the newest version of a key comes first
+//
+// a single writer links fully initialised nodes with atomic
+// stores so readers traverse the list without locking
 type memtable struct {
-	head *node
-	size int
+	head atomic.Pointer[node]
+	size atomic.Int64
+
+	// write ahead log segments holding the memtable batches
+	wals []*WAL
 }
 
 type memtableIterator struct {
@@ -22,86 +33,75 @@ type memtableIterator struct {
 
 func newMemTable() *memtable {
 	return &memtable{
-		head: nil,
-		size: 0,
+		wals: make([]*WAL, 0),
 	}
 }
 
 func (mt *memtable) newMemTableIterator() *memtableIterator {
 	return &memtableIterator{
-		currentNode: mt.head,
+		currentNode: mt.head.Load(),
 	}
 }
 
-func (m *memtable) put(key string, payload []byte) {
-	defer func() { m.size += len(payload) }()
-	if m.head == nil {
-		m.head = &node{
-			key:     key,
-			payload: payload,
-			next:    nil,
-		}
-
-		return
+// seek return iterator positioned at the first key >= start
+func (mt *memtable) seek(start string) *memtableIterator {
+	n := mt.head.Load()
+	for n != nil && strings.Compare(n.key, start) < 0 {
+		n = n.next.Load()
+	}
+	return &memtableIterator{
+		currentNode: n,
 	}
+}
 
-	n := m.head
-	for {
-		if n.next != nil {
-			n = n.next
-			continue
-		}
-		break
+// put insert a new version of key
+//
+// callers must serialise put
+func (m *memtable) put(key string, seq uint64, payload []byte) {
+	defer m.size.Add(int64(len(payload)))
+
+	var prev *node
+	n := m.head.Load()
+	for n != nil && (strings.Compare(n.key, key) < 0 || (n.key == key && n.seq > seq)) {
+		prev = n
+		n = n.next.Load()
 	}
 
-	n.next = &node{
+	nn := &node{
 		key:     key,
+		seq:     seq,
 		payload: payload,
-		next:    nil,
 	}
+	nn.next.Store(n)
 
-}
-
-func (m *memtable) get(key string) ([]byte, error) {
-	if m.head == nil {
-		return nil, errors.New("head is nil")
+	if prev == nil {
+		m.head.Store(nn)
+		return
 	}
+	prev.next.Store(nn)
+}
 
-	n := m.head
-
-	for {
-
-		if strings.Compare(key, n.key) == 0 {
+// get newest version of key visible at seq
+func (m *memtable) get(key string, seq uint64) ([]byte, error) {
+	for n := m.head.Load(); n != nil; n = n.next.Load() {
+		switch c := strings.Compare(key, n.key); {
+		case c == 0 && n.seq <= seq:
 			return n.payload, nil
+		case c < 0:
+			// list is sorted, key will not be found further on
+			return nil, ErrNotFound
 		}
-
-		if n.next == nil {
-			break
-		}
-
-		n = n.next
 	}
 
 	return nil, ErrNotFound
 }
 
-func (m *memtable) flush() {
-	defer func() { m.size = 0 }()
-	n := m.head
-	for n != nil {
-		t := n.next
-		n = nil
-		n = t
-	}
-	m.head = nil
-}
-
 func (it *memtableIterator) hasNext() bool {
-	return it.currentNode != nil && it.currentNode.next != nil
+	return it.currentNode != nil
 }
 
-func (it *memtableIterator) next() node {
+func (it *memtableIterator) next() *node {
 	n := it.currentNode
-	it.currentNode = n.next
-	return *n
-}
\ No newline at end of file
+	it.currentNode = n.next.Load()
+	return n
+}
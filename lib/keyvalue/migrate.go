@@ -0,0 +1,230 @@
+package keyvalue
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
+)
+
+const (
+	// legacyDir holds the files of a migrated legacy store
+	legacyDir = "legacy"
+	// migrateDir new store is built here before it replaces the legacy files
+	migrateDir = ".migrate"
+)
+
+// MigrateLegacy convert a store written by the original audit
+// engine in dir into the current format
+//
+// the legacy layout has no manifest, an index_0.log and sorted string
+// tables of unframed key values in insertion order. legacy files are
+// moved into a legacy sub directory and kept. a no-op when dir is not
+// a legacy store
+func MigrateLegacy(dir string, opts ...Option) error {
+	dir = filepath.Clean(dir)
+
+	_, err := os.Stat(filepath.Join(dir, manifestFile))
+	if err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("os.Stat: %w", err)
+	}
+
+	legacy := filepath.Join(dir, legacyDir)
+	ok, err := isLegacy(dir, legacy)
+	if err != nil || !ok {
+		return err
+	}
+
+	err = moveLegacy(dir, legacy)
+	if err != nil {
+		return fmt.Errorf("failed to move legacy files: %w", err)
+	}
+
+	tmp := filepath.Join(dir, migrateDir)
+	err = os.RemoveAll(tmp)
+	if err != nil {
+		return fmt.Errorf("os.RemoveAll: %w", err)
+	}
+
+	err = os.Mkdir(tmp, dataDirPerm)
+	if err != nil {
+		return fmt.Errorf("os.Mkdir: %w", err)
+	}
+
+	err = migrate(legacy, tmp, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to migrate legacy store: %w", err)
+	}
+
+	// manifest is moved last, until then
+	// the migration is restarted on open
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		return fmt.Errorf("os.ReadDir: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == manifestFile {
+			continue
+		}
+
+		err = os.Rename(filepath.Join(tmp, entry.Name()), filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("os.Rename: %w", err)
+		}
+	}
+
+	err = os.Rename(filepath.Join(tmp, manifestFile), filepath.Join(dir, manifestFile))
+	if err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	err = syncDir(dir)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(tmp)
+}
+
+// isLegacy dir holds a legacy store or an interrupted migration
+func isLegacy(dir, legacy string) (bool, error) {
+	_, err := os.Stat(legacy)
+	if err == nil {
+		return true, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "index_*.log"))
+	if err != nil {
+		return false, fmt.Errorf("filepath.Glob: %w", err)
+	}
+
+	return len(matches) > 0, nil
+}
+
+func moveLegacy(dir, legacy string) error {
+	err := os.MkdirAll(legacy, dataDirPerm)
+	if err != nil {
+		return fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("os.ReadDir: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasPrefix(name, "sstable_") ||
+			strings.HasPrefix(name, "index_") || name == "wal.log") {
+			continue
+		}
+
+		// files left by an interrupted migration are rebuilt
+		if _, err := os.Stat(filepath.Join(legacy, name)); err == nil {
+			err = os.Remove(filepath.Join(dir, name))
+		} else {
+			err = os.Rename(filepath.Join(dir, name), filepath.Join(legacy, name))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to move %s: %w", name, err)
+		}
+	}
+
+	return syncDir(dir)
+}
+
+// migrate write every legacy record into a new store in dst
+//
+// tables are replayed oldest to newest so the most
+// recent value of a key is the one that survives
+func migrate(src, dst string, opts ...Option) error {
+	matches, err := filepath.Glob(filepath.Join(src, "sstable_*.data"))
+	if err != nil {
+		return fmt.Errorf("filepath.Glob: %w", err)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return fileNumber(matches[i]) < fileNumber(matches[j])
+	})
+
+	store, err := New(dst, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+
+	for _, fp := range matches {
+		buf, err := os.ReadFile(filepath.Clean(fp))
+		if err != nil {
+			_ = store.Close()
+			return fmt.Errorf("os.ReadFile: %w", err)
+		}
+
+		keyvalues, err := splitLegacy(buf)
+		if err != nil {
+			_ = store.Close()
+			return fmt.Errorf("failed to parse %s: %w", filepath.Base(fp), err)
+		}
+
+		for _, keyvalue := range keyvalues {
+			err = store.Put(keyvalue.Key, keyvalue.Value, nil, keyvalue.Ttl)
+			if err != nil {
+				_ = store.Close()
+				return fmt.Errorf("lsm.Put: %w", err)
+			}
+		}
+	}
+
+	return store.Close()
+}
+
+// splitLegacy decode concatenated key values
+//
+// records were written without framing, a field number
+// lower or equal to the previous one starts a new record
+func splitLegacy(buf []byte) ([]*pb.KeyValue, error) {
+	keyvalues := make([]*pb.KeyValue, 0)
+
+	var start, offset int
+	var last protowire.Number
+	for offset < len(buf) {
+		num, typ, n := protowire.ConsumeTag(buf[offset:])
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+
+		if num <= last {
+			keyvalue, err := unmarshalKeyValue(buf[start:offset])
+			if err != nil {
+				return nil, err
+			}
+			keyvalues = append(keyvalues, keyvalue)
+			start = offset
+		}
+		last = num
+
+		m := protowire.ConsumeFieldValue(num, typ, buf[offset+n:])
+		if m < 0 {
+			return nil, protowire.ParseError(m)
+		}
+		offset += n + m
+	}
+
+	if start < len(buf) {
+		keyvalue, err := unmarshalKeyValue(buf[start:])
+		if err != nil {
+			return nil, err
+		}
+		keyvalues = append(keyvalues, keyvalue)
+	}
+
+	return keyvalues, nil
+}
@@ -0,0 +1,93 @@
+package keyvalue_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
+)
+
+const (
+	migrateTestDir = "testfiles_migrate"
+)
+
+// writeLegacyTable write key values the way the original audit engine
+// flushed its memtable, back to back without framing
+func writeLegacyTable(t *testing.T, name string, keyvalues ...*pb.KeyValue) {
+	var buf []byte
+	for _, keyvalue := range keyvalues {
+		pbbytes, err := proto.Marshal(keyvalue)
+		if err != nil {
+			t.Fatalf("failed to marshal key value: %v", err)
+		}
+		buf = append(buf, pbbytes...)
+	}
+
+	err := os.WriteFile(filepath.Join(migrateTestDir, name), buf, 0o600)
+	if err != nil {
+		t.Fatalf("failed to write legacy table: %v", err)
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	assert := assert.New(t)
+
+	err := os.Mkdir(migrateTestDir, os.ModePerm)
+	if err != nil {
+		t.Fatalf("failed to create working directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(migrateTestDir) }()
+
+	writeLegacyTable(t, "sstable_0.data",
+		&pb.KeyValue{Key: "block_0", Value: []byte("genesis"), Ttl: -1},
+		&pb.KeyValue{Key: "block_1", Value: []byte("first"), Ttl: -1},
+	)
+	writeLegacyTable(t, "sstable_1.data",
+		&pb.KeyValue{Key: "block_1", Value: []byte("replaced"), Ttl: -1},
+		&pb.KeyValue{Key: "block_2", Value: []byte("second"), Ttl: -1},
+	)
+	for _, name := range []string{"index_0.log", "wal.log"} {
+		assert.NoError(os.WriteFile(filepath.Join(migrateTestDir, name), nil, 0o600))
+	}
+
+	assert.NoError(keyvalue.MigrateLegacy(migrateTestDir))
+
+	// a migrated store is left alone
+	assert.NoError(keyvalue.MigrateLegacy(migrateTestDir))
+
+	_, err = os.Stat(filepath.Join(migrateTestDir, "legacy", "sstable_0.data"))
+	assert.NoError(err)
+
+	lsm, err := keyvalue.New(migrateTestDir)
+	if err != nil {
+		t.Fatalf("failed to open migrated lsm: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	expected := map[string]string{
+		"block_0": "genesis",
+		"block_1": "replaced",
+		"block_2": "second",
+	}
+
+	for key, value := range expected {
+		vbytes, err := lsm.Get(key)
+		assert.NoError(err)
+		assert.Equal(value, string(vbytes))
+	}
+
+	var count int
+	it := lsm.Iterator()
+	for it.HasNext() {
+		_, _, err = it.Next()
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(len(expected), count)
+}
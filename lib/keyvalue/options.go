@@ -0,0 +1,117 @@
+package keyvalue
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultMemtableSize = 10000
+	defaultBlockSize    = 4096
+)
+
+// Compression sorted string table block compression
+type Compression uint8
+
+const (
+	// NoCompression blocks are stored as is
+	NoCompression Compression = iota
+	// Snappy fast block compression
+	Snappy
+	// Zstd higher ratio block compression
+	Zstd
+)
+
+// String
+func (c Compression) String() string {
+	switch c {
+	case NoCompression:
+		return "none"
+	case Snappy:
+		return "snappy"
+	case Zstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("compression(%d)", uint8(c))
+	}
+}
+
+// ParseCompression parse compression name
+func ParseCompression(name string) (Compression, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return NoCompression, nil
+	case "snappy":
+		return Snappy, nil
+	case "zstd":
+		return Zstd, nil
+	default:
+		return NoCompression, fmt.Errorf("unsupported compression %s", name)
+	}
+}
+
+// Option store option pattern
+type Option func(*LSM)
+
+// WithSync fsync the write ahead log after every batch
+//
+// enabled by default, disabling trades durability of the
+// most recent batches on power loss for write throughput
+func WithSync(sync bool) Option {
+	return func(l *LSM) {
+		l.sync = sync
+	}
+}
+
+// WithCompression compression applied to new sorted string table blocks
+func WithCompression(compression Compression) Option {
+	return func(l *LSM) {
+		l.compression = compression
+	}
+}
+
+// WithBlockSize target uncompressed size of sorted string table blocks
+func WithBlockSize(size int) Option {
+	return func(l *LSM) {
+		if size > 0 {
+			l.blockSize = size
+		}
+	}
+}
+
+// WithMemtableSize memtable size in bytes before it is flushed to disk
+func WithMemtableSize(size int64) Option {
+	return func(l *LSM) {
+		if size > 0 {
+			l.flushToDisk = size
+		}
+	}
+}
+
+// WithEncryption seal sorted string table blocks and write
+// ahead log records with data keys wrapped by wrapper
+func WithEncryption(wrapper KeyWrapper) Option {
+	return func(l *LSM) {
+		l.wrapper = wrapper
+	}
+}
+
+// WithRetention flushed write ahead log segments kept so
+// subscribers can resume from an older sequence number
+func WithRetention(segments int) Option {
+	return func(l *LSM) {
+		if segments > 0 {
+			l.retention = segments
+		}
+	}
+}
+
+// WithCompactionTrigger compact in the background once
+// tables sorted string tables are flushed, zero disables it
+func WithCompactionTrigger(tables int) Option {
+	return func(l *LSM) {
+		if tables >= 0 {
+			l.compactAt = tables
+		}
+	}
+}
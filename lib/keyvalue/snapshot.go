@@ -0,0 +1,60 @@
+package keyvalue
+
+import "sync"
+
+// Snapshot consistent point in time view of the store
+//
+// compaction keeps every version visible at the sequence number
+// of a live snapshot, Release once done so they can be discarded
+type Snapshot struct {
+	lsm  *LSM
+	seq  uint64
+	once sync.Once
+}
+
+// Release let compaction discard versions only the snapshot
+// could read, the snapshot must not be used afterwards
+func (s *Snapshot) Release() {
+	s.once.Do(func() {
+		s.lsm.releaseSnapshot(s.seq)
+	})
+}
+
+// Seq sequence number the snapshot was taken at
+func (s *Snapshot) Seq() uint64 {
+	return s.seq
+}
+
+// Get record by key as of the snapshot
+func (s *Snapshot) Get(key string) ([]byte, error) {
+	keyvalue, err := s.lsm.get(key, s.seq)
+	if err != nil {
+		return nil, err
+	}
+	return keyvalue.Value, nil
+}
+
+// Iterator over all records as of the snapshot
+func (s *Snapshot) Iterator() Iterator {
+	return s.lsm.newIterator(s.lsm.current(), "", s.seq, isReservedKey)
+}
+
+// Scan iterator over records with key prefix as of the snapshot
+func (s *Snapshot) Scan(prefix string) Iterator {
+	return s.lsm.newIterator(s.lsm.current(), prefix, s.seq, isReservedKey)
+}
+
+// GetByIndex iterator over indexed records as of the snapshot
+func (s *Snapshot) GetByIndex(name, value string) Iterator {
+	return s.lsm.newIndexIterator(s.lsm.current(), name, value, s.seq)
+}
+
+// Range iterator over records with keys in [start, end) as of the snapshot
+func (s *Snapshot) Range(start, end string) Iterator {
+	return s.lsm.newRangeIterator(s.lsm.current(), "", max(start, firstUserKey), end, s.seq, isReservedKey)
+}
+
+// GetByIndexRange iterator over indexed records with keys in [start, end) as of the snapshot
+func (s *Snapshot) GetByIndexRange(name, value, start, end string) Iterator {
+	return s.lsm.newIndexRangeIterator(s.lsm.current(), name, value, start, end, s.seq)
+}
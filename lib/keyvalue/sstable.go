@@ -1 +1,604 @@
-package keyvalue
\ No newline at end of file
+package keyvalue
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
+)
+
+// sorted string table layout
+//
+// | data block | ... | data block | index block | footer |
+//
+// data blocks hold length prefixed encoded key values in key
+// order, the index block holds a length prefixed block handle
+// for every data block and the fixed size footer locates the
+// index block
+//
+// every block is followed by a trailer with the compression
+// applied to the block and a crc32c of the stored bytes, the high
+// bit of the compression byte marks blocks sealed with a data key
+//
+// | block | compression uint8 | crc32c uint32 |
+// | index offset uint64 | index size uint64 | max seq uint64 | compression uint8 | magic uint64 |
+//
+// v1 tables have blocks without trailers and v1, v2 footers have no
+// max seq. tables written before blocks were introduced have no
+// footer and are read as a single uncompressed block
+const (
+	tableMagicV1     uint64 = 0x7462646c736d7631 // tbdlsmv1
+	tableMagicV2     uint64 = 0x7462646c736d7632 // tbdlsmv2
+	tableMagic       uint64 = 0x7462646c736d7633 // tbdlsmv3
+	tableFooterLen          = 8 + 8 + 8 + 1 + 8
+	tableFooterLenV2        = 8 + 8 + 1 + 8
+	blockTrailerLen         = 1 + 4
+
+	blockEncrypted byte = 0x80
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+func writeRecord(w io.Writer, payload []byte) error {
+	buf := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(payload))
+	n := binary.PutUvarint(buf, uint64(len(payload)))
+	buf = append(buf[:n], payload...)
+
+	_, err := w.Write(buf)
+	if err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+
+	return nil
+}
+
+// table open sorted string table
+type table struct {
+	f           *os.File
+	keys        *keyring
+	compression Compression
+	blocks      []*pb.BlockHandle
+	// maxSeq highest sequence number, zero when unknown
+	maxSeq uint64
+	// flat tables have a single block without a known last key
+	flat bool
+	// trailers blocks carry a compression type and checksum
+	trailers bool
+	// keyIDs data keys the table was sealed with, nil when unknown
+	keyIDs map[uint32]struct{}
+	// refs open views of the table, plus one while the store
+	// lists it, the file is closed when the last is released
+	refs atomic.Int64
+}
+
+func openTable(f *os.File, keys *keyring) (*table, error) {
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("file.Stat: %w", err)
+	}
+
+	t := &table{
+		f:      f,
+		keys:   keys,
+		blocks: make([]*pb.BlockHandle, 0),
+	}
+	t.refs.Store(1)
+
+	footer, err := readFooter(f, stat.Size())
+	if err != nil {
+		return nil, err
+	} else if footer == nil {
+		return t.legacy(stat.Size()), nil
+	}
+
+	t.compression = footer.compression
+	t.maxSeq = footer.maxSeq
+	t.trailers = footer.trailers
+
+	buf, err := t.read(footer.index)
+	if err != nil {
+		return nil, fmt.Errorf("read index block: %w", err)
+	}
+
+	r := bytes.NewReader(buf)
+	for r.Len() > 0 {
+		record, err := readRecord(r)
+		if err != nil {
+			return nil, fmt.Errorf("read index record: %w", err)
+		}
+
+		var h pb.BlockHandle
+		err = proto.Unmarshal(record, &h)
+		if err != nil {
+			return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+		}
+		t.blocks = append(t.blocks, &h)
+	}
+
+	return t, nil
+}
+
+type tableFooter struct {
+	index       *pb.BlockHandle
+	maxSeq      uint64
+	compression Compression
+	trailers    bool
+}
+
+// readFooter nil for tables written before footers were introduced
+func readFooter(f *os.File, size int64) (*tableFooter, error) {
+	if size < tableFooterLenV2 {
+		return nil, nil
+	}
+
+	magic := make([]byte, 8)
+	_, err := f.ReadAt(magic, size-8)
+	if err != nil {
+		return nil, fmt.Errorf("read footer: %w", err)
+	}
+
+	footer := &tableFooter{}
+	footerLen := int64(tableFooterLenV2)
+	switch binary.BigEndian.Uint64(magic) {
+	case tableMagic:
+		footerLen = tableFooterLen
+		footer.trailers = true
+	case tableMagicV2:
+		footer.trailers = true
+	case tableMagicV1:
+	default:
+		return nil, nil
+	}
+
+	if size < footerLen {
+		return nil, nil
+	}
+
+	buf := make([]byte, footerLen)
+	_, err = f.ReadAt(buf, size-footerLen)
+	if err != nil {
+		return nil, fmt.Errorf("read footer: %w", err)
+	}
+
+	footer.index = &pb.BlockHandle{
+		Offset: binary.BigEndian.Uint64(buf[0:8]),
+		Size:   binary.BigEndian.Uint64(buf[8:16]),
+	}
+	if footerLen == tableFooterLen {
+		footer.maxSeq = binary.BigEndian.Uint64(buf[16:24])
+	}
+	footer.compression = Compression(buf[footerLen-9])
+
+	return footer, nil
+}
+
+// tableSeq highest sequence number of the table in f
+// without reading its blocks when the footer records it
+func tableSeq(f *os.File, keys *keyring) (uint64, error) {
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("file.Stat: %w", err)
+	}
+
+	footer, err := readFooter(f, stat.Size())
+	if err != nil {
+		return 0, err
+	} else if footer != nil && footer.maxSeq > 0 {
+		return footer.maxSeq, nil
+	}
+
+	t, err := openTable(f, keys)
+	if err != nil {
+		return 0, err
+	}
+
+	return sstableMaxSeq(t)
+}
+
+// ref keep the table file open until released
+func (t *table) ref() {
+	t.refs.Add(1)
+}
+
+// release drop a reference and close the file with the last one
+func (t *table) release() error {
+	if t.refs.Add(-1) > 0 {
+		return nil
+	}
+	return t.f.Close()
+}
+
+func (t *table) legacy(size int64) *table {
+	t.flat = true
+	if size > 0 {
+		t.blocks = append(t.blocks, &pb.BlockHandle{Offset: 0, Size: uint64(size)})
+	}
+	return t
+}
+
+// readBlock read data block i
+func (t *table) readBlock(i int) ([]byte, error) {
+	buf, err := t.read(t.blocks[i])
+	if err != nil {
+		return nil, fmt.Errorf("block %d: %w", i, err)
+	}
+	return buf, nil
+}
+
+// read block at h verifying its checksum
+func (t *table) read(h *pb.BlockHandle) ([]byte, error) {
+	buf := make([]byte, h.Size)
+	_, err := t.f.ReadAt(buf, int64(h.Offset))
+	if err != nil {
+		return nil, fmt.Errorf("read block: %w", err)
+	}
+
+	if !t.trailers {
+		return buf, nil
+	}
+
+	if len(buf) < blockTrailerLen {
+		return nil, fmt.Errorf("%s: short block: %w", filepath.Base(t.f.Name()), ErrChecksum)
+	}
+
+	n := len(buf) - blockTrailerLen
+	if crc32.Checksum(buf[:n+1], castagnoli) != binary.BigEndian.Uint32(buf[n+1:]) {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(t.f.Name()), ErrChecksum)
+	}
+
+	typ := buf[n]
+	block := buf[:n]
+	if typ&blockEncrypted != 0 {
+		block, err = t.keys.decrypt(block, blockAAD(h.Offset))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(t.f.Name()), err)
+		}
+	}
+
+	return decompress(Compression(typ&^blockEncrypted), block)
+}
+
+// blockAAD bind sealed blocks to their position in the table
+func blockAAD(offset uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, offset)
+}
+
+func readRecord(r io.ByteReader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := make([]byte, length)
+	for i := range payload {
+		payload[i], err = r.ReadByte()
+		if err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+	}
+
+	return payload, nil
+}
+
+// tableWriter writes sorted records into fixed size blocks
+type tableWriter struct {
+	f           *os.File
+	blockSize   int
+	compression Compression
+	keys        *keyring
+
+	block    bytes.Buffer
+	lastKey  string
+	maxSeq   uint64
+	offset   uint64
+	index    []*pb.BlockHandle
+	tableSeq uint64
+	keyIDs   map[uint32]struct{}
+}
+
+func newTableWriter(f *os.File, blockSize int, compression Compression, keys *keyring) *tableWriter {
+	return &tableWriter{
+		f:           f,
+		blockSize:   blockSize,
+		compression: compression,
+		keys:        keys,
+		index:       make([]*pb.BlockHandle, 0),
+		keyIDs:      make(map[uint32]struct{}),
+	}
+}
+
+func (w *tableWriter) add(key string, seq uint64, payload []byte) error {
+	err := writeRecord(&w.block, payload)
+	if err != nil {
+		return err
+	}
+	w.lastKey = key
+	w.maxSeq = max(w.maxSeq, seq)
+	w.tableSeq = max(w.tableSeq, seq)
+
+	if w.block.Len() >= w.blockSize {
+		return w.flushBlock()
+	}
+
+	return nil
+}
+
+func (w *tableWriter) flushBlock() error {
+	if w.block.Len() == 0 {
+		return nil
+	}
+
+	h, err := w.write(w.block.Bytes())
+	if err != nil {
+		return err
+	}
+
+	h.LastKey = w.lastKey
+	h.MaxSeq = w.maxSeq
+	w.index = append(w.index, h)
+	w.block.Reset()
+	w.maxSeq = 0
+
+	return nil
+}
+
+// write compress block and append it with its trailer
+func (w *tableWriter) write(block []byte) (*pb.BlockHandle, error) {
+	compression, buf, err := compress(w.compression, block)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := byte(compression)
+	if w.keys != nil {
+		var id uint32
+		id, buf = w.keys.encrypt(buf, blockAAD(w.offset))
+		w.keyIDs[id] = struct{}{}
+		typ |= blockEncrypted
+	}
+
+	buf = append(buf, typ)
+	buf = binary.BigEndian.AppendUint32(buf, crc32.Checksum(buf, castagnoli))
+
+	n, err := w.f.Write(buf)
+	if err != nil {
+		return nil, fmt.Errorf("write block: %w", err)
+	}
+
+	h := &pb.BlockHandle{
+		Offset: w.offset,
+		Size:   uint64(n),
+	}
+	w.offset += uint64(n)
+
+	return h, nil
+}
+
+// finish write the remaining block, index block and footer
+func (w *tableWriter) finish() error {
+	err := w.flushBlock()
+	if err != nil {
+		return err
+	}
+
+	var index bytes.Buffer
+	for _, h := range w.index {
+		pbbytes, err := proto.Marshal(h)
+		if err != nil {
+			return fmt.Errorf("proto.Marshal: %w", err)
+		}
+
+		err = writeRecord(&index, pbbytes)
+		if err != nil {
+			return err
+		}
+	}
+
+	h, err := w.write(index.Bytes())
+	if err != nil {
+		return fmt.Errorf("write index block: %w", err)
+	}
+
+	footer := make([]byte, tableFooterLen)
+	binary.BigEndian.PutUint64(footer[0:8], h.Offset)
+	binary.BigEndian.PutUint64(footer[8:16], h.Size)
+	binary.BigEndian.PutUint64(footer[16:24], w.tableSeq)
+	footer[24] = byte(w.compression)
+	binary.BigEndian.PutUint64(footer[25:], tableMagic)
+
+	_, err = w.f.Write(footer)
+	if err != nil {
+		return fmt.Errorf("write footer: %w", err)
+	}
+
+	return w.f.Sync()
+}
+
+// sstableCursor sequential reader over a sorted string table
+type sstableCursor struct {
+	t     *table
+	block int
+	r     *bytes.Reader
+
+	k  string
+	s  uint64
+	kv []byte
+	ok bool
+}
+
+func newSSTableCursor(t *table) (*sstableCursor, error) {
+	c := &sstableCursor{
+		t:     t,
+		block: -1,
+		r:     bytes.NewReader(nil),
+	}
+
+	err := c.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *sstableCursor) valid() bool {
+	return c.ok
+}
+
+func (c *sstableCursor) key() string {
+	return c.k
+}
+
+func (c *sstableCursor) seq() uint64 {
+	return c.s
+}
+
+func (c *sstableCursor) payload() []byte {
+	return c.kv
+}
+
+func (c *sstableCursor) advance() error {
+	for c.r.Len() == 0 {
+		if c.block+1 >= len(c.t.blocks) {
+			c.ok = false
+			return nil
+		}
+
+		err := c.load(c.block + 1)
+		if err != nil {
+			return err
+		}
+	}
+
+	payload, err := readRecord(c.r)
+	if err != nil {
+		return fmt.Errorf("read record: %w", err)
+	}
+
+	keyvalue, err := unmarshalKeyValue(payload)
+	if err != nil {
+		return fmt.Errorf("unmarshalKeyValue: %w", err)
+	}
+
+	c.k = keyvalue.Key
+	c.s = keyvalue.Seq
+	c.kv = payload
+	c.ok = true
+
+	return nil
+}
+
+func (c *sstableCursor) load(block int) error {
+	buf, err := c.t.readBlock(block)
+	if err != nil {
+		return err
+	}
+
+	c.block = block
+	c.r = bytes.NewReader(buf)
+
+	return nil
+}
+
+// seek advance cursor to the first key >= start
+func (c *sstableCursor) seek(start string) error {
+	if !c.t.flat && c.ok && strings.Compare(c.k, start) < 0 {
+		// skip whole blocks that end before start
+		i := sort.Search(len(c.t.blocks), func(i int) bool {
+			return strings.Compare(c.t.blocks[i].LastKey, start) >= 0
+		})
+		if i >= len(c.t.blocks) {
+			c.ok = false
+			return nil
+		}
+
+		if i > c.block {
+			err := c.load(i)
+			if err != nil {
+				return err
+			}
+
+			err = c.advance()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for c.ok && strings.Compare(c.k, start) < 0 {
+		err := c.advance()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sstableGet scan sorted string table for the newest version of key visible at seq
+func sstableGet(t *table, key string, seq uint64) ([]byte, error) {
+	c, err := newSSTableCursor(t)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.seek(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// versions are ordered newest first
+	for c.valid() && c.key() == key {
+		if c.seq() <= seq {
+			return c.payload(), nil
+		}
+
+		err = c.advance()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// sstableMaxSeq highest sequence number written to the table
+func sstableMaxSeq(t *table) (uint64, error) {
+	if t.maxSeq > 0 {
+		return t.maxSeq, nil
+	}
+
+	if t.trailers {
+		var seq uint64
+		for _, h := range t.blocks {
+			seq = max(seq, h.MaxSeq)
+		}
+		return seq, nil
+	}
+
+	c, err := newSSTableCursor(t)
+	if err != nil {
+		return 0, err
+	}
+
+	var seq uint64
+	for c.valid() {
+		seq = max(seq, c.seq())
+
+		err = c.advance()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,166 @@
+package keyvalue
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
+)
+
+// Subscription ordered and resumable feed of committed changes
+//
+// changes still held in write ahead log segments are replayed
+// from disk before live changes follow without gaps. a consumer
+// resumes after a restart by subscribing from the sequence number
+// after the last change it processed
+type Subscription struct {
+	lsm *LSM
+	// next sequence number to deliver, zero for the oldest retained
+	next    uint64
+	pending []Change
+	w       *Watcher
+	closed  bool
+}
+
+// Subscribe feed of changes committed from sequence number fromSeq,
+// zero starts at the oldest change still retained
+func (l *LSM) Subscribe(fromSeq uint64) *Subscription {
+	return &Subscription{
+		lsm:     l,
+		next:    fromSeq,
+		pending: make([]Change, 0),
+	}
+}
+
+// Next block until the next change is committed or ctx is done
+//
+// returns ErrTruncated when changes from the requested sequence
+// number are no longer retained and ErrClosed once the store closes
+func (s *Subscription) Next(ctx context.Context) (Change, error) {
+	for {
+		if s.closed {
+			return Change{}, ErrClosed
+		}
+
+		for len(s.pending) > 0 {
+			change := s.pending[0]
+			s.pending = s.pending[1:]
+			if change.Seq >= s.next {
+				s.next = change.Seq + 1
+				return change, nil
+			}
+		}
+
+		if s.w == nil {
+			err := s.catchUp()
+			if err != nil {
+				return Change{}, err
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return Change{}, ctx.Err()
+		case change, ok := <-s.w.Changes():
+			if ok {
+				s.pending = append(s.pending, change)
+				continue
+			}
+
+			err := s.w.Err()
+			s.w = nil
+			if !errors.Is(err, ErrWatcherLagged) {
+				return Change{}, ErrClosed
+			}
+			// missed changes are read back from disk
+		}
+	}
+}
+
+// Close stop the subscription
+func (s *Subscription) Close() {
+	s.closed = true
+	s.pending = nil
+	if s.w != nil {
+		s.w.Close()
+		s.w = nil
+	}
+}
+
+// catchUp queue committed changes read from disk and
+// register a watcher for the changes that follow
+//
+// segments are listed and the watcher registered under
+// writeMu so no batch is committed in between
+func (s *Subscription) catchUp() error {
+	l := s.lsm
+
+	l.writeMu.Lock()
+	select {
+	case <-l.done:
+		l.writeMu.Unlock()
+		return ErrClosed
+	default:
+	}
+
+	l.mu.RLock()
+	seq := l.seq
+	paths := make([]string, 0, len(l.retained)+len(l.immutable)+1)
+	for _, wal := range l.retained {
+		paths = append(paths, wal.path)
+	}
+	for _, mt := range append(l.immutable[:len(l.immutable):len(l.immutable)], l.memtable) {
+		for _, wal := range mt.wals {
+			paths = append(paths, wal.path)
+		}
+	}
+	l.mu.RUnlock()
+
+	s.w = l.Watch("")
+	l.writeMu.Unlock()
+
+	if s.next > seq {
+		return nil
+	}
+
+	var first uint64
+	for _, path := range paths {
+		err := readSegment(path, l.keys, func(batch *pb.Batch) error {
+			if first == 0 {
+				first = batch.Seq
+			}
+
+			if batch.Seq < s.next || batch.Seq > seq {
+				return nil
+			}
+
+			if change, ok := newChange(batch, ""); ok {
+				s.pending = append(s.pending, change)
+			}
+			return nil
+		})
+		// segments expire oldest first, one removed since
+		// it was listed only matters if first is too new
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			s.reset()
+			return err
+		}
+	}
+
+	if s.next > 0 && (first == 0 || first > s.next) {
+		s.reset()
+		return ErrTruncated
+	}
+
+	return nil
+}
+
+func (s *Subscription) reset() {
+	s.pending = s.pending[:0]
+	s.w.Close()
+	s.w = nil
+}
@@ -0,0 +1,159 @@
+package keyvalue_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+const (
+	subscribeTestDir = "testfiles_subscribe"
+)
+
+func TestSubscribe(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	err := os.Mkdir(subscribeTestDir, os.ModePerm)
+	if err != nil {
+		t.Fatalf("failed to create working directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(subscribeTestDir) }()
+
+	opts := []keyvalue.Option{
+		keyvalue.WithMemtableSize(256),
+		keyvalue.WithRetention(1000),
+	}
+
+	lsm, err := keyvalue.New(subscribeTestDir, opts...)
+	if err != nil {
+		t.Fatalf("failed to open lsm: %v", err)
+	}
+
+	// enough batches to flush several memtables
+	for i := range 100 {
+		assert.NoError(lsm.Put(fmt.Sprintf("key_%03d", i), []byte("value"), nil, -1))
+	}
+	assert.NoError(lsm.Close())
+
+	lsm, err = keyvalue.New(subscribeTestDir, opts...)
+	if err != nil {
+		t.Fatalf("failed to reopen lsm: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	t.Run("replay", func(t *testing.T) {
+		sub := lsm.Subscribe(1)
+		defer sub.Close()
+
+		for i := range 100 {
+			change, err := sub.Next(ctx)
+			assert.NoError(err)
+			assert.Equal(uint64(i+1), change.Seq)
+			assert.Equal(fmt.Sprintf("key_%03d", i), change.Events[0].Key)
+		}
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		sub := lsm.Subscribe(90)
+		defer sub.Close()
+
+		change, err := sub.Next(ctx)
+		assert.NoError(err)
+		assert.Equal(uint64(90), change.Seq)
+	})
+
+	t.Run("live", func(t *testing.T) {
+		sub := lsm.Subscribe(101)
+		defer sub.Close()
+
+		go func() {
+			b := keyvalue.NewBatch()
+			b.Put("key_100", []byte("value"), map[string]string{"kind": "live"}, -1)
+			b.Delete("key_000")
+			_ = lsm.Write(b)
+		}()
+
+		change, err := sub.Next(ctx)
+		assert.NoError(err)
+		assert.Equal(uint64(101), change.Seq)
+		if assert.Len(change.Events, 2) {
+			assert.Equal("key_100", change.Events[0].Key)
+			assert.True(change.Events[1].Deleted)
+		}
+	})
+
+	t.Run("lagged", func(t *testing.T) {
+		sub := lsm.Subscribe(102)
+		defer sub.Close()
+
+		assert.NoError(lsm.Put("lag_start", []byte("value"), nil, -1))
+		change, err := sub.Next(ctx)
+		assert.NoError(err)
+		assert.Equal(uint64(102), change.Seq)
+
+		// overflow the live buffer, missed changes are read from disk
+		for i := range 200 {
+			assert.NoError(lsm.Put(fmt.Sprintf("lag_%03d", i), []byte("value"), nil, -1))
+		}
+
+		for i := range 200 {
+			change, err := sub.Next(ctx)
+			assert.NoError(err)
+			assert.Equal(uint64(103+i), change.Seq)
+		}
+	})
+}
+
+func TestSubscribeTruncated(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	err := os.Mkdir(subscribeTestDir, os.ModePerm)
+	if err != nil {
+		t.Fatalf("failed to create working directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(subscribeTestDir) }()
+
+	lsm, err := keyvalue.New(subscribeTestDir, keyvalue.WithMemtableSize(256))
+	if err != nil {
+		t.Fatalf("failed to open lsm: %v", err)
+	}
+
+	for i := range 100 {
+		assert.NoError(lsm.Put(fmt.Sprintf("key_%03d", i), []byte("value"), nil, -1))
+	}
+	assert.NoError(lsm.Close())
+
+	lsm, err = keyvalue.New(subscribeTestDir, keyvalue.WithMemtableSize(256))
+	if err != nil {
+		t.Fatalf("failed to reopen lsm: %v", err)
+	}
+
+	sub := lsm.Subscribe(1)
+	_, err = sub.Next(ctx)
+	assert.ErrorIs(err, keyvalue.ErrTruncated)
+	sub.Close()
+
+	// oldest retained change onwards
+	sub = lsm.Subscribe(0)
+	change, err := sub.Next(ctx)
+	assert.NoError(err)
+	assert.Greater(change.Seq, uint64(1))
+	sub.Close()
+
+	sub = lsm.Subscribe(0)
+	assert.NoError(lsm.Close())
+	_, err = sub.Next(ctx)
+	assert.ErrorIs(err, keyvalue.ErrClosed)
+}
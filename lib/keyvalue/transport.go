@@ -0,0 +1,295 @@
+package keyvalue
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/trevatk/tbd/lib/protocol"
+	pb "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
+)
+
+type transport struct {
+	pb.UnimplementedKeyValueServiceServer
+
+	logger *slog.Logger
+	store  *LSM
+}
+
+// NewTransport return key value service gateway transport implementation
+func NewTransport(logger *slog.Logger, store *LSM) (*grpc.ServiceDesc, pb.KeyValueServiceServer) {
+	return &pb.KeyValueService_ServiceDesc, &transport{
+		logger: logger,
+		store:  store,
+	}
+}
+
+// Get
+func (t *transport) Get(ctx context.Context, in *pb.GetRequest) (*pb.GetResponse, error) {
+	if err := protocol.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	} else if isReservedKey(in.Key) {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	v := t.store.current()
+	defer v.release()
+
+	keyvalue, err := v.get(in.Key, v.seq)
+	if errors.Is(err, ErrNotFound) {
+		return nil, protocol.ErrNotFound()
+	} else if err != nil {
+		t.logger.ErrorContext(ctx, "failed to get key", "error", err)
+		return nil, protocol.ErrInternal()
+	}
+
+	return &pb.GetResponse{
+		Entry: &pb.Entry{
+			Key:    keyvalue.Key,
+			Value:  keyvalue.Value,
+			Indice: keyvalue.Indice,
+			Ttl:    keyvalue.Ttl,
+		},
+	}, nil
+}
+
+// Put
+func (t *transport) Put(ctx context.Context, in *pb.PutRequest) (*pb.PutResponse, error) {
+	if err := protocol.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	err := t.store.Put(in.Entry.Key, in.Entry.Value, in.Entry.Indice, in.Entry.Ttl)
+	if err != nil {
+		return nil, t.writeErr(ctx, err)
+	}
+
+	return &pb.PutResponse{}, nil
+}
+
+// Delete
+func (t *transport) Delete(ctx context.Context, in *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	if err := protocol.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	err := t.store.Delete(in.Key)
+	if err != nil {
+		return nil, t.writeErr(ctx, err)
+	}
+
+	return &pb.DeleteResponse{}, nil
+}
+
+// Scan
+func (t *transport) Scan(in *pb.ScanRequest, stream grpc.ServerStreamingServer[pb.ScanResponse]) error {
+	if err := protocol.Validate(in); err != nil {
+		return protocol.ErrInvalidArgument()
+	}
+
+	snapshot := t.store.Snapshot()
+	defer snapshot.Release()
+
+	var it Iterator
+	if in.IndexName != "" {
+		it = snapshot.GetByIndex(in.IndexName, in.IndexValue)
+	} else {
+		it = snapshot.Scan(in.Prefix)
+	}
+	defer it.Close()
+
+	var sent int64
+	for it.HasNext() && (in.Limit == 0 || sent < in.Limit) {
+		key, _, err := it.Next()
+		if err != nil {
+			t.logger.ErrorContext(stream.Context(), "failed to scan", "error", err)
+			return protocol.ErrInternal()
+		}
+
+		// iterators only expose the value, read
+		// the full record at the same snapshot
+		keyvalue, err := t.store.get(key, snapshot.Seq())
+		if err != nil {
+			t.logger.ErrorContext(stream.Context(), "failed to read scanned key", "error", err)
+			return protocol.ErrInternal()
+		}
+
+		err = stream.Send(&pb.ScanResponse{
+			Entry: &pb.Entry{
+				Key:    keyvalue.Key,
+				Value:  keyvalue.Value,
+				Indice: keyvalue.Indice,
+				Ttl:    keyvalue.Ttl,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		sent++
+	}
+
+	return nil
+}
+
+// Batch
+func (t *transport) Batch(ctx context.Context, in *pb.BatchRequest) (*pb.BatchResponse, error) {
+	if err := protocol.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	b := NewBatch()
+	for _, op := range in.Operations {
+		switch o := op.Op.(type) {
+		case *pb.Operation_Put:
+			b.Put(o.Put.Key, o.Put.Value, o.Put.Indice, o.Put.Ttl)
+		case *pb.Operation_Delete:
+			b.Delete(o.Delete)
+		}
+	}
+
+	err := t.store.Write(b)
+	if err != nil {
+		return nil, t.writeErr(ctx, err)
+	}
+
+	return &pb.BatchResponse{}, nil
+}
+
+// Watch
+func (t *transport) Watch(in *pb.WatchRequest, stream grpc.ServerStreamingServer[pb.WatchResponse]) error {
+	if err := protocol.Validate(in); err != nil {
+		return protocol.ErrInvalidArgument()
+	}
+
+	w := t.store.Watch(in.Prefix)
+	defer w.Close()
+
+	// headers tell the client the watch is registered
+	err := stream.SendHeader(metadata.MD{})
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case change, ok := <-w.Changes():
+			if !ok {
+				t.logger.WarnContext(stream.Context(), "watcher stopped", "error", w.Err())
+				return protocol.ErrInternal()
+			}
+
+			err := stream.Send(newWatchResponse(change, in.Prefix))
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Subscribe
+func (t *transport) Subscribe(in *pb.SubscribeRequest, stream grpc.ServerStreamingServer[pb.WatchResponse]) error {
+	if err := protocol.Validate(in); err != nil {
+		return protocol.ErrInvalidArgument()
+	}
+
+	sub := t.store.Subscribe(in.FromSeq)
+	defer sub.Close()
+
+	for {
+		change, err := sub.Next(stream.Context())
+		if errors.Is(err, ErrTruncated) {
+			return protocol.ErrOutOfRange()
+		} else if stream.Context().Err() != nil {
+			return nil
+		} else if err != nil {
+			t.logger.WarnContext(stream.Context(), "subscription stopped", "error", err)
+			return protocol.ErrInternal()
+		}
+
+		resp := newWatchResponse(change, in.Prefix)
+		if len(resp.Events) == 0 {
+			continue
+		}
+
+		err = stream.Send(resp)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Compact
+func (t *transport) Compact(ctx context.Context, _ *pb.CompactRequest) (*pb.CompactResponse, error) {
+	err := t.store.Compact()
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to compact", "error", err)
+		return nil, protocol.ErrInternal()
+	}
+
+	return &pb.CompactResponse{}, nil
+}
+
+// RotateKey
+func (t *transport) RotateKey(ctx context.Context, in *pb.RotateKeyRequest) (*pb.RotateKeyResponse, error) {
+	err := t.store.RotateKey()
+	if errors.Is(err, ErrNotEncrypted) {
+		return nil, protocol.ErrFailedPrecondition()
+	} else if err != nil {
+		t.logger.ErrorContext(ctx, "failed to rotate data key", "error", err)
+		return nil, protocol.ErrInternal()
+	}
+
+	if in.Compact {
+		_, err = t.Compact(ctx, &pb.CompactRequest{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &pb.RotateKeyResponse{}, nil
+}
+
+func (t *transport) writeErr(ctx context.Context, err error) error {
+	if errors.Is(err, ErrInvalidKey) || errors.Is(err, ErrInvalidIndex) {
+		return protocol.ErrInvalidArgument()
+	}
+
+	t.logger.ErrorContext(ctx, "failed to write", "error", err)
+	return protocol.ErrInternal()
+}
+
+func newWatchResponse(change Change, prefix string) *pb.WatchResponse {
+	resp := &pb.WatchResponse{
+		Seq:    change.Seq,
+		Events: make([]*pb.Event, 0, len(change.Events)),
+	}
+
+	for _, e := range change.Events {
+		if !strings.HasPrefix(e.Key, prefix) {
+			continue
+		}
+
+		typ := pb.Event_TYPE_PUT
+		if e.Deleted {
+			typ = pb.Event_TYPE_DELETE
+		}
+
+		resp.Events = append(resp.Events, &pb.Event{
+			Type: typ,
+			Entry: &pb.Entry{
+				Key:    e.Key,
+				Value:  e.Value,
+				Indice: e.Indice,
+				Ttl:    e.TTL,
+			},
+		})
+	}
+
+	return resp
+}
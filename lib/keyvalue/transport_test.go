@@ -0,0 +1,164 @@
+package keyvalue_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+	"github.com/trevatk/tbd/lib/protocol"
+
+	pb "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
+)
+
+const (
+	transportTestDir = "testfiles_transport"
+)
+
+func TestTransport(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	err := os.Mkdir(transportTestDir, os.ModePerm)
+	if err != nil {
+		t.Fatalf("failed to create working directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(transportTestDir) }()
+
+	lsm, err := keyvalue.New(transportTestDir)
+	if err != nil {
+		t.Fatalf("failed to open lsm: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	logger := slog.Default()
+	desc, service := keyvalue.NewTransport(logger, lsm)
+
+	ts := protocol.NewTestServer(
+		protocol.WithTestTransports([]protocol.Transport{{ServiceDesc: desc, Service: service}}),
+		protocol.WithTestLogger(logger),
+	)
+	go ts.Start(ctx)
+	defer ts.Stop(ctx)
+
+	conn, err := protocol.NewTestConn(ctx, ts.BufDialer)
+	if err != nil {
+		t.Fatalf("failed to create test conn: %v", err)
+	}
+	client := pb.NewKeyValueServiceClient(conn)
+
+	watch, err := client.Watch(ctx, &pb.WatchRequest{Prefix: "user_"})
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+
+	// headers are sent once the watch is registered
+	_, err = watch.Header()
+	assert.NoError(err)
+
+	// a write to another prefix is not delivered
+	_, err = client.Put(ctx, &pb.PutRequest{Entry: &pb.Entry{Key: "realm_0", Value: []byte("tbd"), Ttl: -1}})
+	assert.NoError(err)
+
+	_, err = client.Put(ctx, &pb.PutRequest{
+		Entry: &pb.Entry{Key: "user_0", Value: []byte("alice"), Indice: map[string]string{"realm": "tbd"}, Ttl: -1},
+	})
+	assert.NoError(err)
+
+	_, err = client.Batch(ctx, &pb.BatchRequest{
+		Operations: []*pb.Operation{
+			{Op: &pb.Operation_Put{Put: &pb.Entry{Key: "user_1", Value: []byte("bob"), Ttl: -1}}},
+			{Op: &pb.Operation_Put{Put: &pb.Entry{Key: "user_2", Value: []byte("carol"), Ttl: -1}}},
+			{Op: &pb.Operation_Delete{Delete: "user_0"}},
+		},
+	})
+	assert.NoError(err)
+
+	resp, err := client.Get(ctx, &pb.GetRequest{Key: "user_1"})
+	assert.NoError(err)
+	assert.Equal("bob", string(resp.Entry.Value))
+
+	_, err = client.Get(ctx, &pb.GetRequest{Key: "user_0"})
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	_, err = client.Put(ctx, &pb.PutRequest{Entry: &pb.Entry{Key: "\x00index", Value: []byte("x")}})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Batch(ctx, &pb.BatchRequest{})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	keys := scanKeys(t, ctx, client, &pb.ScanRequest{Prefix: "user_"})
+	assert.Equal([]string{"user_1", "user_2"}, keys)
+
+	keys = scanKeys(t, ctx, client, &pb.ScanRequest{Limit: 1})
+	assert.Equal([]string{"realm_0"}, keys)
+
+	_, err = client.Delete(ctx, &pb.DeleteRequest{Key: "user_2"})
+	assert.NoError(err)
+
+	change, err := watch.Recv()
+	assert.NoError(err)
+	if assert.Len(change.Events, 1) {
+		assert.Equal(pb.Event_TYPE_PUT, change.Events[0].Type)
+		assert.Equal("user_0", change.Events[0].Entry.Key)
+		assert.Equal("tbd", change.Events[0].Entry.Indice["realm"])
+	}
+
+	change, err = watch.Recv()
+	assert.NoError(err)
+	if assert.Len(change.Events, 3) {
+		assert.Equal(pb.Event_TYPE_DELETE, change.Events[2].Type)
+	}
+
+	change, err = watch.Recv()
+	assert.NoError(err)
+	if assert.Len(change.Events, 1) {
+		assert.Equal(pb.Event_TYPE_DELETE, change.Events[0].Type)
+		assert.Equal("user_2", change.Events[0].Entry.Key)
+	}
+
+	_, err = client.Compact(ctx, &pb.CompactRequest{})
+	assert.NoError(err)
+
+	_, err = client.RotateKey(ctx, &pb.RotateKeyRequest{Compact: true})
+	assert.Equal(codes.FailedPrecondition, status.Code(err))
+
+	// the same changes are replayed from the log on subscribe
+	sub, err := client.Subscribe(ctx, &pb.SubscribeRequest{FromSeq: 1, Prefix: "user_"})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	for _, expected := range []uint64{2, 3, 4} {
+		change, err = sub.Recv()
+		assert.NoError(err)
+		assert.Equal(expected, change.Seq)
+	}
+}
+
+func scanKeys(t *testing.T, ctx context.Context, client pb.KeyValueServiceClient, in *pb.ScanRequest) []string {
+	stream, err := client.Scan(ctx, in)
+	if err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+
+	keys := make([]string, 0)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return keys
+		} else if err != nil {
+			t.Fatalf("failed to receive scan: %v", err)
+		}
+		keys = append(keys, resp.Entry.Key)
+	}
+}
@@ -0,0 +1,66 @@
+package keyvalue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pbvault "github.com/trevatk/tbd/lib/protocol/vault/v1"
+)
+
+const vaultTimeout = 5 * time.Second
+
+// vaultKeyWrapper wraps data keys with a key encryption key held by the vault service
+type vaultKeyWrapper struct {
+	client pbvault.VaultServiceClient
+	keyID  string
+}
+
+// NewVaultKeyWrapper wrap data keys with the vault key encryption key named keyID
+//
+// rotating the vault key is transparent to the store
+// as long as the vault can unwrap older versions
+func NewVaultKeyWrapper(client pbvault.VaultServiceClient, keyID string) KeyWrapper {
+	return &vaultKeyWrapper{
+		client: client,
+		keyID:  keyID,
+	}
+}
+
+// KeyID
+func (w *vaultKeyWrapper) KeyID() string {
+	return w.keyID
+}
+
+// Wrap
+func (w *vaultKeyWrapper) Wrap(dek []byte) (string, []byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+	defer cancel()
+
+	resp, err := w.client.WrapKey(ctx, &pbvault.WrapKeyRequest{
+		KeyId:     w.keyID,
+		Plaintext: dek,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("vault.WrapKey: %w", err)
+	}
+
+	// key versions are tracked by the vault inside the ciphertext
+	return w.keyID, resp.Ciphertext, nil
+}
+
+// Unwrap
+func (w *vaultKeyWrapper) Unwrap(id string, wrapped []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+	defer cancel()
+
+	resp, err := w.client.UnwrapKey(ctx, &pbvault.UnwrapKeyRequest{
+		KeyId:      id,
+		Ciphertext: wrapped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("vault.UnwrapKey: %w", err)
+	}
+
+	return resp.Plaintext, nil
+}
@@ -0,0 +1,71 @@
+package keyvalue
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Verify read every block of every sorted string table
+//
+// checksums and record order are checked, one error
+// is reported for every corrupt table
+func (l *LSM) Verify() error {
+	v := l.current()
+	defer v.release()
+
+	var errs []error
+	for _, t := range v.sstables {
+		err := verifyTable(t)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(t.f.Name()), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyFile verify the sorted string table at path
+func verifyFile(path string) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("os.Open: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	t, err := openTable(f, nil)
+	if err != nil {
+		return fmt.Errorf("openTable: %w", err)
+	}
+
+	return verifyTable(t)
+}
+
+func verifyTable(t *table) error {
+	c, err := newSSTableCursor(t)
+	if err != nil {
+		return err
+	}
+
+	var (
+		prevKey string
+		prevSeq uint64
+		first   = true
+	)
+	for c.valid() {
+		cmp := strings.Compare(prevKey, c.key())
+		if !first && (cmp > 0 || cmp == 0 && prevSeq <= c.seq()) {
+			return fmt.Errorf("key %q out of order: %w", c.key(), ErrCorrupt)
+		}
+		prevKey, prevSeq, first = c.key(), c.seq(), false
+
+		err = c.advance()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package keyvalue_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+const (
+	verifyTestDir = "testfiles_verify"
+)
+
+func TestCompression(t *testing.T) {
+	for _, compression := range []keyvalue.Compression{keyvalue.NoCompression, keyvalue.Snappy, keyvalue.Zstd} {
+		t.Run(compression.String(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			dir := filepath.Join(verifyTestDir, compression.String())
+			err := os.MkdirAll(dir, os.ModePerm)
+			if err != nil {
+				t.Fatalf("failed to create working directory: %v", err)
+			}
+			defer func() { _ = os.RemoveAll(verifyTestDir) }()
+
+			opts := []keyvalue.Option{
+				keyvalue.WithCompression(compression),
+				keyvalue.WithBlockSize(256),
+				keyvalue.WithMemtableSize(1024),
+			}
+
+			lsm, err := keyvalue.New(dir, opts...)
+			if err != nil {
+				t.Fatalf("failed to create lsm: %v", err)
+			}
+
+			for i := range 100 {
+				key := fmt.Sprintf("key_%03d", i)
+				assert.NoError(lsm.Put(key, []byte("compressible compressible compressible"), nil, -1))
+			}
+			assert.NoError(lsm.Close())
+
+			lsm, err = keyvalue.New(dir, opts...)
+			if err != nil {
+				t.Fatalf("failed to reopen lsm: %v", err)
+			}
+			defer func() { _ = lsm.Close() }()
+
+			assert.NoError(lsm.Verify())
+
+			vbytes, err := lsm.Get("key_042")
+			assert.NoError(err)
+			assert.Equal("compressible compressible compressible", string(vbytes))
+		})
+	}
+}
+
+func TestVerifyCorrupt(t *testing.T) {
+	assert := assert.New(t)
+
+	err := os.Mkdir(verifyTestDir, os.ModePerm)
+	if err != nil {
+		t.Fatalf("failed to create working directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(verifyTestDir) }()
+
+	opts := []keyvalue.Option{
+		keyvalue.WithCompression(keyvalue.Snappy),
+		keyvalue.WithMemtableSize(1024),
+	}
+
+	lsm, err := keyvalue.New(verifyTestDir, opts...)
+	if err != nil {
+		t.Fatalf("failed to create lsm: %v", err)
+	}
+
+	for i := range 100 {
+		assert.NoError(lsm.Put(fmt.Sprintf("key_%03d", i), []byte("value"), nil, -1))
+	}
+	assert.NoError(lsm.Close())
+
+	// flip a bit in the first data block
+	fp := filepath.Join(verifyTestDir, "sstable_0.data")
+	buf, err := os.ReadFile(fp)
+	if err != nil {
+		t.Fatalf("failed to read table: %v", err)
+	}
+	buf[0] ^= 0x01
+	assert.NoError(os.WriteFile(fp, buf, 0o600))
+
+	lsm, err = keyvalue.New(verifyTestDir, opts...)
+	if err != nil {
+		t.Fatalf("failed to reopen lsm: %v", err)
+	}
+	defer func() { _ = lsm.Close() }()
+
+	err = lsm.Verify()
+	if !errors.Is(err, keyvalue.ErrChecksum) {
+		t.Fatalf("expected checksum error, got %v", err)
+	}
+
+	_, err = lsm.Get("key_000")
+	assert.ErrorIs(err, keyvalue.ErrChecksum)
+}
@@ -1,19 +1,48 @@
 package keyvalue
 
 import (
+	"bufio"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"path/filepath"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
 )
 
 // WAL write ahead log
+//
+// each record is a length prefixed batch so
+// a batch is either replayed whole or not at all
+//
+// records of encrypted stores are sealed with a data key and
+// prefixed by a zero byte, never the first byte of an encoded
+// batch, and the plaintext batch sequence number
+//
+// | 0x00 | seq uvarint | data key id uint32 | nonce | ciphertext |
 type WAL struct {
-	f *os.File
+	f    *os.File
+	path string
+	// sync fsync after every batch
+	sync bool
+	keys *keyring
+	// keyIDs data keys sealing records of the segment
+	keyIDs map[uint32]struct{}
 }
 
-func newWAL(f *os.File) *WAL {
+const walEncrypted byte = 0x00
+
+func newWAL(f *os.File, path string, sync bool, keys *keyring) *WAL {
 	return &WAL{
-		f: f,
+		f:      f,
+		path:   path,
+		sync:   sync,
+		keys:   keys,
+		keyIDs: make(map[uint32]struct{}),
 	}
 }
 
@@ -21,20 +50,176 @@ func newWAL(f *os.File) *WAL {
 func (w *WAL) Size() (int64, error) {
 	stat, err := w.f.Stat()
 	if err != nil {
-		return 0, fmt.Errorf("file.Size: %v", err)
+		return 0, fmt.Errorf("file.Size: %w", err)
 	}
 	return stat.Size(), nil
 }
 
-func (w *WAL) appendEntry(op, key, value string) error {
-	entry := fmt.Sprintf("%s|%s|%s\n", strings.ToUpper(op), key, value)
-	_, err := w.f.WriteString(entry)
+func (w *WAL) appendBatch(batch *pb.Batch) error {
+	pbbytes, err := proto.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	if w.keys != nil {
+		header := binary.AppendUvarint([]byte{walEncrypted}, batch.Seq)
+		id, sealed := w.keys.encrypt(pbbytes, header)
+		w.keyIDs[id] = struct{}{}
+		pbbytes = append(header, sealed...)
+	}
+
+	err = writeRecord(w.f, pbbytes)
+	if err != nil {
+		return fmt.Errorf("writeRecord: %w", err)
+	}
+
+	if !w.sync {
+		return nil
+	}
+
+	err = w.f.Sync()
 	if err != nil {
-		return fmt.Errorf("file.WriteString: %v", err)
+		return fmt.Errorf("file.Sync: %w", err)
 	}
+
 	return nil
 }
 
-func (w *WAL) flush() error {
-	return w.f.Truncate(0)
-}
\ No newline at end of file
+// replay read every complete batch in the log
+func (w *WAL) replay(fn func(*pb.Batch) error) error {
+	return w.records(func(payload []byte) error {
+		if len(payload) > 0 && payload[0] == walEncrypted {
+			seq, n := binary.Uvarint(payload[1:])
+			if n <= 0 {
+				return fmt.Errorf("read record seq: %w", ErrChecksum)
+			}
+
+			header, sealed := payload[:1+n], payload[1+n:]
+			var err error
+			payload, err = w.keys.decrypt(sealed, header)
+			if err != nil {
+				return fmt.Errorf("batch %d: %w", seq, err)
+			}
+			w.keyIDs[binary.BigEndian.Uint32(sealed)] = struct{}{}
+		}
+
+		var batch pb.Batch
+		err := proto.Unmarshal(payload, &batch)
+		if err != nil {
+			return fmt.Errorf("proto.Unmarshal: %w", err)
+		}
+
+		return fn(&batch)
+	})
+}
+
+// maxSeq highest batch sequence number without decrypting records
+func (w *WAL) maxSeq() (uint64, error) {
+	var seq uint64
+	err := w.records(func(payload []byte) error {
+		if len(payload) > 0 && payload[0] == walEncrypted {
+			s, n := binary.Uvarint(payload[1:])
+			if n <= 0 {
+				return fmt.Errorf("read record seq: %w", ErrChecksum)
+			}
+			seq = max(seq, s)
+			return nil
+		}
+
+		var batch pb.Batch
+		err := proto.Unmarshal(payload, &batch)
+		if err != nil {
+			return fmt.Errorf("proto.Unmarshal: %w", err)
+		}
+		seq = max(seq, batch.Seq)
+		return nil
+	})
+	return seq, err
+}
+
+// records read every complete record in the log
+//
+// a torn record at the tail is the result of a crash
+// mid write and is discarded
+func (w *WAL) records(fn func([]byte) error) error {
+	stat, err := w.f.Stat()
+	if err != nil {
+		return fmt.Errorf("file.Stat: %w", err)
+	}
+
+	r := bufio.NewReader(io.NewSectionReader(w.f, 0, stat.Size()))
+
+	for {
+		length, err := binary.ReadUvarint(r)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("read record length: %w", err)
+		}
+
+		payload := make([]byte, length)
+		_, err = io.ReadFull(r, payload)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("read record: %w", err)
+		}
+
+		err = fn(payload)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// remove delete a closed segment once its batches are persisted
+// in a sorted string table and it is no longer retained
+func (w *WAL) remove() error {
+	err := os.Remove(w.path)
+	if err != nil {
+		return fmt.Errorf("os.Remove: %w", err)
+	}
+
+	return nil
+}
+
+// openRetained load the data keys sealing a flushed segment,
+// the file is only opened again by subscribers
+func openRetained(path string, keys *keyring) (*WAL, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("os.Open: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	w := newWAL(f, path, false, keys)
+	err = w.records(func(payload []byte) error {
+		if len(payload) == 0 || payload[0] != walEncrypted {
+			return nil
+		}
+
+		_, n := binary.Uvarint(payload[1:])
+		if n <= 0 || len(payload) < 1+n+keyIDLen {
+			return fmt.Errorf("read record key id: %w", ErrChecksum)
+		}
+		w.keyIDs[binary.BigEndian.Uint32(payload[1+n:])] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// readSegment replay a segment from its own file handle so the
+// writer appending to it or the flusher retiring it is not disturbed
+func readSegment(path string, keys *keyring, fn func(*pb.Batch) error) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("os.Open: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return newWAL(f, path, false, keys).replay(fn)
+}
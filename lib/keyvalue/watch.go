@@ -0,0 +1,146 @@
+package keyvalue
+
+import (
+	"strings"
+	"sync"
+
+	pb "github.com/trevatk/tbd/lib/protocol/lsm/v1"
+)
+
+// watchBuffer committed batches a watcher may fall behind by
+const watchBuffer = 64
+
+// Event change to a single record
+type Event struct {
+	Key     string
+	Value   []byte
+	Indice  map[string]string
+	TTL     int64
+	Deleted bool
+}
+
+// Change events of a committed batch
+type Change struct {
+	Seq    uint64
+	Events []Event
+}
+
+// Watcher live feed of committed changes
+type Watcher struct {
+	lsm    *LSM
+	prefix string
+	ch     chan Change
+
+	once sync.Once
+	err  error
+}
+
+// Watch subscribe to changes of records with key prefix
+// committed after the call
+//
+// a watcher falling more than a few batches behind is closed
+// with ErrWatcherLagged so writers are never blocked
+func (l *LSM) Watch(prefix string) *Watcher {
+	w := &Watcher{
+		lsm:    l,
+		prefix: prefix,
+		ch:     make(chan Change, watchBuffer),
+	}
+
+	l.watchMu.Lock()
+	defer l.watchMu.Unlock()
+
+	l.watchers[w] = struct{}{}
+
+	select {
+	case <-l.done:
+		w.stop(ErrClosed)
+	default:
+	}
+
+	return w
+}
+
+// Changes channel closed once the watcher stops
+func (w *Watcher) Changes() <-chan Change {
+	return w.ch
+}
+
+// Err reason the watcher stopped, nil when closed by the caller
+func (w *Watcher) Err() error {
+	w.lsm.watchMu.Lock()
+	defer w.lsm.watchMu.Unlock()
+	return w.err
+}
+
+// Close stop receiving changes
+func (w *Watcher) Close() {
+	w.lsm.watchMu.Lock()
+	defer w.lsm.watchMu.Unlock()
+	w.stop(nil)
+}
+
+// stop callers must hold watchMu
+func (w *Watcher) stop(err error) {
+	w.once.Do(func() {
+		w.err = err
+		delete(w.lsm.watchers, w)
+		close(w.ch)
+	})
+}
+
+// publish hand a committed batch to every interested watcher
+//
+// callers must hold writeMu so changes are delivered in commit order
+func (l *LSM) publish(batch *pb.Batch) {
+	l.watchMu.Lock()
+	defer l.watchMu.Unlock()
+
+	for w := range l.watchers {
+		change, ok := newChange(batch, w.prefix)
+		if !ok {
+			continue
+		}
+
+		select {
+		case w.ch <- change:
+		default:
+			w.stop(ErrWatcherLagged)
+		}
+	}
+}
+
+// closeWatchers stop every watcher when the store closes
+func (l *LSM) closeWatchers() {
+	l.watchMu.Lock()
+	defer l.watchMu.Unlock()
+
+	for w := range l.watchers {
+		w.stop(ErrClosed)
+	}
+}
+
+// newChange events of batch for keys with prefix, reports
+// whether the batch changed any of them
+func newChange(batch *pb.Batch, prefix string) (Change, bool) {
+	change := Change{
+		Seq:    batch.Seq,
+		Events: make([]Event, 0),
+	}
+
+	for _, entry := range batch.Entries {
+		if isReservedKey(entry.Key) || !strings.HasPrefix(entry.Key, prefix) {
+			continue
+		}
+
+		change.Events = append(change.Events, Event{
+			Key:     entry.Key,
+			Value:   entry.Value,
+			Indice:  entry.Indice,
+			TTL:     entry.Ttl,
+			Deleted: entry.Tombstone,
+		})
+	}
+
+	return change, len(change.Events) > 0
+}
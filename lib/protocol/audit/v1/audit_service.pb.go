@@ -10,7 +10,7 @@ import (
 	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,26 +23,88 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type CreateDecisionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type Outcome int32
+
+const (
+	Outcome_OUTCOME_UNSPECIFIED Outcome = 0
+	Outcome_OUTCOME_ALLOW       Outcome = 1
+	Outcome_OUTCOME_DENY        Outcome = 2
+)
+
+// Enum value maps for Outcome.
+var (
+	Outcome_name = map[int32]string{
+		0: "OUTCOME_UNSPECIFIED",
+		1: "OUTCOME_ALLOW",
+		2: "OUTCOME_DENY",
+	}
+	Outcome_value = map[string]int32{
+		"OUTCOME_UNSPECIFIED": 0,
+		"OUTCOME_ALLOW":       1,
+		"OUTCOME_DENY":        2,
+	}
+)
+
+func (x Outcome) Enum() *Outcome {
+	p := new(Outcome)
+	*p = x
+	return p
+}
+
+func (x Outcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Outcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_audit_v1_audit_service_proto_enumTypes[0].Descriptor()
+}
+
+func (Outcome) Type() protoreflect.EnumType {
+	return &file_audit_v1_audit_service_proto_enumTypes[0]
+}
+
+func (x Outcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Outcome.Descriptor instead.
+func (Outcome) EnumDescriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{0}
+}
+
+// subject, resource and outcome are index values, control
+// characters are rejected in every field
+type DecisionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// subject the decision was made for
+	Subject string `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	// resource access was requested to
+	Resource string  `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
+	Action   string  `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Outcome  Outcome `protobuf:"varint,4,opt,name=outcome,proto3,enum=audit.v1.Outcome" json:"outcome,omitempty"`
+	// version of the policy the decision was evaluated against
+	PolicyVersion string `protobuf:"bytes,5,opt,name=policy_version,json=policyVersion,proto3" json:"policy_version,omitempty"`
+	// request the decision was made for, decisions
+	// are recorded once per request id
+	RequestId     string `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateDecisionRequest) Reset() {
-	*x = CreateDecisionRequest{}
+func (x *DecisionRequest) Reset() {
+	*x = DecisionRequest{}
 	mi := &file_audit_v1_audit_service_proto_msgTypes[0]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateDecisionRequest) String() string {
+func (x *DecisionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateDecisionRequest) ProtoMessage() {}
+func (*DecisionRequest) ProtoMessage() {}
 
-func (x *CreateDecisionRequest) ProtoReflect() protoreflect.Message {
+func (x *DecisionRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_audit_v1_audit_service_proto_msgTypes[0]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -54,31 +116,75 @@ func (x *CreateDecisionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateDecisionRequest.ProtoReflect.Descriptor instead.
-func (*CreateDecisionRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DecisionRequest.ProtoReflect.Descriptor instead.
+func (*DecisionRequest) Descriptor() ([]byte, []int) {
 	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{0}
 }
 
-type CreateDecisionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+func (x *DecisionRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *DecisionRequest) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *DecisionRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *DecisionRequest) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+func (x *DecisionRequest) GetPolicyVersion() string {
+	if x != nil {
+		return x.PolicyVersion
+	}
+	return ""
+}
+
+func (x *DecisionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type DecisionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// hash of the transaction recording the decision
+	TxHash        string `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateDecisionResponse) Reset() {
-	*x = CreateDecisionResponse{}
+func (x *DecisionResponse) Reset() {
+	*x = DecisionResponse{}
 	mi := &file_audit_v1_audit_service_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateDecisionResponse) String() string {
+func (x *DecisionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateDecisionResponse) ProtoMessage() {}
+func (*DecisionResponse) ProtoMessage() {}
 
-func (x *CreateDecisionResponse) ProtoReflect() protoreflect.Message {
+func (x *DecisionResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_audit_v1_audit_service_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -90,22 +196,113 @@ func (x *CreateDecisionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateDecisionResponse.ProtoReflect.Descriptor instead.
-func (*CreateDecisionResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DecisionResponse.ProtoReflect.Descriptor instead.
+func (*DecisionResponse) Descriptor() ([]byte, []int) {
 	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{1}
 }
 
+func (x *DecisionResponse) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+type DecisionFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// empty matches any subject
+	Subject string `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	// empty matches any resource
+	Resource string `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
+	// unspecified matches any outcome
+	Outcome Outcome `protobuf:"varint,3,opt,name=outcome,proto3,enum=audit.v1.Outcome" json:"outcome,omitempty"`
+	// inclusive lower bound of the decision time
+	Start *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	// exclusive upper bound of the decision time
+	End           *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecisionFilter) Reset() {
+	*x = DecisionFilter{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecisionFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecisionFilter) ProtoMessage() {}
+
+func (x *DecisionFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecisionFilter.ProtoReflect.Descriptor instead.
+func (*DecisionFilter) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DecisionFilter) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *DecisionFilter) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *DecisionFilter) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+func (x *DecisionFilter) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *DecisionFilter) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
 type ListDecisionsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int64                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// page size, defaults to 50 when unset
+	Limit int64 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// next_cursor of the previous page, empty for the first page
+	Cursor        string          `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Filter        *DecisionFilter `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListDecisionsRequest) Reset() {
 	*x = ListDecisionsRequest{}
-	mi := &file_audit_v1_audit_service_proto_msgTypes[2]
+	mi := &file_audit_v1_audit_service_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -117,7 +314,7 @@ func (x *ListDecisionsRequest) String() string {
 func (*ListDecisionsRequest) ProtoMessage() {}
 
 func (x *ListDecisionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_audit_v1_audit_service_proto_msgTypes[2]
+	mi := &file_audit_v1_audit_service_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -130,7 +327,7 @@ func (x *ListDecisionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDecisionsRequest.ProtoReflect.Descriptor instead.
 func (*ListDecisionsRequest) Descriptor() ([]byte, []int) {
-	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{2}
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ListDecisionsRequest) GetLimit() int64 {
@@ -140,38 +337,52 @@ func (x *ListDecisionsRequest) GetLimit() int64 {
 	return 0
 }
 
-func (x *ListDecisionsRequest) GetOffset() int64 {
+func (x *ListDecisionsRequest) GetCursor() string {
 	if x != nil {
-		return x.Offset
+		return x.Cursor
 	}
-	return 0
+	return ""
 }
 
-type Tx struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
-	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
-	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
-	Sig           string                 `protobuf:"bytes,4,opt,name=sig,proto3" json:"sig,omitempty"`
+func (x *ListDecisionsRequest) GetFilter() *DecisionFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type DecisionRecord struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// hash of the transaction recording the decision
+	TxHash        string                 `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Resource      string                 `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Outcome       Outcome                `protobuf:"varint,5,opt,name=outcome,proto3,enum=audit.v1.Outcome" json:"outcome,omitempty"`
+	PolicyVersion string                 `protobuf:"bytes,6,opt,name=policy_version,json=policyVersion,proto3" json:"policy_version,omitempty"`
+	RequestId     string                 `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// height of the block sealing the decision, 0 while pending
+	Height        int64 `protobuf:"varint,9,opt,name=height,proto3" json:"height,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Tx) Reset() {
-	*x = Tx{}
-	mi := &file_audit_v1_audit_service_proto_msgTypes[3]
+func (x *DecisionRecord) Reset() {
+	*x = DecisionRecord{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Tx) String() string {
+func (x *DecisionRecord) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Tx) ProtoMessage() {}
+func (*DecisionRecord) ProtoMessage() {}
 
-func (x *Tx) ProtoReflect() protoreflect.Message {
-	mi := &file_audit_v1_audit_service_proto_msgTypes[3]
+func (x *DecisionRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -182,49 +393,86 @@ func (x *Tx) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Tx.ProtoReflect.Descriptor instead.
-func (*Tx) Descriptor() ([]byte, []int) {
-	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use DecisionRecord.ProtoReflect.Descriptor instead.
+func (*DecisionRecord) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Tx) GetHash() string {
+func (x *DecisionRecord) GetTxHash() string {
 	if x != nil {
-		return x.Hash
+		return x.TxHash
 	}
 	return ""
 }
 
-func (x *Tx) GetFrom() string {
+func (x *DecisionRecord) GetSubject() string {
 	if x != nil {
-		return x.From
+		return x.Subject
 	}
 	return ""
 }
 
-func (x *Tx) GetTo() string {
+func (x *DecisionRecord) GetResource() string {
 	if x != nil {
-		return x.To
+		return x.Resource
 	}
 	return ""
 }
 
-func (x *Tx) GetSig() string {
+func (x *DecisionRecord) GetAction() string {
 	if x != nil {
-		return x.Sig
+		return x.Action
+	}
+	return ""
+}
+
+func (x *DecisionRecord) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+func (x *DecisionRecord) GetPolicyVersion() string {
+	if x != nil {
+		return x.PolicyVersion
+	}
+	return ""
+}
+
+func (x *DecisionRecord) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
 	}
 	return ""
 }
 
+func (x *DecisionRecord) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *DecisionRecord) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
 type ListDecisionsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Txs           []*Tx                  `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Decisions []*DecisionRecord      `protobuf:"bytes,2,rep,name=decisions,proto3" json:"decisions,omitempty"`
+	// cursor of the next page, empty on the last page
+	NextCursor    string `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListDecisionsResponse) Reset() {
 	*x = ListDecisionsResponse{}
-	mi := &file_audit_v1_audit_service_proto_msgTypes[4]
+	mi := &file_audit_v1_audit_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -236,7 +484,7 @@ func (x *ListDecisionsResponse) String() string {
 func (*ListDecisionsResponse) ProtoMessage() {}
 
 func (x *ListDecisionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_audit_v1_audit_service_proto_msgTypes[4]
+	mi := &file_audit_v1_audit_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -249,12 +497,547 @@ func (x *ListDecisionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDecisionsResponse.ProtoReflect.Descriptor instead.
 func (*ListDecisionsResponse) Descriptor() ([]byte, []int) {
-	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{4}
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListDecisionsResponse) GetDecisions() []*DecisionRecord {
+	if x != nil {
+		return x.Decisions
+	}
+	return nil
+}
+
+func (x *ListDecisionsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ExportDecisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *DecisionFilter        `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportDecisionsRequest) Reset() {
+	*x = ExportDecisionsRequest{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportDecisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportDecisionsRequest) ProtoMessage() {}
+
+func (x *ExportDecisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportDecisionsRequest.ProtoReflect.Descriptor instead.
+func (*ExportDecisionsRequest) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ExportDecisionsRequest) GetFilter() *DecisionFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type GetProofRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxHash        string                 `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProofRequest) Reset() {
+	*x = GetProofRequest{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProofRequest) ProtoMessage() {}
+
+func (x *GetProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProofRequest.ProtoReflect.Descriptor instead.
+func (*GetProofRequest) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetProofRequest) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+type ProofStep struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sibling hash at this level of the tree
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// sibling is the left child
+	Left          bool `protobuf:"varint,2,opt,name=left,proto3" json:"left,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProofStep) Reset() {
+	*x = ProofStep{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProofStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProofStep) ProtoMessage() {}
+
+func (x *ProofStep) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProofStep.ProtoReflect.Descriptor instead.
+func (*ProofStep) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ProofStep) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *ProofStep) GetLeft() bool {
+	if x != nil {
+		return x.Left
+	}
+	return false
+}
+
+type Proof struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TxHash string                 `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	// hash of the block sealing the transaction
+	BlockHash string `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Height    int64  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	// path from the leaf up to the merkle root
+	Path []*ProofStep `protobuf:"bytes,4,rep,name=path,proto3" json:"path,omitempty"`
+	// position of the transaction in the block
+	Index         int64 `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Proof) Reset() {
+	*x = Proof{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Proof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Proof) ProtoMessage() {}
+
+func (x *Proof) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Proof.ProtoReflect.Descriptor instead.
+func (*Proof) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Proof) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *Proof) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *Proof) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
 }
 
-func (x *ListDecisionsResponse) GetTxs() []*Tx {
+func (x *Proof) GetPath() []*ProofStep {
 	if x != nil {
-		return x.Txs
+		return x.Path
+	}
+	return nil
+}
+
+func (x *Proof) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+type GetBlockHeaderRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// block height, 0 for the tip
+	Height        int64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBlockHeaderRequest) Reset() {
+	*x = GetBlockHeaderRequest{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlockHeaderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockHeaderRequest) ProtoMessage() {}
+
+func (x *GetBlockHeaderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockHeaderRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockHeaderRequest) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetBlockHeaderRequest) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type BlockHeader struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Hash  string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// empty for the genesis block
+	PrevHash   string                 `protobuf:"bytes,2,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
+	Height     int64                  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	MerkleRoot string                 `protobuf:"bytes,4,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Timestamp  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// signature of the hash by the signer
+	Sig string `protobuf:"bytes,6,opt,name=sig,proto3" json:"sig,omitempty"`
+	// public key of the signer, covered by the hash
+	Signer string `protobuf:"bytes,7,opt,name=signer,proto3" json:"signer,omitempty"`
+	// number of transactions under the merkle root
+	TxCount       int64 `protobuf:"varint,8,opt,name=tx_count,json=txCount,proto3" json:"tx_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockHeader) Reset() {
+	*x = BlockHeader{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockHeader) ProtoMessage() {}
+
+func (x *BlockHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockHeader.ProtoReflect.Descriptor instead.
+func (*BlockHeader) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BlockHeader) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetPrevHash() string {
+	if x != nil {
+		return x.PrevHash
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *BlockHeader) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *BlockHeader) GetSig() string {
+	if x != nil {
+		return x.Sig
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetSigner() string {
+	if x != nil {
+		return x.Signer
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetTxCount() int64 {
+	if x != nil {
+		return x.TxCount
+	}
+	return 0
+}
+
+type VerifyChainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyChainRequest) Reset() {
+	*x = VerifyChainRequest{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyChainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyChainRequest) ProtoMessage() {}
+
+func (x *VerifyChainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyChainRequest.ProtoReflect.Descriptor instead.
+func (*VerifyChainRequest) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{12}
+}
+
+type Inconsistency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Height        int64                  `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	BlockHash     string                 `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Inconsistency) Reset() {
+	*x = Inconsistency{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Inconsistency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Inconsistency) ProtoMessage() {}
+
+func (x *Inconsistency) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Inconsistency.ProtoReflect.Descriptor instead.
+func (*Inconsistency) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Inconsistency) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Inconsistency) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *Inconsistency) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type VerifyChainResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Valid bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	// height of the last block verified
+	Height int64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// first inconsistency found, unset when valid
+	Inconsistency *Inconsistency `protobuf:"bytes,3,opt,name=inconsistency,proto3" json:"inconsistency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyChainResponse) Reset() {
+	*x = VerifyChainResponse{}
+	mi := &file_audit_v1_audit_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyChainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyChainResponse) ProtoMessage() {}
+
+func (x *VerifyChainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_v1_audit_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyChainResponse.ProtoReflect.Descriptor instead.
+func (*VerifyChainResponse) Descriptor() ([]byte, []int) {
+	return file_audit_v1_audit_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *VerifyChainResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyChainResponse) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *VerifyChainResponse) GetInconsistency() *Inconsistency {
+	if x != nil {
+		return x.Inconsistency
 	}
 	return nil
 }
@@ -263,22 +1046,92 @@ var File_audit_v1_audit_service_proto protoreflect.FileDescriptor
 
 const file_audit_v1_audit_service_proto_rawDesc = "" +
 	"\n" +
-	"\x1caudit/v1/audit_service.proto\x12\baudit.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1bgoogle/protobuf/empty.proto\"\x17\n" +
-	"\x15CreateDecisionRequest\"\x18\n" +
-	"\x16CreateDecisionResponse\"D\n" +
-	"\x14ListDecisionsRequest\x12\x14\n" +
-	"\x05limit\x18\x01 \x01(\x03R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x03R\x06offset\"N\n" +
-	"\x02Tx\x12\x12\n" +
-	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x12\n" +
-	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
-	"\x02to\x18\x03 \x01(\tR\x02to\x12\x10\n" +
-	"\x03sig\x18\x04 \x01(\tR\x03sig\"7\n" +
-	"\x15ListDecisionsResponse\x12\x1e\n" +
-	"\x03txs\x18\x01 \x03(\v2\f.audit.v1.TxR\x03txs2\xa9\x01\n" +
-	"\fAuditService\x12E\n" +
-	"\bDecision\x12\x1f.audit.v1.CreateDecisionRequest\x1a\x16.google.protobuf.Empty\"\x00\x12R\n" +
-	"\rListDecisions\x12\x1e.audit.v1.ListDecisionsRequest\x1a\x1f.audit.v1.ListDecisionsResponse\"\x00B%Z#github.com/structx/idp/api/audit/v1b\x06proto3"
+	"\x1caudit/v1/audit_service.proto\x12\baudit.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xce\x02\n" +
+	"\x0fDecisionRequest\x12/\n" +
+	"\asubject\x18\x01 \x01(\tB\x15\xbaH\x12r\x10\x10\x01\x18\x80\x022\t^\\P{Cc}*$R\asubject\x121\n" +
+	"\bresource\x18\x02 \x01(\tB\x15\xbaH\x12r\x10\x10\x01\x18\x80\x022\t^\\P{Cc}*$R\bresource\x12-\n" +
+	"\x06action\x18\x03 \x01(\tB\x15\xbaH\x12r\x10\x10\x01\x18\x80\x012\t^\\P{Cc}*$R\x06action\x127\n" +
+	"\aoutcome\x18\x04 \x01(\x0e2\x11.audit.v1.OutcomeB\n" +
+	"\xbaH\a\x82\x01\x04\x10\x01 \x00R\aoutcome\x129\n" +
+	"\x0epolicy_version\x18\x05 \x01(\tB\x12\xbaH\x0fr\r\x18@2\t^\\P{Cc}*$R\rpolicyVersion\x124\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tB\x15\xbaH\x12r\x10\x10\x01\x18\x80\x012\t^\\P{Cc}*$R\trequestId\"+\n" +
+	"\x10DecisionResponse\x12\x17\n" +
+	"\atx_hash\x18\x01 \x01(\tR\x06txHash\"\x87\x02\n" +
+	"\x0eDecisionFilter\x12-\n" +
+	"\asubject\x18\x01 \x01(\tB\x13\xbaH\x10r\x0e\x18\x80\x022\t^\\P{Cc}*$R\asubject\x12/\n" +
+	"\bresource\x18\x02 \x01(\tB\x13\xbaH\x10r\x0e\x18\x80\x022\t^\\P{Cc}*$R\bresource\x125\n" +
+	"\aoutcome\x18\x03 \x01(\x0e2\x11.audit.v1.OutcomeB\b\xbaH\x05\x82\x01\x02\x10\x01R\aoutcome\x120\n" +
+	"\x05start\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"\x9a\x01\n" +
+	"\x14ListDecisionsRequest\x12 \n" +
+	"\x05limit\x18\x01 \x01(\x03B\n" +
+	"\xbaH\a\"\x05\x18\xe8\a(\x00R\x05limit\x12 \n" +
+	"\x06cursor\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x04R\x06cursor\x120\n" +
+	"\x06filter\x18\x04 \x01(\v2\x18.audit.v1.DecisionFilterR\x06filterJ\x04\b\x02\x10\x03R\x06offset\"\xbc\x02\n" +
+	"\x0eDecisionRecord\x12\x17\n" +
+	"\atx_hash\x18\x01 \x01(\tR\x06txHash\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x1a\n" +
+	"\bresource\x18\x03 \x01(\tR\bresource\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12+\n" +
+	"\aoutcome\x18\x05 \x01(\x0e2\x11.audit.v1.OutcomeR\aoutcome\x12%\n" +
+	"\x0epolicy_version\x18\x06 \x01(\tR\rpolicyVersion\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\a \x01(\tR\trequestId\x128\n" +
+	"\ttimestamp\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06height\x18\t \x01(\x03R\x06height\"{\n" +
+	"\x15ListDecisionsResponse\x126\n" +
+	"\tdecisions\x18\x02 \x03(\v2\x18.audit.v1.DecisionRecordR\tdecisions\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursorJ\x04\b\x01\x10\x02R\x03txs\"J\n" +
+	"\x16ExportDecisionsRequest\x120\n" +
+	"\x06filter\x18\x01 \x01(\v2\x18.audit.v1.DecisionFilterR\x06filter\"6\n" +
+	"\x0fGetProofRequest\x12#\n" +
+	"\atx_hash\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\x80\x01R\x06txHash\"3\n" +
+	"\tProofStep\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\fR\x04hash\x12\x12\n" +
+	"\x04left\x18\x02 \x01(\bR\x04left\"\x96\x01\n" +
+	"\x05Proof\x12\x17\n" +
+	"\atx_hash\x18\x01 \x01(\tR\x06txHash\x12\x1d\n" +
+	"\n" +
+	"block_hash\x18\x02 \x01(\tR\tblockHash\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x03R\x06height\x12'\n" +
+	"\x04path\x18\x04 \x03(\v2\x13.audit.v1.ProofStepR\x04path\x12\x14\n" +
+	"\x05index\x18\x05 \x01(\x03R\x05index\"8\n" +
+	"\x15GetBlockHeaderRequest\x12\x1f\n" +
+	"\x06height\x18\x01 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\x06height\"\xf6\x01\n" +
+	"\vBlockHeader\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x1b\n" +
+	"\tprev_hash\x18\x02 \x01(\tR\bprevHash\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x03R\x06height\x12\x1f\n" +
+	"\vmerkle_root\x18\x04 \x01(\tR\n" +
+	"merkleRoot\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x10\n" +
+	"\x03sig\x18\x06 \x01(\tR\x03sig\x12\x16\n" +
+	"\x06signer\x18\a \x01(\tR\x06signer\x12\x19\n" +
+	"\btx_count\x18\b \x01(\x03R\atxCount\"\x14\n" +
+	"\x12VerifyChainRequest\"^\n" +
+	"\rInconsistency\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\x03R\x06height\x12\x1d\n" +
+	"\n" +
+	"block_hash\x18\x02 \x01(\tR\tblockHash\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x82\x01\n" +
+	"\x13VerifyChainResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x03R\x06height\x12=\n" +
+	"\rinconsistency\x18\x03 \x01(\v2\x17.audit.v1.InconsistencyR\rinconsistency*G\n" +
+	"\aOutcome\x12\x17\n" +
+	"\x13OUTCOME_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rOUTCOME_ALLOW\x10\x01\x12\x10\n" +
+	"\fOUTCOME_DENY\x10\x022\xce\x03\n" +
+	"\fAuditService\x12C\n" +
+	"\bDecision\x12\x19.audit.v1.DecisionRequest\x1a\x1a.audit.v1.DecisionResponse\"\x00\x12R\n" +
+	"\rListDecisions\x12\x1e.audit.v1.ListDecisionsRequest\x1a\x1f.audit.v1.ListDecisionsResponse\"\x00\x12Q\n" +
+	"\x0fExportDecisions\x12 .audit.v1.ExportDecisionsRequest\x1a\x18.audit.v1.DecisionRecord\"\x000\x01\x128\n" +
+	"\bGetProof\x12\x19.audit.v1.GetProofRequest\x1a\x0f.audit.v1.Proof\"\x00\x12J\n" +
+	"\x0eGetBlockHeader\x12\x1f.audit.v1.GetBlockHeaderRequest\x1a\x15.audit.v1.BlockHeader\"\x00\x12L\n" +
+	"\vVerifyChain\x12\x1c.audit.v1.VerifyChainRequest\x1a\x1d.audit.v1.VerifyChainResponse\"\x00B.Z,github.com/trevatk/tbd/lib/protocol/audit/v1b\x06proto3"
 
 var (
 	file_audit_v1_audit_service_proto_rawDescOnce sync.Once
@@ -292,26 +1145,57 @@ func file_audit_v1_audit_service_proto_rawDescGZIP() []byte {
 	return file_audit_v1_audit_service_proto_rawDescData
 }
 
-var file_audit_v1_audit_service_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_audit_v1_audit_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_audit_v1_audit_service_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_audit_v1_audit_service_proto_goTypes = []any{
-	(*CreateDecisionRequest)(nil),  // 0: audit.v1.CreateDecisionRequest
-	(*CreateDecisionResponse)(nil), // 1: audit.v1.CreateDecisionResponse
-	(*ListDecisionsRequest)(nil),   // 2: audit.v1.ListDecisionsRequest
-	(*Tx)(nil),                     // 3: audit.v1.Tx
-	(*ListDecisionsResponse)(nil),  // 4: audit.v1.ListDecisionsResponse
-	(*emptypb.Empty)(nil),          // 5: google.protobuf.Empty
+	(Outcome)(0),                   // 0: audit.v1.Outcome
+	(*DecisionRequest)(nil),        // 1: audit.v1.DecisionRequest
+	(*DecisionResponse)(nil),       // 2: audit.v1.DecisionResponse
+	(*DecisionFilter)(nil),         // 3: audit.v1.DecisionFilter
+	(*ListDecisionsRequest)(nil),   // 4: audit.v1.ListDecisionsRequest
+	(*DecisionRecord)(nil),         // 5: audit.v1.DecisionRecord
+	(*ListDecisionsResponse)(nil),  // 6: audit.v1.ListDecisionsResponse
+	(*ExportDecisionsRequest)(nil), // 7: audit.v1.ExportDecisionsRequest
+	(*GetProofRequest)(nil),        // 8: audit.v1.GetProofRequest
+	(*ProofStep)(nil),              // 9: audit.v1.ProofStep
+	(*Proof)(nil),                  // 10: audit.v1.Proof
+	(*GetBlockHeaderRequest)(nil),  // 11: audit.v1.GetBlockHeaderRequest
+	(*BlockHeader)(nil),            // 12: audit.v1.BlockHeader
+	(*VerifyChainRequest)(nil),     // 13: audit.v1.VerifyChainRequest
+	(*Inconsistency)(nil),          // 14: audit.v1.Inconsistency
+	(*VerifyChainResponse)(nil),    // 15: audit.v1.VerifyChainResponse
+	(*timestamppb.Timestamp)(nil),  // 16: google.protobuf.Timestamp
 }
 var file_audit_v1_audit_service_proto_depIdxs = []int32{
-	3, // 0: audit.v1.ListDecisionsResponse.txs:type_name -> audit.v1.Tx
-	0, // 1: audit.v1.AuditService.Decision:input_type -> audit.v1.CreateDecisionRequest
-	2, // 2: audit.v1.AuditService.ListDecisions:input_type -> audit.v1.ListDecisionsRequest
-	5, // 3: audit.v1.AuditService.Decision:output_type -> google.protobuf.Empty
-	4, // 4: audit.v1.AuditService.ListDecisions:output_type -> audit.v1.ListDecisionsResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: audit.v1.DecisionRequest.outcome:type_name -> audit.v1.Outcome
+	0,  // 1: audit.v1.DecisionFilter.outcome:type_name -> audit.v1.Outcome
+	16, // 2: audit.v1.DecisionFilter.start:type_name -> google.protobuf.Timestamp
+	16, // 3: audit.v1.DecisionFilter.end:type_name -> google.protobuf.Timestamp
+	3,  // 4: audit.v1.ListDecisionsRequest.filter:type_name -> audit.v1.DecisionFilter
+	0,  // 5: audit.v1.DecisionRecord.outcome:type_name -> audit.v1.Outcome
+	16, // 6: audit.v1.DecisionRecord.timestamp:type_name -> google.protobuf.Timestamp
+	5,  // 7: audit.v1.ListDecisionsResponse.decisions:type_name -> audit.v1.DecisionRecord
+	3,  // 8: audit.v1.ExportDecisionsRequest.filter:type_name -> audit.v1.DecisionFilter
+	9,  // 9: audit.v1.Proof.path:type_name -> audit.v1.ProofStep
+	16, // 10: audit.v1.BlockHeader.timestamp:type_name -> google.protobuf.Timestamp
+	14, // 11: audit.v1.VerifyChainResponse.inconsistency:type_name -> audit.v1.Inconsistency
+	1,  // 12: audit.v1.AuditService.Decision:input_type -> audit.v1.DecisionRequest
+	4,  // 13: audit.v1.AuditService.ListDecisions:input_type -> audit.v1.ListDecisionsRequest
+	7,  // 14: audit.v1.AuditService.ExportDecisions:input_type -> audit.v1.ExportDecisionsRequest
+	8,  // 15: audit.v1.AuditService.GetProof:input_type -> audit.v1.GetProofRequest
+	11, // 16: audit.v1.AuditService.GetBlockHeader:input_type -> audit.v1.GetBlockHeaderRequest
+	13, // 17: audit.v1.AuditService.VerifyChain:input_type -> audit.v1.VerifyChainRequest
+	2,  // 18: audit.v1.AuditService.Decision:output_type -> audit.v1.DecisionResponse
+	6,  // 19: audit.v1.AuditService.ListDecisions:output_type -> audit.v1.ListDecisionsResponse
+	5,  // 20: audit.v1.AuditService.ExportDecisions:output_type -> audit.v1.DecisionRecord
+	10, // 21: audit.v1.AuditService.GetProof:output_type -> audit.v1.Proof
+	12, // 22: audit.v1.AuditService.GetBlockHeader:output_type -> audit.v1.BlockHeader
+	15, // 23: audit.v1.AuditService.VerifyChain:output_type -> audit.v1.VerifyChainResponse
+	18, // [18:24] is the sub-list for method output_type
+	12, // [12:18] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_audit_v1_audit_service_proto_init() }
@@ -324,13 +1208,14 @@ func file_audit_v1_audit_service_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_audit_v1_audit_service_proto_rawDesc), len(file_audit_v1_audit_service_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      1,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_audit_v1_audit_service_proto_goTypes,
 		DependencyIndexes: file_audit_v1_audit_service_proto_depIdxs,
+		EnumInfos:         file_audit_v1_audit_service_proto_enumTypes,
 		MessageInfos:      file_audit_v1_audit_service_proto_msgTypes,
 	}.Build()
 	File_audit_v1_audit_service_proto = out.File
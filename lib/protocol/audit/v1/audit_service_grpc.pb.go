@@ -11,7 +11,6 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -20,16 +19,31 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AuditService_Decision_FullMethodName      = "/audit.v1.AuditService/Decision"
-	AuditService_ListDecisions_FullMethodName = "/audit.v1.AuditService/ListDecisions"
+	AuditService_Decision_FullMethodName        = "/audit.v1.AuditService/Decision"
+	AuditService_ListDecisions_FullMethodName   = "/audit.v1.AuditService/ListDecisions"
+	AuditService_ExportDecisions_FullMethodName = "/audit.v1.AuditService/ExportDecisions"
+	AuditService_GetProof_FullMethodName        = "/audit.v1.AuditService/GetProof"
+	AuditService_GetBlockHeader_FullMethodName  = "/audit.v1.AuditService/GetBlockHeader"
+	AuditService_VerifyChain_FullMethodName     = "/audit.v1.AuditService/VerifyChain"
 )
 
 // AuditServiceClient is the client API for AuditService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AuditServiceClient interface {
-	Decision(ctx context.Context, in *CreateDecisionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Decision record an authorization decision as a signed transaction
+	Decision(ctx context.Context, in *DecisionRequest, opts ...grpc.CallOption) (*DecisionResponse, error)
+	// ListDecisions page through recorded decisions oldest first
 	ListDecisions(ctx context.Context, in *ListDecisionsRequest, opts ...grpc.CallOption) (*ListDecisionsResponse, error)
+	// ExportDecisions stream every recorded decision matching the filter
+	ExportDecisions(ctx context.Context, in *ExportDecisionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DecisionRecord], error)
+	// GetProof merkle inclusion proof of a sealed transaction
+	GetProof(ctx context.Context, in *GetProofRequest, opts ...grpc.CallOption) (*Proof, error)
+	// GetBlockHeader signed header of the block at height
+	GetBlockHeader(ctx context.Context, in *GetBlockHeaderRequest, opts ...grpc.CallOption) (*BlockHeader, error)
+	// VerifyChain recompute hashes and check signatures from genesis
+	// to tip, reporting the first inconsistency found
+	VerifyChain(ctx context.Context, in *VerifyChainRequest, opts ...grpc.CallOption) (*VerifyChainResponse, error)
 }
 
 type auditServiceClient struct {
@@ -40,9 +54,9 @@ func NewAuditServiceClient(cc grpc.ClientConnInterface) AuditServiceClient {
 	return &auditServiceClient{cc}
 }
 
-func (c *auditServiceClient) Decision(ctx context.Context, in *CreateDecisionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (c *auditServiceClient) Decision(ctx context.Context, in *DecisionRequest, opts ...grpc.CallOption) (*DecisionResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(emptypb.Empty)
+	out := new(DecisionResponse)
 	err := c.cc.Invoke(ctx, AuditService_Decision_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -60,12 +74,72 @@ func (c *auditServiceClient) ListDecisions(ctx context.Context, in *ListDecision
 	return out, nil
 }
 
+func (c *auditServiceClient) ExportDecisions(ctx context.Context, in *ExportDecisionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DecisionRecord], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AuditService_ServiceDesc.Streams[0], AuditService_ExportDecisions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportDecisionsRequest, DecisionRecord]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuditService_ExportDecisionsClient = grpc.ServerStreamingClient[DecisionRecord]
+
+func (c *auditServiceClient) GetProof(ctx context.Context, in *GetProofRequest, opts ...grpc.CallOption) (*Proof, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Proof)
+	err := c.cc.Invoke(ctx, AuditService_GetProof_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auditServiceClient) GetBlockHeader(ctx context.Context, in *GetBlockHeaderRequest, opts ...grpc.CallOption) (*BlockHeader, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlockHeader)
+	err := c.cc.Invoke(ctx, AuditService_GetBlockHeader_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auditServiceClient) VerifyChain(ctx context.Context, in *VerifyChainRequest, opts ...grpc.CallOption) (*VerifyChainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyChainResponse)
+	err := c.cc.Invoke(ctx, AuditService_VerifyChain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuditServiceServer is the server API for AuditService service.
 // All implementations must embed UnimplementedAuditServiceServer
 // for forward compatibility.
 type AuditServiceServer interface {
-	Decision(context.Context, *CreateDecisionRequest) (*emptypb.Empty, error)
+	// Decision record an authorization decision as a signed transaction
+	Decision(context.Context, *DecisionRequest) (*DecisionResponse, error)
+	// ListDecisions page through recorded decisions oldest first
 	ListDecisions(context.Context, *ListDecisionsRequest) (*ListDecisionsResponse, error)
+	// ExportDecisions stream every recorded decision matching the filter
+	ExportDecisions(*ExportDecisionsRequest, grpc.ServerStreamingServer[DecisionRecord]) error
+	// GetProof merkle inclusion proof of a sealed transaction
+	GetProof(context.Context, *GetProofRequest) (*Proof, error)
+	// GetBlockHeader signed header of the block at height
+	GetBlockHeader(context.Context, *GetBlockHeaderRequest) (*BlockHeader, error)
+	// VerifyChain recompute hashes and check signatures from genesis
+	// to tip, reporting the first inconsistency found
+	VerifyChain(context.Context, *VerifyChainRequest) (*VerifyChainResponse, error)
 	mustEmbedUnimplementedAuditServiceServer()
 }
 
@@ -76,12 +150,24 @@ type AuditServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedAuditServiceServer struct{}
 
-func (UnimplementedAuditServiceServer) Decision(context.Context, *CreateDecisionRequest) (*emptypb.Empty, error) {
+func (UnimplementedAuditServiceServer) Decision(context.Context, *DecisionRequest) (*DecisionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Decision not implemented")
 }
 func (UnimplementedAuditServiceServer) ListDecisions(context.Context, *ListDecisionsRequest) (*ListDecisionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListDecisions not implemented")
 }
+func (UnimplementedAuditServiceServer) ExportDecisions(*ExportDecisionsRequest, grpc.ServerStreamingServer[DecisionRecord]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportDecisions not implemented")
+}
+func (UnimplementedAuditServiceServer) GetProof(context.Context, *GetProofRequest) (*Proof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProof not implemented")
+}
+func (UnimplementedAuditServiceServer) GetBlockHeader(context.Context, *GetBlockHeaderRequest) (*BlockHeader, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockHeader not implemented")
+}
+func (UnimplementedAuditServiceServer) VerifyChain(context.Context, *VerifyChainRequest) (*VerifyChainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyChain not implemented")
+}
 func (UnimplementedAuditServiceServer) mustEmbedUnimplementedAuditServiceServer() {}
 func (UnimplementedAuditServiceServer) testEmbeddedByValue()                      {}
 
@@ -104,7 +190,7 @@ func RegisterAuditServiceServer(s grpc.ServiceRegistrar, srv AuditServiceServer)
 }
 
 func _AuditService_Decision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateDecisionRequest)
+	in := new(DecisionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -116,7 +202,7 @@ func _AuditService_Decision_Handler(srv interface{}, ctx context.Context, dec fu
 		FullMethod: AuditService_Decision_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuditServiceServer).Decision(ctx, req.(*CreateDecisionRequest))
+		return srv.(AuditServiceServer).Decision(ctx, req.(*DecisionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -139,6 +225,71 @@ func _AuditService_ListDecisions_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuditService_ExportDecisions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportDecisionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuditServiceServer).ExportDecisions(m, &grpc.GenericServerStream[ExportDecisionsRequest, DecisionRecord]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuditService_ExportDecisionsServer = grpc.ServerStreamingServer[DecisionRecord]
+
+func _AuditService_GetProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditServiceServer).GetProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuditService_GetProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditServiceServer).GetProof(ctx, req.(*GetProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuditService_GetBlockHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockHeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditServiceServer).GetBlockHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuditService_GetBlockHeader_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditServiceServer).GetBlockHeader(ctx, req.(*GetBlockHeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuditService_VerifyChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditServiceServer).VerifyChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuditService_VerifyChain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditServiceServer).VerifyChain(ctx, req.(*VerifyChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuditService_ServiceDesc is the grpc.ServiceDesc for AuditService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -154,7 +305,25 @@ var AuditService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListDecisions",
 			Handler:    _AuditService_ListDecisions_Handler,
 		},
+		{
+			MethodName: "GetProof",
+			Handler:    _AuditService_GetProof_Handler,
+		},
+		{
+			MethodName: "GetBlockHeader",
+			Handler:    _AuditService_GetBlockHeader_Handler,
+		},
+		{
+			MethodName: "VerifyChain",
+			Handler:    _AuditService_VerifyChain_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportDecisions",
+			Handler:       _AuditService_ExportDecisions_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "audit/v1/audit_service.proto",
 }
@@ -11,6 +11,36 @@ func ErrInvalidArgument() error {
 	return status.Error(codes.InvalidArgument, codes.InvalidArgument.String())
 }
 
+// ErrNotFound ...
+func ErrNotFound() error {
+	return status.Error(codes.NotFound, codes.NotFound.String())
+}
+
+// ErrAlreadyExists ...
+func ErrAlreadyExists() error {
+	return status.Error(codes.AlreadyExists, codes.AlreadyExists.String())
+}
+
+// ErrOutOfRange ...
+func ErrOutOfRange() error {
+	return status.Error(codes.OutOfRange, codes.OutOfRange.String())
+}
+
+// ErrFailedPrecondition ...
+func ErrFailedPrecondition() error {
+	return status.Error(codes.FailedPrecondition, codes.FailedPrecondition.String())
+}
+
+// ErrPermissionDenied ...
+func ErrPermissionDenied() error {
+	return status.Error(codes.PermissionDenied, codes.PermissionDenied.String())
+}
+
+// ErrUnavailable ...
+func ErrUnavailable() error {
+	return status.Error(codes.Unavailable, codes.Unavailable.String())
+}
+
 // ErrInternal ...
 func ErrInternal() error {
 	return status.Error(codes.Internal, codes.Internal.String())
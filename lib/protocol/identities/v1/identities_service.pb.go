@@ -23,8 +23,10 @@ const (
 )
 
 type RealmCreate struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DisplayName   string                 `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DisplayName string                 `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// owner_email email of the user created as owner of the realm
+	OwnerEmail    string `protobuf:"bytes,2,opt,name=owner_email,json=ownerEmail,proto3" json:"owner_email,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,6 +68,13 @@ func (x *RealmCreate) GetDisplayName() string {
 	return ""
 }
 
+func (x *RealmCreate) GetOwnerEmail() string {
+	if x != nil {
+		return x.OwnerEmail
+	}
+	return ""
+}
+
 type CreateRealmRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Create        *RealmCreate           `protobuf:"bytes,1,opt,name=create,proto3" json:"create,omitempty"`
@@ -165,6 +174,7 @@ func (x *Realm) GetDisplayName() string {
 type CreateRealmResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Realm         *Realm                 `protobuf:"bytes,1,opt,name=realm,proto3" json:"realm,omitempty"`
+	Owner         *User                  `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -206,28 +216,34 @@ func (x *CreateRealmResponse) GetRealm() *Realm {
 	return nil
 }
 
-type CreateUserRequest struct {
+func (x *CreateRealmResponse) GetOwner() *User {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+type GetRealmRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	RealmHash     string                 `protobuf:"bytes,2,opt,name=realm_hash,json=realmHash,proto3" json:"realm_hash,omitempty"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateUserRequest) Reset() {
-	*x = CreateUserRequest{}
+func (x *GetRealmRequest) Reset() {
+	*x = GetRealmRequest{}
 	mi := &file_identities_v1_identities_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateUserRequest) String() string {
+func (x *GetRealmRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserRequest) ProtoMessage() {}
+func (*GetRealmRequest) ProtoMessage() {}
 
-func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+func (x *GetRealmRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_identities_v1_identities_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -239,47 +255,192 @@ func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
-func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetRealmRequest.ProtoReflect.Descriptor instead.
+func (*GetRealmRequest) Descriptor() ([]byte, []int) {
 	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CreateUserRequest) GetEmail() string {
+func (x *GetRealmRequest) GetHash() string {
 	if x != nil {
-		return x.Email
+		return x.Hash
 	}
 	return ""
 }
 
-func (x *CreateUserRequest) GetRealmHash() string {
+type GetRealmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Realm         *Realm                 `protobuf:"bytes,1,opt,name=realm,proto3" json:"realm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRealmResponse) Reset() {
+	*x = GetRealmResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRealmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRealmResponse) ProtoMessage() {}
+
+func (x *GetRealmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[5]
 	if x != nil {
-		return x.RealmHash
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRealmResponse.ProtoReflect.Descriptor instead.
+func (*GetRealmResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetRealmResponse) GetRealm() *Realm {
+	if x != nil {
+		return x.Realm
+	}
+	return nil
+}
+
+type ListRealmsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// limit page size, zero for the default
+	Limit int64 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// next_cursor of the previous page, empty for the first page
+	Cursor        string `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRealmsRequest) Reset() {
+	*x = ListRealmsRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRealmsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRealmsRequest) ProtoMessage() {}
+
+func (x *ListRealmsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRealmsRequest.ProtoReflect.Descriptor instead.
+func (*ListRealmsRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListRealmsRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRealmsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
 	}
 	return ""
 }
 
-type CreateUserResponse struct {
+type ListRealmsResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Realms []*Realm               `protobuf:"bytes,1,rep,name=realms,proto3" json:"realms,omitempty"`
+	// cursor of the next page, empty on the last page
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRealmsResponse) Reset() {
+	*x = ListRealmsResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRealmsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRealmsResponse) ProtoMessage() {}
+
+func (x *ListRealmsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRealmsResponse.ProtoReflect.Descriptor instead.
+func (*ListRealmsResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListRealmsResponse) GetRealms() []*Realm {
+	if x != nil {
+		return x.Realms
+	}
+	return nil
+}
+
+func (x *ListRealmsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type UpdateRealmRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateUserResponse) Reset() {
-	*x = CreateUserResponse{}
-	mi := &file_identities_v1_identities_service_proto_msgTypes[5]
+func (x *UpdateRealmRequest) Reset() {
+	*x = UpdateRealmRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateUserResponse) String() string {
+func (x *UpdateRealmRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserResponse) ProtoMessage() {}
+func (*UpdateRealmRequest) ProtoMessage() {}
 
-func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_identities_v1_identities_service_proto_msgTypes[5]
+func (x *UpdateRealmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -290,42 +451,2318 @@ func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserResponse.ProtoReflect.Descriptor instead.
-func (*CreateUserResponse) Descriptor() ([]byte, []int) {
-	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use UpdateRealmRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRealmRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *CreateUserResponse) GetHash() string {
+func (x *UpdateRealmRequest) GetHash() string {
 	if x != nil {
 		return x.Hash
 	}
 	return ""
 }
 
-var File_identities_v1_identities_service_proto protoreflect.FileDescriptor
+func (x *UpdateRealmRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
 
-const file_identities_v1_identities_service_proto_rawDesc = "" +
-	"\n" +
-	"&identities/v1/identities_service.proto\x12\ridentities.v1\x1a\x1bbuf/validate/validate.proto\"0\n" +
-	"\vRealmCreate\x12!\n" +
-	"\fdisplay_name\x18\x01 \x01(\tR\vdisplayName\"H\n" +
-	"\x12CreateRealmRequest\x122\n" +
-	"\x06create\x18\x01 \x01(\v2\x1a.identities.v1.RealmCreateR\x06create\">\n" +
-	"\x05Realm\x12\x12\n" +
-	"\x04hash\x18\x01 \x01(\tR\x04hash\x12!\n" +
-	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\"A\n" +
-	"\x13CreateRealmResponse\x12*\n" +
-	"\x05realm\x18\x01 \x01(\v2\x14.identities.v1.RealmR\x05realm\"Q\n" +
-	"\x11CreateUserRequest\x12\x1d\n" +
-	"\x05email\x18\x01 \x01(\tB\a\xbaH\x04r\x02`\x01R\x05email\x12\x1d\n" +
-	"\n" +
-	"realm_hash\x18\x02 \x01(\tR\trealmHash\"(\n" +
-	"\x12CreateUserResponse\x12\x12\n" +
-	"\x04hash\x18\x01 \x01(\tR\x04hash2\xc0\x01\n" +
-	"\x11IdentitiesService\x12V\n" +
-	"\vCreateRealm\x12!.identities.v1.CreateRealmRequest\x1a\".identities.v1.CreateRealmResponse\"\x00\x12S\n" +
+type UpdateRealmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Realm         *Realm                 `protobuf:"bytes,1,opt,name=realm,proto3" json:"realm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRealmResponse) Reset() {
+	*x = UpdateRealmResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRealmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRealmResponse) ProtoMessage() {}
+
+func (x *UpdateRealmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRealmResponse.ProtoReflect.Descriptor instead.
+func (*UpdateRealmResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateRealmResponse) GetRealm() *Realm {
+	if x != nil {
+		return x.Realm
+	}
+	return nil
+}
+
+type DeleteRealmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRealmRequest) Reset() {
+	*x = DeleteRealmRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRealmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRealmRequest) ProtoMessage() {}
+
+func (x *DeleteRealmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRealmRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRealmRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteRealmRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type DeleteRealmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRealmResponse) Reset() {
+	*x = DeleteRealmResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRealmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRealmResponse) ProtoMessage() {}
+
+func (x *DeleteRealmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRealmResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRealmResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{11}
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	RealmHash     string                 `protobuf:"bytes,2,opt,name=realm_hash,json=realmHash,proto3" json:"realm_hash,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *User) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *User) GetRealmHash() string {
+	if x != nil {
+		return x.RealmHash
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	RealmHash     string                 `protobuf:"bytes,2,opt,name=realm_hash,json=realmHash,proto3" json:"realm_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetRealmHash() string {
+	if x != nil {
+		return x.RealmHash
+	}
+	return ""
+}
+
+type CreateUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	User          *User                  `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserResponse) Reset() {
+	*x = CreateUserResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserResponse) ProtoMessage() {}
+
+func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserResponse.ProtoReflect.Descriptor instead.
+func (*CreateUserResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateUserResponse) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *CreateUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetUserRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type GetUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserResponse) Reset() {
+	*x = GetUserResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserResponse) ProtoMessage() {}
+
+func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
+func (*GetUserResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type ListUsersRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RealmHash string                 `protobuf:"bytes,1,opt,name=realm_hash,json=realmHash,proto3" json:"realm_hash,omitempty"`
+	// limit page size, zero for the default
+	Limit int64 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// next_cursor of the previous page, empty for the first page
+	Cursor        string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListUsersRequest) GetRealmHash() string {
+	if x != nil {
+		return x.RealmHash
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Users []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// cursor of the next page, empty on the last page
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type UpdateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateUserRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type UpdateUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserResponse) Reset() {
+	*x = UpdateUserResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserResponse) ProtoMessage() {}
+
+func (x *UpdateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserResponse.ProtoReflect.Descriptor instead.
+func (*UpdateUserResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdateUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeleteUserRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type DeleteUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserResponse) Reset() {
+	*x = DeleteUserResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserResponse) ProtoMessage() {}
+
+func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{22}
+}
+
+type Group struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	RealmHash     string                 `protobuf:"bytes,2,opt,name=realm_hash,json=realmHash,proto3" json:"realm_hash,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Group) Reset() {
+	*x = Group{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Group) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Group) ProtoMessage() {}
+
+func (x *Group) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Group.ProtoReflect.Descriptor instead.
+func (*Group) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Group) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Group) GetRealmHash() string {
+	if x != nil {
+		return x.RealmHash
+	}
+	return ""
+}
+
+func (x *Group) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RealmHash     string                 `protobuf:"bytes,1,opt,name=realm_hash,json=realmHash,proto3" json:"realm_hash,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGroupRequest) Reset() {
+	*x = CreateGroupRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGroupRequest) ProtoMessage() {}
+
+func (x *CreateGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateGroupRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CreateGroupRequest) GetRealmHash() string {
+	if x != nil {
+		return x.RealmHash
+	}
+	return ""
+}
+
+func (x *CreateGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *Group                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGroupResponse) Reset() {
+	*x = CreateGroupResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGroupResponse) ProtoMessage() {}
+
+func (x *CreateGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGroupResponse.ProtoReflect.Descriptor instead.
+func (*CreateGroupResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CreateGroupResponse) GetGroup() *Group {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type GetGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGroupRequest) Reset() {
+	*x = GetGroupRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGroupRequest) ProtoMessage() {}
+
+func (x *GetGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGroupRequest.ProtoReflect.Descriptor instead.
+func (*GetGroupRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetGroupRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type GetGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *Group                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGroupResponse) Reset() {
+	*x = GetGroupResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGroupResponse) ProtoMessage() {}
+
+func (x *GetGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGroupResponse.ProtoReflect.Descriptor instead.
+func (*GetGroupResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetGroupResponse) GetGroup() *Group {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type ListGroupsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RealmHash string                 `protobuf:"bytes,1,opt,name=realm_hash,json=realmHash,proto3" json:"realm_hash,omitempty"`
+	// limit page size, zero for the default
+	Limit int64 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// next_cursor of the previous page, empty for the first page
+	Cursor        string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGroupsRequest) Reset() {
+	*x = ListGroupsRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGroupsRequest) ProtoMessage() {}
+
+func (x *ListGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListGroupsRequest) GetRealmHash() string {
+	if x != nil {
+		return x.RealmHash
+	}
+	return ""
+}
+
+func (x *ListGroupsRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListGroupsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListGroupsResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Groups []*Group               `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	// cursor of the next page, empty on the last page
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGroupsResponse) Reset() {
+	*x = ListGroupsResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGroupsResponse) ProtoMessage() {}
+
+func (x *ListGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListGroupsResponse) GetGroups() []*Group {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *ListGroupsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type UpdateGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateGroupRequest) Reset() {
+	*x = UpdateGroupRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateGroupRequest) ProtoMessage() {}
+
+func (x *UpdateGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateGroupRequest.ProtoReflect.Descriptor instead.
+func (*UpdateGroupRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UpdateGroupRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *UpdateGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type UpdateGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *Group                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateGroupResponse) Reset() {
+	*x = UpdateGroupResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateGroupResponse) ProtoMessage() {}
+
+func (x *UpdateGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateGroupResponse.ProtoReflect.Descriptor instead.
+func (*UpdateGroupResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UpdateGroupResponse) GetGroup() *Group {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type DeleteGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteGroupRequest) Reset() {
+	*x = DeleteGroupRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteGroupRequest) ProtoMessage() {}
+
+func (x *DeleteGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteGroupRequest.ProtoReflect.Descriptor instead.
+func (*DeleteGroupRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteGroupRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type DeleteGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteGroupResponse) Reset() {
+	*x = DeleteGroupResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteGroupResponse) ProtoMessage() {}
+
+func (x *DeleteGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteGroupResponse.ProtoReflect.Descriptor instead.
+func (*DeleteGroupResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{33}
+}
+
+type AddMemberRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	GroupHash string                 `protobuf:"bytes,1,opt,name=group_hash,json=groupHash,proto3" json:"group_hash,omitempty"`
+	// member_hash user or group
+	MemberHash    string `protobuf:"bytes,2,opt,name=member_hash,json=memberHash,proto3" json:"member_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddMemberRequest) Reset() {
+	*x = AddMemberRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddMemberRequest) ProtoMessage() {}
+
+func (x *AddMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddMemberRequest.ProtoReflect.Descriptor instead.
+func (*AddMemberRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AddMemberRequest) GetGroupHash() string {
+	if x != nil {
+		return x.GroupHash
+	}
+	return ""
+}
+
+func (x *AddMemberRequest) GetMemberHash() string {
+	if x != nil {
+		return x.MemberHash
+	}
+	return ""
+}
+
+type AddMemberResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConsistencyToken string                 `protobuf:"bytes,1,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AddMemberResponse) Reset() {
+	*x = AddMemberResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddMemberResponse) ProtoMessage() {}
+
+func (x *AddMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddMemberResponse.ProtoReflect.Descriptor instead.
+func (*AddMemberResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *AddMemberResponse) GetConsistencyToken() string {
+	if x != nil {
+		return x.ConsistencyToken
+	}
+	return ""
+}
+
+type RemoveMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupHash     string                 `protobuf:"bytes,1,opt,name=group_hash,json=groupHash,proto3" json:"group_hash,omitempty"`
+	MemberHash    string                 `protobuf:"bytes,2,opt,name=member_hash,json=memberHash,proto3" json:"member_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveMemberRequest) Reset() {
+	*x = RemoveMemberRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberRequest) ProtoMessage() {}
+
+func (x *RemoveMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveMemberRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RemoveMemberRequest) GetGroupHash() string {
+	if x != nil {
+		return x.GroupHash
+	}
+	return ""
+}
+
+func (x *RemoveMemberRequest) GetMemberHash() string {
+	if x != nil {
+		return x.MemberHash
+	}
+	return ""
+}
+
+type RemoveMemberResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConsistencyToken string                 `protobuf:"bytes,1,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RemoveMemberResponse) Reset() {
+	*x = RemoveMemberResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberResponse) ProtoMessage() {}
+
+func (x *RemoveMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberResponse.ProtoReflect.Descriptor instead.
+func (*RemoveMemberResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RemoveMemberResponse) GetConsistencyToken() string {
+	if x != nil {
+		return x.ConsistencyToken
+	}
+	return ""
+}
+
+type RoleAssignment struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Role  string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	// subject_hash user or group
+	SubjectHash   string `protobuf:"bytes,2,opt,name=subject_hash,json=subjectHash,proto3" json:"subject_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoleAssignment) Reset() {
+	*x = RoleAssignment{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoleAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleAssignment) ProtoMessage() {}
+
+func (x *RoleAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleAssignment.ProtoReflect.Descriptor instead.
+func (*RoleAssignment) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RoleAssignment) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *RoleAssignment) GetSubjectHash() string {
+	if x != nil {
+		return x.SubjectHash
+	}
+	return ""
+}
+
+type AssignRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RealmHash     string                 `protobuf:"bytes,1,opt,name=realm_hash,json=realmHash,proto3" json:"realm_hash,omitempty"`
+	Assignment    *RoleAssignment        `protobuf:"bytes,2,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRoleRequest) Reset() {
+	*x = AssignRoleRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRoleRequest) ProtoMessage() {}
+
+func (x *AssignRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRoleRequest.ProtoReflect.Descriptor instead.
+func (*AssignRoleRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *AssignRoleRequest) GetRealmHash() string {
+	if x != nil {
+		return x.RealmHash
+	}
+	return ""
+}
+
+func (x *AssignRoleRequest) GetAssignment() *RoleAssignment {
+	if x != nil {
+		return x.Assignment
+	}
+	return nil
+}
+
+type AssignRoleResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConsistencyToken string                 `protobuf:"bytes,1,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AssignRoleResponse) Reset() {
+	*x = AssignRoleResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRoleResponse) ProtoMessage() {}
+
+func (x *AssignRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRoleResponse.ProtoReflect.Descriptor instead.
+func (*AssignRoleResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *AssignRoleResponse) GetConsistencyToken() string {
+	if x != nil {
+		return x.ConsistencyToken
+	}
+	return ""
+}
+
+type RevokeRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RealmHash     string                 `protobuf:"bytes,1,opt,name=realm_hash,json=realmHash,proto3" json:"realm_hash,omitempty"`
+	Assignment    *RoleAssignment        `protobuf:"bytes,2,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeRoleRequest) Reset() {
+	*x = RevokeRoleRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeRoleRequest) ProtoMessage() {}
+
+func (x *RevokeRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeRoleRequest.ProtoReflect.Descriptor instead.
+func (*RevokeRoleRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RevokeRoleRequest) GetRealmHash() string {
+	if x != nil {
+		return x.RealmHash
+	}
+	return ""
+}
+
+func (x *RevokeRoleRequest) GetAssignment() *RoleAssignment {
+	if x != nil {
+		return x.Assignment
+	}
+	return nil
+}
+
+type RevokeRoleResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConsistencyToken string                 `protobuf:"bytes,1,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RevokeRoleResponse) Reset() {
+	*x = RevokeRoleResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeRoleResponse) ProtoMessage() {}
+
+func (x *RevokeRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeRoleResponse.ProtoReflect.Descriptor instead.
+func (*RevokeRoleResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *RevokeRoleResponse) GetConsistencyToken() string {
+	if x != nil {
+		return x.ConsistencyToken
+	}
+	return ""
+}
+
+type ListRolesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RealmHash     string                 `protobuf:"bytes,1,opt,name=realm_hash,json=realmHash,proto3" json:"realm_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolesRequest) Reset() {
+	*x = ListRolesRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolesRequest) ProtoMessage() {}
+
+func (x *ListRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolesRequest.ProtoReflect.Descriptor instead.
+func (*ListRolesRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListRolesRequest) GetRealmHash() string {
+	if x != nil {
+		return x.RealmHash
+	}
+	return ""
+}
+
+type ListRolesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Assignments   []*RoleAssignment      `protobuf:"bytes,1,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolesResponse) Reset() {
+	*x = ListRolesResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolesResponse) ProtoMessage() {}
+
+func (x *ListRolesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolesResponse.ProtoReflect.Descriptor instead.
+func (*ListRolesResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListRolesResponse) GetAssignments() []*RoleAssignment {
+	if x != nil {
+		return x.Assignments
+	}
+	return nil
+}
+
+// Relationship edge object -relation-> subject
+type Relationship struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Object        string                 `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	Relation      string                 `protobuf:"bytes,2,opt,name=relation,proto3" json:"relation,omitempty"`
+	Subject       string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Relationship) Reset() {
+	*x = Relationship{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Relationship) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Relationship) ProtoMessage() {}
+
+func (x *Relationship) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Relationship.ProtoReflect.Descriptor instead.
+func (*Relationship) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *Relationship) GetObject() string {
+	if x != nil {
+		return x.Object
+	}
+	return ""
+}
+
+func (x *Relationship) GetRelation() string {
+	if x != nil {
+		return x.Relation
+	}
+	return ""
+}
+
+func (x *Relationship) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+type CheckRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Subject  string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Relation string                 `protobuf:"bytes,2,opt,name=relation,proto3" json:"relation,omitempty"`
+	Object   string                 `protobuf:"bytes,3,opt,name=object,proto3" json:"object,omitempty"`
+	// consistency_token evaluate at a revision at least as new as
+	// the token returned by a write, empty accepts any revision
+	ConsistencyToken string `protobuf:"bytes,4,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CheckRequest) Reset() {
+	*x = CheckRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRequest) ProtoMessage() {}
+
+func (x *CheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRequest.ProtoReflect.Descriptor instead.
+func (*CheckRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CheckRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetRelation() string {
+	if x != nil {
+		return x.Relation
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetObject() string {
+	if x != nil {
+		return x.Object
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetConsistencyToken() string {
+	if x != nil {
+		return x.ConsistencyToken
+	}
+	return ""
+}
+
+type CheckResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Allowed          bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	ConsistencyToken string                 `protobuf:"bytes,2,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	// policy_version version of the policy the check evaluated
+	PolicyVersion string `protobuf:"bytes,3,opt,name=policy_version,json=policyVersion,proto3" json:"policy_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckResponse) Reset() {
+	*x = CheckResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckResponse) ProtoMessage() {}
+
+func (x *CheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckResponse.ProtoReflect.Descriptor instead.
+func (*CheckResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *CheckResponse) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *CheckResponse) GetConsistencyToken() string {
+	if x != nil {
+		return x.ConsistencyToken
+	}
+	return ""
+}
+
+func (x *CheckResponse) GetPolicyVersion() string {
+	if x != nil {
+		return x.PolicyVersion
+	}
+	return ""
+}
+
+type WriteRelationshipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Relationship  *Relationship          `protobuf:"bytes,1,opt,name=relationship,proto3" json:"relationship,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteRelationshipRequest) Reset() {
+	*x = WriteRelationshipRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteRelationshipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteRelationshipRequest) ProtoMessage() {}
+
+func (x *WriteRelationshipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteRelationshipRequest.ProtoReflect.Descriptor instead.
+func (*WriteRelationshipRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *WriteRelationshipRequest) GetRelationship() *Relationship {
+	if x != nil {
+		return x.Relationship
+	}
+	return nil
+}
+
+type WriteRelationshipResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConsistencyToken string                 `protobuf:"bytes,1,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *WriteRelationshipResponse) Reset() {
+	*x = WriteRelationshipResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteRelationshipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteRelationshipResponse) ProtoMessage() {}
+
+func (x *WriteRelationshipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteRelationshipResponse.ProtoReflect.Descriptor instead.
+func (*WriteRelationshipResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *WriteRelationshipResponse) GetConsistencyToken() string {
+	if x != nil {
+		return x.ConsistencyToken
+	}
+	return ""
+}
+
+type DeleteRelationshipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Relationship  *Relationship          `protobuf:"bytes,1,opt,name=relationship,proto3" json:"relationship,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRelationshipRequest) Reset() {
+	*x = DeleteRelationshipRequest{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRelationshipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRelationshipRequest) ProtoMessage() {}
+
+func (x *DeleteRelationshipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRelationshipRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRelationshipRequest) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *DeleteRelationshipRequest) GetRelationship() *Relationship {
+	if x != nil {
+		return x.Relationship
+	}
+	return nil
+}
+
+type DeleteRelationshipResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConsistencyToken string                 `protobuf:"bytes,1,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DeleteRelationshipResponse) Reset() {
+	*x = DeleteRelationshipResponse{}
+	mi := &file_identities_v1_identities_service_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRelationshipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRelationshipResponse) ProtoMessage() {}
+
+func (x *DeleteRelationshipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identities_v1_identities_service_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRelationshipResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRelationshipResponse) Descriptor() ([]byte, []int) {
+	return file_identities_v1_identities_service_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *DeleteRelationshipResponse) GetConsistencyToken() string {
+	if x != nil {
+		return x.ConsistencyToken
+	}
+	return ""
+}
+
+var File_identities_v1_identities_service_proto protoreflect.FileDescriptor
+
+const file_identities_v1_identities_service_proto_rawDesc = "" +
+	"\n" +
+	"&identities/v1/identities_service.proto\x12\ridentities.v1\x1a\x1bbuf/validate/validate.proto\"Z\n" +
+	"\vRealmCreate\x12!\n" +
+	"\fdisplay_name\x18\x01 \x01(\tR\vdisplayName\x12(\n" +
+	"\vowner_email\x18\x02 \x01(\tB\a\xbaH\x04r\x02`\x01R\n" +
+	"ownerEmail\"P\n" +
+	"\x12CreateRealmRequest\x12:\n" +
+	"\x06create\x18\x01 \x01(\v2\x1a.identities.v1.RealmCreateB\x06\xbaH\x03\xc8\x01\x01R\x06create\">\n" +
+	"\x05Realm\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\"l\n" +
+	"\x13CreateRealmResponse\x12*\n" +
+	"\x05realm\x18\x01 \x01(\v2\x14.identities.v1.RealmR\x05realm\x12)\n" +
+	"\x05owner\x18\x02 \x01(\v2\x13.identities.v1.UserR\x05owner\"/\n" +
+	"\x0fGetRealmRequest\x12\x1c\n" +
+	"\x04hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x04hash\">\n" +
+	"\x10GetRealmResponse\x12*\n" +
+	"\x05realm\x18\x01 \x01(\v2\x14.identities.v1.RealmR\x05realm\"W\n" +
+	"\x11ListRealmsRequest\x12 \n" +
+	"\x05limit\x18\x01 \x01(\x03B\n" +
+	"\xbaH\a\"\x05\x18\xe8\a(\x00R\x05limit\x12 \n" +
+	"\x06cursor\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x04R\x06cursor\"c\n" +
+	"\x12ListRealmsResponse\x12,\n" +
+	"\x06realms\x18\x01 \x03(\v2\x14.identities.v1.RealmR\x06realms\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"a\n" +
+	"\x12UpdateRealmRequest\x12\x1c\n" +
+	"\x04hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x04hash\x12-\n" +
+	"\fdisplay_name\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\x80\x02R\vdisplayName\"A\n" +
+	"\x13UpdateRealmResponse\x12*\n" +
+	"\x05realm\x18\x01 \x01(\v2\x14.identities.v1.RealmR\x05realm\"2\n" +
+	"\x12DeleteRealmRequest\x12\x1c\n" +
+	"\x04hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x04hash\"\x15\n" +
+	"\x13DeleteRealmResponse\"O\n" +
+	"\x04User\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x1d\n" +
+	"\n" +
+	"realm_hash\x18\x02 \x01(\tR\trealmHash\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\"[\n" +
+	"\x11CreateUserRequest\x12\x1d\n" +
+	"\x05email\x18\x01 \x01(\tB\a\xbaH\x04r\x02`\x01R\x05email\x12'\n" +
+	"\n" +
+	"realm_hash\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\trealmHash\"Q\n" +
+	"\x12CreateUserResponse\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12'\n" +
+	"\x04user\x18\x02 \x01(\v2\x13.identities.v1.UserR\x04user\".\n" +
+	"\x0eGetUserRequest\x12\x1c\n" +
+	"\x04hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x04hash\":\n" +
+	"\x0fGetUserResponse\x12'\n" +
+	"\x04user\x18\x01 \x01(\v2\x13.identities.v1.UserR\x04user\"\x7f\n" +
+	"\x10ListUsersRequest\x12'\n" +
+	"\n" +
+	"realm_hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\trealmHash\x12 \n" +
+	"\x05limit\x18\x02 \x01(\x03B\n" +
+	"\xbaH\a\"\x05\x18\xe8\a(\x00R\x05limit\x12 \n" +
+	"\x06cursor\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x04R\x06cursor\"_\n" +
+	"\x11ListUsersResponse\x12)\n" +
+	"\x05users\x18\x01 \x03(\v2\x13.identities.v1.UserR\x05users\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"P\n" +
+	"\x11UpdateUserRequest\x12\x1c\n" +
+	"\x04hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x04hash\x12\x1d\n" +
+	"\x05email\x18\x02 \x01(\tB\a\xbaH\x04r\x02`\x01R\x05email\"=\n" +
+	"\x12UpdateUserResponse\x12'\n" +
+	"\x04user\x18\x01 \x01(\v2\x13.identities.v1.UserR\x04user\"1\n" +
+	"\x11DeleteUserRequest\x12\x1c\n" +
+	"\x04hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x04hash\"\x14\n" +
+	"\x12DeleteUserResponse\"N\n" +
+	"\x05Group\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x1d\n" +
+	"\n" +
+	"realm_hash\x18\x02 \x01(\tR\trealmHash\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"]\n" +
+	"\x12CreateGroupRequest\x12'\n" +
+	"\n" +
+	"realm_hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\trealmHash\x12\x1e\n" +
+	"\x04name\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\x80\x02R\x04name\"A\n" +
+	"\x13CreateGroupResponse\x12*\n" +
+	"\x05group\x18\x01 \x01(\v2\x14.identities.v1.GroupR\x05group\"/\n" +
+	"\x0fGetGroupRequest\x12\x1c\n" +
+	"\x04hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x04hash\">\n" +
+	"\x10GetGroupResponse\x12*\n" +
+	"\x05group\x18\x01 \x01(\v2\x14.identities.v1.GroupR\x05group\"\x80\x01\n" +
+	"\x11ListGroupsRequest\x12'\n" +
+	"\n" +
+	"realm_hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\trealmHash\x12 \n" +
+	"\x05limit\x18\x02 \x01(\x03B\n" +
+	"\xbaH\a\"\x05\x18\xe8\a(\x00R\x05limit\x12 \n" +
+	"\x06cursor\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x04R\x06cursor\"c\n" +
+	"\x12ListGroupsResponse\x12,\n" +
+	"\x06groups\x18\x01 \x03(\v2\x14.identities.v1.GroupR\x06groups\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"R\n" +
+	"\x12UpdateGroupRequest\x12\x1c\n" +
+	"\x04hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x04hash\x12\x1e\n" +
+	"\x04name\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\x80\x02R\x04name\"A\n" +
+	"\x13UpdateGroupResponse\x12*\n" +
+	"\x05group\x18\x01 \x01(\v2\x14.identities.v1.GroupR\x05group\"2\n" +
+	"\x12DeleteGroupRequest\x12\x1c\n" +
+	"\x04hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x04hash\"\x15\n" +
+	"\x13DeleteGroupResponse\"f\n" +
+	"\x10AddMemberRequest\x12'\n" +
+	"\n" +
+	"group_hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\tgroupHash\x12)\n" +
+	"\vmember_hash\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\n" +
+	"memberHash\"@\n" +
+	"\x11AddMemberResponse\x12+\n" +
+	"\x11consistency_token\x18\x01 \x01(\tR\x10consistencyToken\"i\n" +
+	"\x13RemoveMemberRequest\x12'\n" +
+	"\n" +
+	"group_hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\tgroupHash\x12)\n" +
+	"\vmember_hash\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\n" +
+	"memberHash\"C\n" +
+	"\x14RemoveMemberResponse\x12+\n" +
+	"\x11consistency_token\x18\x01 \x01(\tR\x10consistencyToken\"Z\n" +
+	"\x0eRoleAssignment\x12\x1b\n" +
+	"\x04role\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x04role\x12+\n" +
+	"\fsubject_hash\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\vsubjectHash\"\x83\x01\n" +
+	"\x11AssignRoleRequest\x12'\n" +
+	"\n" +
+	"realm_hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\trealmHash\x12E\n" +
+	"\n" +
+	"assignment\x18\x02 \x01(\v2\x1d.identities.v1.RoleAssignmentB\x06\xbaH\x03\xc8\x01\x01R\n" +
+	"assignment\"A\n" +
+	"\x12AssignRoleResponse\x12+\n" +
+	"\x11consistency_token\x18\x01 \x01(\tR\x10consistencyToken\"\x83\x01\n" +
+	"\x11RevokeRoleRequest\x12'\n" +
+	"\n" +
+	"realm_hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\trealmHash\x12E\n" +
+	"\n" +
+	"assignment\x18\x02 \x01(\v2\x1d.identities.v1.RoleAssignmentB\x06\xbaH\x03\xc8\x01\x01R\n" +
+	"assignment\"A\n" +
+	"\x12RevokeRoleResponse\x12+\n" +
+	"\x11consistency_token\x18\x01 \x01(\tR\x10consistencyToken\";\n" +
+	"\x10ListRolesRequest\x12'\n" +
+	"\n" +
+	"realm_hash\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\trealmHash\"T\n" +
+	"\x11ListRolesResponse\x12?\n" +
+	"\vassignments\x18\x01 \x03(\v2\x1d.identities.v1.RoleAssignmentR\vassignments\"w\n" +
+	"\fRelationship\x12\x1f\n" +
+	"\x06object\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x06object\x12#\n" +
+	"\brelation\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\brelation\x12!\n" +
+	"\asubject\x18\x03 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\asubject\"\xa4\x01\n" +
+	"\fCheckRequest\x12!\n" +
+	"\asubject\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\asubject\x12#\n" +
+	"\brelation\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\brelation\x12\x1f\n" +
+	"\x06object\x18\x03 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x06object\x12+\n" +
+	"\x11consistency_token\x18\x04 \x01(\tR\x10consistencyToken\"}\n" +
+	"\rCheckResponse\x12\x18\n" +
+	"\aallowed\x18\x01 \x01(\bR\aallowed\x12+\n" +
+	"\x11consistency_token\x18\x02 \x01(\tR\x10consistencyToken\x12%\n" +
+	"\x0epolicy_version\x18\x03 \x01(\tR\rpolicyVersion\"c\n" +
+	"\x18WriteRelationshipRequest\x12G\n" +
+	"\frelationship\x18\x01 \x01(\v2\x1b.identities.v1.RelationshipB\x06\xbaH\x03\xc8\x01\x01R\frelationship\"H\n" +
+	"\x19WriteRelationshipResponse\x12+\n" +
+	"\x11consistency_token\x18\x01 \x01(\tR\x10consistencyToken\"d\n" +
+	"\x19DeleteRelationshipRequest\x12G\n" +
+	"\frelationship\x18\x01 \x01(\v2\x1b.identities.v1.RelationshipB\x06\xbaH\x03\xc8\x01\x01R\frelationship\"I\n" +
+	"\x1aDeleteRelationshipResponse\x12+\n" +
+	"\x11consistency_token\x18\x01 \x01(\tR\x10consistencyToken2\xce\x0f\n" +
+	"\x11IdentitiesService\x12V\n" +
+	"\vCreateRealm\x12!.identities.v1.CreateRealmRequest\x1a\".identities.v1.CreateRealmResponse\"\x00\x12M\n" +
+	"\bGetRealm\x12\x1e.identities.v1.GetRealmRequest\x1a\x1f.identities.v1.GetRealmResponse\"\x00\x12S\n" +
+	"\n" +
+	"ListRealms\x12 .identities.v1.ListRealmsRequest\x1a!.identities.v1.ListRealmsResponse\"\x00\x12V\n" +
+	"\vUpdateRealm\x12!.identities.v1.UpdateRealmRequest\x1a\".identities.v1.UpdateRealmResponse\"\x00\x12V\n" +
+	"\vDeleteRealm\x12!.identities.v1.DeleteRealmRequest\x1a\".identities.v1.DeleteRealmResponse\"\x00\x12S\n" +
+	"\n" +
+	"CreateUser\x12 .identities.v1.CreateUserRequest\x1a!.identities.v1.CreateUserResponse\"\x00\x12J\n" +
+	"\aGetUser\x12\x1d.identities.v1.GetUserRequest\x1a\x1e.identities.v1.GetUserResponse\"\x00\x12P\n" +
+	"\tListUsers\x12\x1f.identities.v1.ListUsersRequest\x1a .identities.v1.ListUsersResponse\"\x00\x12S\n" +
+	"\n" +
+	"UpdateUser\x12 .identities.v1.UpdateUserRequest\x1a!.identities.v1.UpdateUserResponse\"\x00\x12S\n" +
+	"\n" +
+	"DeleteUser\x12 .identities.v1.DeleteUserRequest\x1a!.identities.v1.DeleteUserResponse\"\x00\x12V\n" +
+	"\vCreateGroup\x12!.identities.v1.CreateGroupRequest\x1a\".identities.v1.CreateGroupResponse\"\x00\x12M\n" +
+	"\bGetGroup\x12\x1e.identities.v1.GetGroupRequest\x1a\x1f.identities.v1.GetGroupResponse\"\x00\x12S\n" +
+	"\n" +
+	"ListGroups\x12 .identities.v1.ListGroupsRequest\x1a!.identities.v1.ListGroupsResponse\"\x00\x12V\n" +
+	"\vUpdateGroup\x12!.identities.v1.UpdateGroupRequest\x1a\".identities.v1.UpdateGroupResponse\"\x00\x12V\n" +
+	"\vDeleteGroup\x12!.identities.v1.DeleteGroupRequest\x1a\".identities.v1.DeleteGroupResponse\"\x00\x12P\n" +
+	"\tAddMember\x12\x1f.identities.v1.AddMemberRequest\x1a .identities.v1.AddMemberResponse\"\x00\x12Y\n" +
+	"\fRemoveMember\x12\".identities.v1.RemoveMemberRequest\x1a#.identities.v1.RemoveMemberResponse\"\x00\x12S\n" +
+	"\n" +
+	"AssignRole\x12 .identities.v1.AssignRoleRequest\x1a!.identities.v1.AssignRoleResponse\"\x00\x12S\n" +
 	"\n" +
-	"CreateUser\x12 .identities.v1.CreateUserRequest\x1a!.identities.v1.CreateUserResponse\"\x00B*Z(github.com/structx/idp/api/identities/v1b\x06proto3"
+	"RevokeRole\x12 .identities.v1.RevokeRoleRequest\x1a!.identities.v1.RevokeRoleResponse\"\x00\x12P\n" +
+	"\tListRoles\x12\x1f.identities.v1.ListRolesRequest\x1a .identities.v1.ListRolesResponse\"\x00\x12D\n" +
+	"\x05Check\x12\x1b.identities.v1.CheckRequest\x1a\x1c.identities.v1.CheckResponse\"\x00\x12h\n" +
+	"\x11WriteRelationship\x12'.identities.v1.WriteRelationshipRequest\x1a(.identities.v1.WriteRelationshipResponse\"\x00\x12k\n" +
+	"\x12DeleteRelationship\x12(.identities.v1.DeleteRelationshipRequest\x1a).identities.v1.DeleteRelationshipResponse\"\x00B*Z(github.com/structx/idp/api/identities/v1b\x06proto3"
 
 var (
 	file_identities_v1_identities_service_proto_rawDescOnce sync.Once
@@ -339,27 +2776,132 @@ func file_identities_v1_identities_service_proto_rawDescGZIP() []byte {
 	return file_identities_v1_identities_service_proto_rawDescData
 }
 
-var file_identities_v1_identities_service_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_identities_v1_identities_service_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
 var file_identities_v1_identities_service_proto_goTypes = []any{
-	(*RealmCreate)(nil),         // 0: identities.v1.RealmCreate
-	(*CreateRealmRequest)(nil),  // 1: identities.v1.CreateRealmRequest
-	(*Realm)(nil),               // 2: identities.v1.Realm
-	(*CreateRealmResponse)(nil), // 3: identities.v1.CreateRealmResponse
-	(*CreateUserRequest)(nil),   // 4: identities.v1.CreateUserRequest
-	(*CreateUserResponse)(nil),  // 5: identities.v1.CreateUserResponse
+	(*RealmCreate)(nil),                // 0: identities.v1.RealmCreate
+	(*CreateRealmRequest)(nil),         // 1: identities.v1.CreateRealmRequest
+	(*Realm)(nil),                      // 2: identities.v1.Realm
+	(*CreateRealmResponse)(nil),        // 3: identities.v1.CreateRealmResponse
+	(*GetRealmRequest)(nil),            // 4: identities.v1.GetRealmRequest
+	(*GetRealmResponse)(nil),           // 5: identities.v1.GetRealmResponse
+	(*ListRealmsRequest)(nil),          // 6: identities.v1.ListRealmsRequest
+	(*ListRealmsResponse)(nil),         // 7: identities.v1.ListRealmsResponse
+	(*UpdateRealmRequest)(nil),         // 8: identities.v1.UpdateRealmRequest
+	(*UpdateRealmResponse)(nil),        // 9: identities.v1.UpdateRealmResponse
+	(*DeleteRealmRequest)(nil),         // 10: identities.v1.DeleteRealmRequest
+	(*DeleteRealmResponse)(nil),        // 11: identities.v1.DeleteRealmResponse
+	(*User)(nil),                       // 12: identities.v1.User
+	(*CreateUserRequest)(nil),          // 13: identities.v1.CreateUserRequest
+	(*CreateUserResponse)(nil),         // 14: identities.v1.CreateUserResponse
+	(*GetUserRequest)(nil),             // 15: identities.v1.GetUserRequest
+	(*GetUserResponse)(nil),            // 16: identities.v1.GetUserResponse
+	(*ListUsersRequest)(nil),           // 17: identities.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),          // 18: identities.v1.ListUsersResponse
+	(*UpdateUserRequest)(nil),          // 19: identities.v1.UpdateUserRequest
+	(*UpdateUserResponse)(nil),         // 20: identities.v1.UpdateUserResponse
+	(*DeleteUserRequest)(nil),          // 21: identities.v1.DeleteUserRequest
+	(*DeleteUserResponse)(nil),         // 22: identities.v1.DeleteUserResponse
+	(*Group)(nil),                      // 23: identities.v1.Group
+	(*CreateGroupRequest)(nil),         // 24: identities.v1.CreateGroupRequest
+	(*CreateGroupResponse)(nil),        // 25: identities.v1.CreateGroupResponse
+	(*GetGroupRequest)(nil),            // 26: identities.v1.GetGroupRequest
+	(*GetGroupResponse)(nil),           // 27: identities.v1.GetGroupResponse
+	(*ListGroupsRequest)(nil),          // 28: identities.v1.ListGroupsRequest
+	(*ListGroupsResponse)(nil),         // 29: identities.v1.ListGroupsResponse
+	(*UpdateGroupRequest)(nil),         // 30: identities.v1.UpdateGroupRequest
+	(*UpdateGroupResponse)(nil),        // 31: identities.v1.UpdateGroupResponse
+	(*DeleteGroupRequest)(nil),         // 32: identities.v1.DeleteGroupRequest
+	(*DeleteGroupResponse)(nil),        // 33: identities.v1.DeleteGroupResponse
+	(*AddMemberRequest)(nil),           // 34: identities.v1.AddMemberRequest
+	(*AddMemberResponse)(nil),          // 35: identities.v1.AddMemberResponse
+	(*RemoveMemberRequest)(nil),        // 36: identities.v1.RemoveMemberRequest
+	(*RemoveMemberResponse)(nil),       // 37: identities.v1.RemoveMemberResponse
+	(*RoleAssignment)(nil),             // 38: identities.v1.RoleAssignment
+	(*AssignRoleRequest)(nil),          // 39: identities.v1.AssignRoleRequest
+	(*AssignRoleResponse)(nil),         // 40: identities.v1.AssignRoleResponse
+	(*RevokeRoleRequest)(nil),          // 41: identities.v1.RevokeRoleRequest
+	(*RevokeRoleResponse)(nil),         // 42: identities.v1.RevokeRoleResponse
+	(*ListRolesRequest)(nil),           // 43: identities.v1.ListRolesRequest
+	(*ListRolesResponse)(nil),          // 44: identities.v1.ListRolesResponse
+	(*Relationship)(nil),               // 45: identities.v1.Relationship
+	(*CheckRequest)(nil),               // 46: identities.v1.CheckRequest
+	(*CheckResponse)(nil),              // 47: identities.v1.CheckResponse
+	(*WriteRelationshipRequest)(nil),   // 48: identities.v1.WriteRelationshipRequest
+	(*WriteRelationshipResponse)(nil),  // 49: identities.v1.WriteRelationshipResponse
+	(*DeleteRelationshipRequest)(nil),  // 50: identities.v1.DeleteRelationshipRequest
+	(*DeleteRelationshipResponse)(nil), // 51: identities.v1.DeleteRelationshipResponse
 }
 var file_identities_v1_identities_service_proto_depIdxs = []int32{
-	0, // 0: identities.v1.CreateRealmRequest.create:type_name -> identities.v1.RealmCreate
-	2, // 1: identities.v1.CreateRealmResponse.realm:type_name -> identities.v1.Realm
-	1, // 2: identities.v1.IdentitiesService.CreateRealm:input_type -> identities.v1.CreateRealmRequest
-	4, // 3: identities.v1.IdentitiesService.CreateUser:input_type -> identities.v1.CreateUserRequest
-	3, // 4: identities.v1.IdentitiesService.CreateRealm:output_type -> identities.v1.CreateRealmResponse
-	5, // 5: identities.v1.IdentitiesService.CreateUser:output_type -> identities.v1.CreateUserResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: identities.v1.CreateRealmRequest.create:type_name -> identities.v1.RealmCreate
+	2,  // 1: identities.v1.CreateRealmResponse.realm:type_name -> identities.v1.Realm
+	12, // 2: identities.v1.CreateRealmResponse.owner:type_name -> identities.v1.User
+	2,  // 3: identities.v1.GetRealmResponse.realm:type_name -> identities.v1.Realm
+	2,  // 4: identities.v1.ListRealmsResponse.realms:type_name -> identities.v1.Realm
+	2,  // 5: identities.v1.UpdateRealmResponse.realm:type_name -> identities.v1.Realm
+	12, // 6: identities.v1.CreateUserResponse.user:type_name -> identities.v1.User
+	12, // 7: identities.v1.GetUserResponse.user:type_name -> identities.v1.User
+	12, // 8: identities.v1.ListUsersResponse.users:type_name -> identities.v1.User
+	12, // 9: identities.v1.UpdateUserResponse.user:type_name -> identities.v1.User
+	23, // 10: identities.v1.CreateGroupResponse.group:type_name -> identities.v1.Group
+	23, // 11: identities.v1.GetGroupResponse.group:type_name -> identities.v1.Group
+	23, // 12: identities.v1.ListGroupsResponse.groups:type_name -> identities.v1.Group
+	23, // 13: identities.v1.UpdateGroupResponse.group:type_name -> identities.v1.Group
+	38, // 14: identities.v1.AssignRoleRequest.assignment:type_name -> identities.v1.RoleAssignment
+	38, // 15: identities.v1.RevokeRoleRequest.assignment:type_name -> identities.v1.RoleAssignment
+	38, // 16: identities.v1.ListRolesResponse.assignments:type_name -> identities.v1.RoleAssignment
+	45, // 17: identities.v1.WriteRelationshipRequest.relationship:type_name -> identities.v1.Relationship
+	45, // 18: identities.v1.DeleteRelationshipRequest.relationship:type_name -> identities.v1.Relationship
+	1,  // 19: identities.v1.IdentitiesService.CreateRealm:input_type -> identities.v1.CreateRealmRequest
+	4,  // 20: identities.v1.IdentitiesService.GetRealm:input_type -> identities.v1.GetRealmRequest
+	6,  // 21: identities.v1.IdentitiesService.ListRealms:input_type -> identities.v1.ListRealmsRequest
+	8,  // 22: identities.v1.IdentitiesService.UpdateRealm:input_type -> identities.v1.UpdateRealmRequest
+	10, // 23: identities.v1.IdentitiesService.DeleteRealm:input_type -> identities.v1.DeleteRealmRequest
+	13, // 24: identities.v1.IdentitiesService.CreateUser:input_type -> identities.v1.CreateUserRequest
+	15, // 25: identities.v1.IdentitiesService.GetUser:input_type -> identities.v1.GetUserRequest
+	17, // 26: identities.v1.IdentitiesService.ListUsers:input_type -> identities.v1.ListUsersRequest
+	19, // 27: identities.v1.IdentitiesService.UpdateUser:input_type -> identities.v1.UpdateUserRequest
+	21, // 28: identities.v1.IdentitiesService.DeleteUser:input_type -> identities.v1.DeleteUserRequest
+	24, // 29: identities.v1.IdentitiesService.CreateGroup:input_type -> identities.v1.CreateGroupRequest
+	26, // 30: identities.v1.IdentitiesService.GetGroup:input_type -> identities.v1.GetGroupRequest
+	28, // 31: identities.v1.IdentitiesService.ListGroups:input_type -> identities.v1.ListGroupsRequest
+	30, // 32: identities.v1.IdentitiesService.UpdateGroup:input_type -> identities.v1.UpdateGroupRequest
+	32, // 33: identities.v1.IdentitiesService.DeleteGroup:input_type -> identities.v1.DeleteGroupRequest
+	34, // 34: identities.v1.IdentitiesService.AddMember:input_type -> identities.v1.AddMemberRequest
+	36, // 35: identities.v1.IdentitiesService.RemoveMember:input_type -> identities.v1.RemoveMemberRequest
+	39, // 36: identities.v1.IdentitiesService.AssignRole:input_type -> identities.v1.AssignRoleRequest
+	41, // 37: identities.v1.IdentitiesService.RevokeRole:input_type -> identities.v1.RevokeRoleRequest
+	43, // 38: identities.v1.IdentitiesService.ListRoles:input_type -> identities.v1.ListRolesRequest
+	46, // 39: identities.v1.IdentitiesService.Check:input_type -> identities.v1.CheckRequest
+	48, // 40: identities.v1.IdentitiesService.WriteRelationship:input_type -> identities.v1.WriteRelationshipRequest
+	50, // 41: identities.v1.IdentitiesService.DeleteRelationship:input_type -> identities.v1.DeleteRelationshipRequest
+	3,  // 42: identities.v1.IdentitiesService.CreateRealm:output_type -> identities.v1.CreateRealmResponse
+	5,  // 43: identities.v1.IdentitiesService.GetRealm:output_type -> identities.v1.GetRealmResponse
+	7,  // 44: identities.v1.IdentitiesService.ListRealms:output_type -> identities.v1.ListRealmsResponse
+	9,  // 45: identities.v1.IdentitiesService.UpdateRealm:output_type -> identities.v1.UpdateRealmResponse
+	11, // 46: identities.v1.IdentitiesService.DeleteRealm:output_type -> identities.v1.DeleteRealmResponse
+	14, // 47: identities.v1.IdentitiesService.CreateUser:output_type -> identities.v1.CreateUserResponse
+	16, // 48: identities.v1.IdentitiesService.GetUser:output_type -> identities.v1.GetUserResponse
+	18, // 49: identities.v1.IdentitiesService.ListUsers:output_type -> identities.v1.ListUsersResponse
+	20, // 50: identities.v1.IdentitiesService.UpdateUser:output_type -> identities.v1.UpdateUserResponse
+	22, // 51: identities.v1.IdentitiesService.DeleteUser:output_type -> identities.v1.DeleteUserResponse
+	25, // 52: identities.v1.IdentitiesService.CreateGroup:output_type -> identities.v1.CreateGroupResponse
+	27, // 53: identities.v1.IdentitiesService.GetGroup:output_type -> identities.v1.GetGroupResponse
+	29, // 54: identities.v1.IdentitiesService.ListGroups:output_type -> identities.v1.ListGroupsResponse
+	31, // 55: identities.v1.IdentitiesService.UpdateGroup:output_type -> identities.v1.UpdateGroupResponse
+	33, // 56: identities.v1.IdentitiesService.DeleteGroup:output_type -> identities.v1.DeleteGroupResponse
+	35, // 57: identities.v1.IdentitiesService.AddMember:output_type -> identities.v1.AddMemberResponse
+	37, // 58: identities.v1.IdentitiesService.RemoveMember:output_type -> identities.v1.RemoveMemberResponse
+	40, // 59: identities.v1.IdentitiesService.AssignRole:output_type -> identities.v1.AssignRoleResponse
+	42, // 60: identities.v1.IdentitiesService.RevokeRole:output_type -> identities.v1.RevokeRoleResponse
+	44, // 61: identities.v1.IdentitiesService.ListRoles:output_type -> identities.v1.ListRolesResponse
+	47, // 62: identities.v1.IdentitiesService.Check:output_type -> identities.v1.CheckResponse
+	49, // 63: identities.v1.IdentitiesService.WriteRelationship:output_type -> identities.v1.WriteRelationshipResponse
+	51, // 64: identities.v1.IdentitiesService.DeleteRelationship:output_type -> identities.v1.DeleteRelationshipResponse
+	42, // [42:65] is the sub-list for method output_type
+	19, // [19:42] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_identities_v1_identities_service_proto_init() }
@@ -373,7 +2915,7 @@ func file_identities_v1_identities_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_identities_v1_identities_service_proto_rawDesc), len(file_identities_v1_identities_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   52,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
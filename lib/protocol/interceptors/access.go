@@ -2,26 +2,80 @@ package interceptors
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	pbaudit "github.com/trevatk/tbd/lib/protocol/audit/v1"
+)
+
+const (
+	// Authenticated relation granted to every authenticated caller,
+	// resolved for methods open to any user
+	Authenticated = "authenticated"
+
+	recordTimeout = time.Second * 5
 )
 
+// Decision result of an access check
+type Decision struct {
+	Allowed bool
+	// PolicyVersion version of the policy the check evaluated
+	PolicyVersion string
+}
+
 // Checker interceptor access control
 type Checker interface {
-	HasResourceAccess(userHash, resourceHash string) bool
+	// Check subject holds relation to object
+	Check(ctx context.Context, subject, relation, object string) (Decision, error)
+}
+
+// Resolver relation and object a request requires of its caller
+type Resolver func(ctx context.Context, fullMethod string, req any) (relation, object string, err error)
+
+// DecisionRecorder records access decisions, satisfied by the audit service client
+type DecisionRecorder interface {
+	Decision(ctx context.Context, in *pbaudit.DecisionRequest, opts ...grpc.CallOption) (*pbaudit.DecisionResponse, error)
 }
 
 // AccessControl access control wrapper
 type AccessControl struct {
-	c Checker
+	c       Checker
+	resolve Resolver
+
+	recorder DecisionRecorder
 }
 
+// AccessControlOption access control option pattern
+type AccessControlOption func(*AccessControl)
+
 // NewAccessControl return new access control wrapper with gRPC unary interceptor
-func NewAccessControl(checker Checker) *AccessControl {
-	return &AccessControl{
-		c: checker,
+//
+// resolver names the relation and object checked for each request
+func NewAccessControl(checker Checker, resolver Resolver, opts ...AccessControlOption) *AccessControl {
+	ac := &AccessControl{
+		c:       checker,
+		resolve: resolver,
+	}
+
+	for _, opt := range opts {
+		opt(ac)
+	}
+
+	return ac
+}
+
+// WithDecisionRecorder record every access decision
+//
+// a request is rejected as unavailable when its
+// decision cannot be recorded
+func WithDecisionRecorder(recorder DecisionRecorder) AccessControlOption {
+	return func(ac *AccessControl) {
+		ac.recorder = recorder
 	}
 }
 
@@ -32,10 +86,62 @@ func (ac *AccessControl) EnsureResourceAccess() grpc.UnaryServerInterceptor {
 		// interceptor func check if user has access to requested resource
 		userHash := ctx.Value(User).(string)
 
-		granted := ac.c.HasResourceAccess(userHash, "")
-		if !granted {
+		relation, object, err := ac.resolve(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, codes.InvalidArgument.String())
+		}
+
+		// checks that fail deny the request
+		var d Decision
+		if relation == Authenticated {
+			d.Allowed = userHash != Anon
+		} else {
+			d, err = ac.c.Check(ctx, userHash, relation, object)
+			d.Allowed = d.Allowed && err == nil
+		}
+
+		err = ac.record(ctx, userHash, relation, object, d)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, codes.Unavailable.String())
+		}
+
+		if !d.Allowed {
 			return nil, status.Error(codes.PermissionDenied, codes.PermissionDenied.String())
 		}
 		return handler(ctx, req)
 	}
 }
+
+// record send decision to the recorder when configured,
+// the object is recorded as resource and the relation as action
+func (ac *AccessControl) record(ctx context.Context, subject, relation, object string, d Decision) error {
+	if ac.recorder == nil {
+		return nil
+	}
+
+	outcome := pbaudit.Outcome_OUTCOME_DENY
+	if d.Allowed {
+		outcome = pbaudit.Outcome_OUTCOME_ALLOW
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, recordTimeout)
+	defer cancel()
+
+	_, err := ac.recorder.Decision(ctx, &pbaudit.DecisionRequest{
+		Subject:       subject,
+		Resource:      object,
+		Action:        relation,
+		Outcome:       outcome,
+		PolicyVersion: d.PolicyVersion,
+		RequestId:     requestID(),
+	})
+	return err
+}
+
+// requestID random id of the request, generated by the server
+// so callers cannot collide with decisions of other requests
+func requestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
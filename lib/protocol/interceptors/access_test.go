@@ -0,0 +1,108 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbaudit "github.com/trevatk/tbd/lib/protocol/audit/v1"
+)
+
+// checker allows the subjects it holds
+type checker map[string]bool
+
+func (c checker) Check(_ context.Context, subject, _, _ string) (Decision, error) {
+	return Decision{Allowed: c[subject], PolicyVersion: "v1"}, nil
+}
+
+// recorder keeps every decision or fails them all
+type recorder struct {
+	decisions []*pbaudit.DecisionRequest
+	err       error
+}
+
+func (r *recorder) Decision(_ context.Context, in *pbaudit.DecisionRequest, _ ...grpc.CallOption) (*pbaudit.DecisionResponse, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.decisions = append(r.decisions, in)
+	return &pbaudit.DecisionResponse{}, nil
+}
+
+func resolveRealm(_ context.Context, fullMethod string, _ any) (string, string, error) {
+	if fullMethod == "/realms/List" {
+		return Authenticated, fullMethod, nil
+	}
+	return "view", "acme", nil
+}
+
+func TestEnsureResourceAccess(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    string
+		method  string
+		err     error
+		code    codes.Code
+		outcome pbaudit.Outcome
+	}{
+		{name: "allow", user: "alice", method: "/realms/Get", code: codes.OK, outcome: pbaudit.Outcome_OUTCOME_ALLOW},
+		{name: "deny", user: "bob", method: "/realms/Get", code: codes.PermissionDenied, outcome: pbaudit.Outcome_OUTCOME_DENY},
+		{name: "authenticated", user: "bob", method: "/realms/List", code: codes.OK, outcome: pbaudit.Outcome_OUTCOME_ALLOW},
+		{name: "anonymous", user: Anon, method: "/realms/List", code: codes.PermissionDenied, outcome: pbaudit.Outcome_OUTCOME_DENY},
+		{name: "recorder failure", user: "alice", method: "/realms/Get", err: errors.New("unavailable"), code: codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{err: tt.err}
+			ac := NewAccessControl(checker{"alice": true}, resolveRealm, WithDecisionRecorder(r))
+
+			handled := false
+			handler := func(context.Context, any) (any, error) {
+				handled = true
+				return "ok", nil
+			}
+
+			ctx := context.WithValue(context.Background(), User, tt.user)
+			_, err := ac.EnsureResourceAccess()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("unexpected code %s expected %s", code, tt.code)
+			}
+			if handled != (tt.code == codes.OK) {
+				t.Fatalf("unexpected handled %t", handled)
+			}
+
+			if tt.err != nil {
+				return
+			}
+			if len(r.decisions) != 1 {
+				t.Fatalf("unexpected %d decisions expected 1", len(r.decisions))
+			}
+			d := r.decisions[0]
+			if d.Subject != tt.user || d.Outcome != tt.outcome || d.RequestId == "" {
+				t.Fatalf("unexpected decision %+v", d)
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	r := &recorder{}
+	ac := NewAccessControl(checker{"alice": true}, resolveRealm, WithDecisionRecorder(r))
+
+	ctx := context.WithValue(context.Background(), User, "alice")
+	for range 2 {
+		_, err := ac.EnsureResourceAccess()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/realms/Get"},
+			func(context.Context, any) (any, error) { return nil, nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.decisions[0].RequestId == r.decisions[1].RequestId {
+		t.Fatalf("requests share id %s", r.decisions[0].RequestId)
+	}
+}
@@ -83,3 +83,45 @@ func (a *Auth) valid(authorization []string) (string, bool) {
 	}
 	return claims["user_id"].(string), true
 }
+
+// RequireCaller gRPC unary interceptor admitting only callers to
+// methods, every other method is left to the handler
+//
+// must run after ValidToken, anonymous callers are unauthenticated
+// and any other caller is denied
+func RequireCaller(methods []string, callers ...string) grpc.UnaryServerInterceptor {
+	guarded := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		guarded[method] = true
+	}
+	allowed := make(map[string]bool, len(callers))
+	for _, caller := range callers {
+		allowed[caller] = true
+	}
+
+	return func(ctx context.Context, req any,
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		if !guarded[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		caller, _ := ctx.Value(User).(string)
+		if caller == "" || caller == Anon {
+			return nil, status.Error(codes.Unauthenticated, codes.Unauthenticated.String())
+		} else if !allowed[caller] {
+			return nil, status.Error(codes.PermissionDenied, codes.PermissionDenied.String())
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// BearerToken gRPC unary client interceptor sending token
+// as the authorization of every call
+func BearerToken(token string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,39 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequireCaller(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   string
+		method string
+		code   codes.Code
+	}{
+		{name: "allowed caller", user: "identities", method: "/audit/Decision", code: codes.OK},
+		{name: "other caller", user: "alice", method: "/audit/Decision", code: codes.PermissionDenied},
+		{name: "anonymous", user: Anon, method: "/audit/Decision", code: codes.Unauthenticated},
+		{name: "unguarded method", user: Anon, method: "/audit/List", code: codes.OK},
+	}
+
+	interceptor := RequireCaller([]string{"/audit/Decision"}, "identities")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(context.Context, any) (any, error) {
+				return "ok", nil
+			}
+
+			ctx := context.WithValue(context.Background(), User, tt.user)
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("unexpected code %s expected %s", code, tt.code)
+			}
+		})
+	}
+}
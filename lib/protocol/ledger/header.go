@@ -0,0 +1,103 @@
+package ledger
+
+import (
+	"crypto/sha3"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
+)
+
+const (
+	// HashSize size of block hashes and merkle roots
+	HashSize = 32
+	// KeySize size of signer public keys
+	KeySize = 32
+)
+
+var (
+	// ErrHashMismatch header hash does not match its fields
+	ErrHashMismatch = errors.New("header hash mismatch")
+	// ErrBlockMismatch proof is for another block
+	ErrBlockMismatch = errors.New("proof block does not match header")
+)
+
+// EncodeHeader fixed size encoding of the fields covered by the block hash
+//
+// | height uint64 | prev hash | merkle root | timestamp unix nano int64 | tx count uint64 | signer key |
+//
+// the tx count fixes the size of the tree under the root and the
+// signer key binds the hash to the key its signature verifies with
+func EncodeHeader(h *pb.BlockHeader) ([]byte, error) {
+	prev := make([]byte, HashSize)
+	if h.PrevHash != "" {
+		hb, err := hex.DecodeString(h.PrevHash)
+		if err != nil || len(hb) != HashSize {
+			return nil, fmt.Errorf("invalid prev hash %q", h.PrevHash)
+		}
+		prev = hb
+	}
+
+	root, err := hex.DecodeString(h.MerkleRoot)
+	if err != nil || len(root) != HashSize {
+		return nil, fmt.Errorf("invalid merkle root %q", h.MerkleRoot)
+	}
+
+	if h.TxCount < 0 {
+		return nil, fmt.Errorf("invalid tx count %d", h.TxCount)
+	}
+
+	signer, err := hex.DecodeString(h.Signer)
+	if err != nil || len(signer) != KeySize {
+		return nil, fmt.Errorf("invalid signer %q", h.Signer)
+	}
+
+	buf := make([]byte, 0, 8+HashSize*2+8+8+KeySize)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(h.Height))
+	buf = append(buf, prev...)
+	buf = append(buf, root...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(h.Timestamp.AsTime().UnixNano()))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(h.TxCount))
+	buf = append(buf, signer...)
+
+	return buf, nil
+}
+
+// HeaderHash hex sha3-256 of the encoded header
+func HeaderHash(h *pb.BlockHeader) (string, error) {
+	eh, err := EncodeHeader(h)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha3.Sum256(eh)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyProof check the proof against a published block header
+//
+// the header hash is recomputed from its fields, the signature
+// over the hash and trust in its signer are left to the caller
+func VerifyProof(p *pb.Proof, h *pb.BlockHeader) error {
+	hash, err := HeaderHash(h)
+	if err != nil {
+		return err
+	}
+	if hash != h.Hash {
+		return ErrHashMismatch
+	}
+
+	if p.BlockHash != h.Hash || p.Height != h.Height {
+		return ErrBlockMismatch
+	}
+
+	leaf, err := hex.DecodeString(p.TxHash)
+	if err != nil {
+		return fmt.Errorf("invalid tx hash %q", p.TxHash)
+	}
+
+	root, _ := hex.DecodeString(h.MerkleRoot)
+	return VerifyPath(leaf, p.Index, h.TxCount, p.Path, root)
+}
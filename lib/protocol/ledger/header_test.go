@@ -0,0 +1,152 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
+)
+
+// testBlock header over leaves and a proof of the leaf at index
+func testBlock(t *testing.T, leaves [][]byte, index int) (*pb.BlockHeader, *pb.Proof) {
+	t.Helper()
+
+	h := &pb.BlockHeader{
+		PrevHash:   hex.EncodeToString(bytes.Repeat([]byte{0x01}, HashSize)),
+		Height:     2,
+		MerkleRoot: hex.EncodeToString(MerkleRoot(leaves)),
+		Timestamp:  timestamppb.New(time.Unix(1700000000, 0)),
+		TxCount:    int64(len(leaves)),
+		Signer:     hex.EncodeToString(bytes.Repeat([]byte{0x02}, KeySize)),
+	}
+
+	var err error
+	h.Hash, err = HeaderHash(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := MerkleProof(leaves, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h, &pb.Proof{
+		TxHash:    hex.EncodeToString(leaves[index]),
+		BlockHash: h.Hash,
+		Height:    h.Height,
+		Path:      path,
+		Index:     int64(index),
+	}
+}
+
+func TestVerifyProof(t *testing.T) {
+	leaves := testLeaves(5)
+
+	tests := []struct {
+		name   string
+		tamper func(h *pb.BlockHeader, p *pb.Proof)
+		err    error
+	}{
+		{
+			name:   "valid",
+			tamper: func(*pb.BlockHeader, *pb.Proof) {},
+		},
+		{
+			name: "modified leaf",
+			tamper: func(_ *pb.BlockHeader, p *pb.Proof) {
+				p.TxHash = hex.EncodeToString(leaves[1])
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			name: "wrong index",
+			tamper: func(_ *pb.BlockHeader, p *pb.Proof) {
+				p.Index = 2
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			name: "truncated proof",
+			tamper: func(_ *pb.BlockHeader, p *pb.Proof) {
+				p.Path = p.Path[:len(p.Path)-1]
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			name: "modified root",
+			tamper: func(h *pb.BlockHeader, _ *pb.Proof) {
+				h.MerkleRoot = hex.EncodeToString(MerkleRoot(leaves[:4]))
+			},
+			err: ErrHashMismatch,
+		},
+		{
+			name: "modified tx count",
+			tamper: func(h *pb.BlockHeader, _ *pb.Proof) {
+				h.TxCount++
+			},
+			err: ErrHashMismatch,
+		},
+		{
+			name: "modified height",
+			tamper: func(h *pb.BlockHeader, p *pb.Proof) {
+				h.Height++
+				p.Height++
+			},
+			err: ErrHashMismatch,
+		},
+		{
+			name: "wrong signer",
+			tamper: func(h *pb.BlockHeader, _ *pb.Proof) {
+				h.Signer = hex.EncodeToString(bytes.Repeat([]byte{0x03}, KeySize))
+			},
+			err: ErrHashMismatch,
+		},
+		{
+			name: "other block",
+			tamper: func(_ *pb.BlockHeader, p *pb.Proof) {
+				p.BlockHash = hex.EncodeToString(bytes.Repeat([]byte{0x04}, HashSize))
+			},
+			err: ErrBlockMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, p := testBlock(t, leaves, 3)
+			tt.tamper(h, p)
+
+			err := VerifyProof(p, h)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("unexpected error %v expected %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestEncodeHeaderInvalid(t *testing.T) {
+	leaves := testLeaves(2)
+
+	tests := map[string]func(h *pb.BlockHeader){
+		"short signer":      func(h *pb.BlockHeader) { h.Signer = h.Signer[2:] },
+		"missing signer":    func(h *pb.BlockHeader) { h.Signer = "" },
+		"negative tx count": func(h *pb.BlockHeader) { h.TxCount = -1 },
+		"invalid root":      func(h *pb.BlockHeader) { h.MerkleRoot = "root" },
+	}
+
+	for name, tamper := range tests {
+		t.Run(name, func(t *testing.T) {
+			h, _ := testBlock(t, leaves, 0)
+			tamper(h)
+
+			if _, err := EncodeHeader(h); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
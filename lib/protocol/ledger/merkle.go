@@ -0,0 +1,146 @@
+// Package ledger audit chain hashing shared by the audit
+// service and offline verifiers
+package ledger
+
+import (
+	"bytes"
+	"crypto/sha3"
+	"errors"
+
+	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
+)
+
+const (
+	leafPrefix byte = 0x00
+	nodePrefix byte = 0x01
+)
+
+// ErrInvalidProof proof does not lead to the expected root
+var ErrInvalidProof = errors.New("invalid proof")
+
+// MerkleRoot root of a binary hash tree over leaves
+//
+// leaves and nodes are hashed with distinct prefixes so a node
+// can never be passed off as a leaf, the last node of an odd
+// level is promoted to the next level unpaired as in RFC 6962,
+// so no two lists of leaves share a root
+func MerkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		h := sha3.Sum256(nil)
+		return h[:]
+	}
+
+	level := hashLeaves(leaves)
+	for len(level) > 1 {
+		level = nextLevel(level)
+	}
+
+	return level[0]
+}
+
+// MerkleProof path from the leaf at index up to the root
+func MerkleProof(leaves [][]byte, index int) ([]*pb.ProofStep, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, ErrInvalidProof
+	}
+
+	path := make([]*pb.ProofStep, 0)
+
+	level := hashLeaves(leaves)
+	for len(level) > 1 {
+		// odd last node is promoted without a sibling
+		sibling := index ^ 1
+		if sibling < len(level) {
+			path = append(path, &pb.ProofStep{
+				Hash: level[sibling],
+				Left: sibling < index,
+			})
+		}
+
+		level = nextLevel(level)
+		index /= 2
+	}
+
+	return path, nil
+}
+
+// VerifyPath check the path leads from the leaf at index of a
+// tree of size leaves to root
+//
+// the side of every sibling follows from index and size, RFC 9162
+// section 2.1.3.2, so a path is only valid for the position it
+// was issued for
+func VerifyPath(leaf []byte, index, size int64, path []*pb.ProofStep, root []byte) error {
+	if index < 0 || index >= size {
+		return ErrInvalidProof
+	}
+
+	fn, sn := index, size-1
+	h := hashLeaf(leaf)
+	for _, step := range path {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+
+		if fn&1 == 1 || fn == sn {
+			if !step.Left {
+				return ErrInvalidProof
+			}
+			h = hashNode(step.Hash, h)
+			// levels where the node was promoted have no sibling
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			if step.Left {
+				return ErrInvalidProof
+			}
+			h = hashNode(h, step.Hash)
+		}
+
+		fn >>= 1
+		sn >>= 1
+	}
+
+	if sn != 0 || !bytes.Equal(h, root) {
+		return ErrInvalidProof
+	}
+
+	return nil
+}
+
+func hashLeaves(leaves [][]byte) [][]byte {
+	level := make([][]byte, 0, len(leaves))
+	for _, leaf := range leaves {
+		level = append(level, hashLeaf(leaf))
+	}
+	return level
+}
+
+func nextLevel(level [][]byte) [][]byte {
+	next := make([][]byte, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			next = append(next, level[i])
+			break
+		}
+		next = append(next, hashNode(level[i], level[i+1]))
+	}
+	return next
+}
+
+func hashLeaf(leaf []byte) []byte {
+	h := sha3.Sum256(append([]byte{leafPrefix}, leaf...))
+	return h[:]
+}
+
+func hashNode(left, right []byte) []byte {
+	buf := make([]byte, 0, 1+len(left)+len(right))
+	buf = append(buf, nodePrefix)
+	buf = append(buf, left...)
+	buf = append(buf, right...)
+
+	h := sha3.Sum256(buf)
+	return h[:]
+}
@@ -0,0 +1,120 @@
+package ledger
+
+import (
+	"bytes"
+	"crypto/sha3"
+	"errors"
+	"fmt"
+	"testing"
+
+	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
+)
+
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, 0, n)
+	for i := range n {
+		h := sha3.Sum256(fmt.Appendf(nil, "tx-%d", i))
+		leaves = append(leaves, h[:])
+	}
+	return leaves
+}
+
+func TestMerkleProof(t *testing.T) {
+	// sizes of one full level and one past a power of two
+	for _, size := range []int{1, 2, 3, 4, 5, 9, 17} {
+		t.Run(fmt.Sprintf("%d leaves", size), func(t *testing.T) {
+			leaves := testLeaves(size)
+			root := MerkleRoot(leaves)
+
+			for index := range leaves {
+				path, err := MerkleProof(leaves, index)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = VerifyPath(leaves[index], int64(index), int64(size), path, root)
+				if err != nil {
+					t.Fatalf("leaf %d: %v", index, err)
+				}
+			}
+		})
+	}
+}
+
+func TestMerkleRootDuplicate(t *testing.T) {
+	// CVE-2012-2459 a repeated last leaf must not share the root
+	leaves := testLeaves(3)
+	if bytes.Equal(MerkleRoot(leaves), MerkleRoot(append(leaves, leaves[2]))) {
+		t.Fatal("root of 3 leaves matches root with last leaf repeated")
+	}
+	if bytes.Equal(MerkleRoot(leaves[:1]), leaves[0]) {
+		t.Fatal("root of a single leaf is the unhashed leaf")
+	}
+}
+
+func TestVerifyPathTampered(t *testing.T) {
+	leaves := testLeaves(9)
+	root := MerkleRoot(leaves)
+
+	tests := []struct {
+		name   string
+		tamper func(leaf []byte, index, size int64, path []*pb.ProofStep) ([]byte, int64, int64, []*pb.ProofStep)
+	}{
+		{
+			name: "modified leaf",
+			tamper: func(leaf []byte, index, size int64, path []*pb.ProofStep) ([]byte, int64, int64, []*pb.ProofStep) {
+				modified := bytes.Clone(leaf)
+				modified[0] ^= 0xff
+				return modified, index, size, path
+			},
+		},
+		{
+			name: "wrong index",
+			tamper: func(leaf []byte, index, size int64, path []*pb.ProofStep) ([]byte, int64, int64, []*pb.ProofStep) {
+				return leaf, index ^ 1, size, path
+			},
+		},
+		{
+			name: "index out of range",
+			tamper: func(leaf []byte, _, size int64, path []*pb.ProofStep) ([]byte, int64, int64, []*pb.ProofStep) {
+				return leaf, size, size, path
+			},
+		},
+		{
+			name: "truncated proof",
+			tamper: func(leaf []byte, index, size int64, path []*pb.ProofStep) ([]byte, int64, int64, []*pb.ProofStep) {
+				return leaf, index, size, path[:len(path)-1]
+			},
+		},
+		{
+			name: "extended proof",
+			tamper: func(leaf []byte, index, size int64, path []*pb.ProofStep) ([]byte, int64, int64, []*pb.ProofStep) {
+				return leaf, index, size, append(path, &pb.ProofStep{Hash: root})
+			},
+		},
+		{
+			name: "flipped side",
+			tamper: func(leaf []byte, index, size int64, path []*pb.ProofStep) ([]byte, int64, int64, []*pb.ProofStep) {
+				flipped := append([]*pb.ProofStep{{Hash: path[0].Hash, Left: !path[0].Left}}, path[1:]...)
+				return leaf, index, size, flipped
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, index := range []int{0, 4, 7} {
+				path, err := MerkleProof(leaves, index)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				leaf, i, size, path := tt.tamper(leaves[index], int64(index), int64(len(leaves)), path)
+				err = VerifyPath(leaf, i, size, path, root)
+				if !errors.Is(err, ErrInvalidProof) {
+					t.Fatalf("leaf %d: unexpected error %v expected %v", index, err, ErrInvalidProof)
+				}
+			}
+		})
+	}
+}
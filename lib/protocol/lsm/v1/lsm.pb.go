@@ -74,10 +74,15 @@ func (x *Index) GetValue() string {
 }
 
 type KeyValue struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	Ttl           int64                  `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Ttl   int64                  `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// secondary index name to indexed value
+	Indice    map[string]string `protobuf:"bytes,4,rep,name=indice,proto3" json:"indice,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tombstone bool              `protobuf:"varint,5,opt,name=tombstone,proto3" json:"tombstone,omitempty"`
+	// sequence number of the batch that wrote this version
+	Seq           uint64 `protobuf:"varint,6,opt,name=seq,proto3" json:"seq,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -133,6 +138,380 @@ func (x *KeyValue) GetTtl() int64 {
 	return 0
 }
 
+func (x *KeyValue) GetIndice() map[string]string {
+	if x != nil {
+		return x.Indice
+	}
+	return nil
+}
+
+func (x *KeyValue) GetTombstone() bool {
+	if x != nil {
+		return x.Tombstone
+	}
+	return false
+}
+
+func (x *KeyValue) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+// Batch write ahead log record
+type Batch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Entries       []*KeyValue            `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Batch) Reset() {
+	*x = Batch{}
+	mi := &file_lsm_v1_lsm_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Batch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Batch) ProtoMessage() {}
+
+func (x *Batch) ProtoReflect() protoreflect.Message {
+	mi := &file_lsm_v1_lsm_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Batch.ProtoReflect.Descriptor instead.
+func (*Batch) Descriptor() ([]byte, []int) {
+	return file_lsm_v1_lsm_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Batch) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Batch) GetEntries() []*KeyValue {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// ManifestFile data file belonging to a store
+type ManifestFile struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size  int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// sha256 of the file contents, set on checkpoints
+	Checksum      []byte `protobuf:"bytes,3,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ManifestFile) Reset() {
+	*x = ManifestFile{}
+	mi := &file_lsm_v1_lsm_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManifestFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManifestFile) ProtoMessage() {}
+
+func (x *ManifestFile) ProtoReflect() protoreflect.Message {
+	mi := &file_lsm_v1_lsm_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManifestFile.ProtoReflect.Descriptor instead.
+func (*ManifestFile) Descriptor() ([]byte, []int) {
+	return file_lsm_v1_lsm_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ManifestFile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ManifestFile) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ManifestFile) GetChecksum() []byte {
+	if x != nil {
+		return x.Checksum
+	}
+	return nil
+}
+
+// Manifest live files of a store
+type Manifest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Sstables    []*ManifestFile        `protobuf:"bytes,1,rep,name=sstables,proto3" json:"sstables,omitempty"`
+	Wals        []*ManifestFile        `protobuf:"bytes,2,rep,name=wals,proto3" json:"wals,omitempty"`
+	NextSstable uint64                 `protobuf:"varint,3,opt,name=next_sstable,json=nextSstable,proto3" json:"next_sstable,omitempty"`
+	NextWal     uint64                 `protobuf:"varint,4,opt,name=next_wal,json=nextWal,proto3" json:"next_wal,omitempty"`
+	// last committed sequence number, set on checkpoints
+	Seq uint64 `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`
+	// wrapped data encryption keys, empty when unencrypted
+	Keys []*DataKey `protobuf:"bytes,6,rep,name=keys,proto3" json:"keys,omitempty"`
+	// data key used for new writes
+	ActiveKey uint32 `protobuf:"varint,7,opt,name=active_key,json=activeKey,proto3" json:"active_key,omitempty"`
+	// flushed log segments kept for change subscribers, oldest first
+	Retained      []*ManifestFile `protobuf:"bytes,8,rep,name=retained,proto3" json:"retained,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Manifest) Reset() {
+	*x = Manifest{}
+	mi := &file_lsm_v1_lsm_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Manifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Manifest) ProtoMessage() {}
+
+func (x *Manifest) ProtoReflect() protoreflect.Message {
+	mi := &file_lsm_v1_lsm_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Manifest.ProtoReflect.Descriptor instead.
+func (*Manifest) Descriptor() ([]byte, []int) {
+	return file_lsm_v1_lsm_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Manifest) GetSstables() []*ManifestFile {
+	if x != nil {
+		return x.Sstables
+	}
+	return nil
+}
+
+func (x *Manifest) GetWals() []*ManifestFile {
+	if x != nil {
+		return x.Wals
+	}
+	return nil
+}
+
+func (x *Manifest) GetNextSstable() uint64 {
+	if x != nil {
+		return x.NextSstable
+	}
+	return 0
+}
+
+func (x *Manifest) GetNextWal() uint64 {
+	if x != nil {
+		return x.NextWal
+	}
+	return 0
+}
+
+func (x *Manifest) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Manifest) GetKeys() []*DataKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *Manifest) GetActiveKey() uint32 {
+	if x != nil {
+		return x.ActiveKey
+	}
+	return 0
+}
+
+func (x *Manifest) GetRetained() []*ManifestFile {
+	if x != nil {
+		return x.Retained
+	}
+	return nil
+}
+
+// DataKey data encryption key wrapped by a key encryption key
+type DataKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// key encryption key the data key is wrapped with
+	KekId         string `protobuf:"bytes,2,opt,name=kek_id,json=kekId,proto3" json:"kek_id,omitempty"`
+	Wrapped       []byte `protobuf:"bytes,3,opt,name=wrapped,proto3" json:"wrapped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DataKey) Reset() {
+	*x = DataKey{}
+	mi := &file_lsm_v1_lsm_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataKey) ProtoMessage() {}
+
+func (x *DataKey) ProtoReflect() protoreflect.Message {
+	mi := &file_lsm_v1_lsm_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataKey.ProtoReflect.Descriptor instead.
+func (*DataKey) Descriptor() ([]byte, []int) {
+	return file_lsm_v1_lsm_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DataKey) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DataKey) GetKekId() string {
+	if x != nil {
+		return x.KekId
+	}
+	return ""
+}
+
+func (x *DataKey) GetWrapped() []byte {
+	if x != nil {
+		return x.Wrapped
+	}
+	return nil
+}
+
+// BlockHandle location of a sorted string table data block
+type BlockHandle struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// last key stored in the block
+	LastKey string `protobuf:"bytes,1,opt,name=last_key,json=lastKey,proto3" json:"last_key,omitempty"`
+	Offset  uint64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Size    uint64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// highest sequence number stored in the block
+	MaxSeq        uint64 `protobuf:"varint,4,opt,name=max_seq,json=maxSeq,proto3" json:"max_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockHandle) Reset() {
+	*x = BlockHandle{}
+	mi := &file_lsm_v1_lsm_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockHandle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockHandle) ProtoMessage() {}
+
+func (x *BlockHandle) ProtoReflect() protoreflect.Message {
+	mi := &file_lsm_v1_lsm_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockHandle.ProtoReflect.Descriptor instead.
+func (*BlockHandle) Descriptor() ([]byte, []int) {
+	return file_lsm_v1_lsm_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BlockHandle) GetLastKey() string {
+	if x != nil {
+		return x.LastKey
+	}
+	return ""
+}
+
+func (x *BlockHandle) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *BlockHandle) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BlockHandle) GetMaxSeq() uint64 {
+	if x != nil {
+		return x.MaxSeq
+	}
+	return 0
+}
+
 var File_lsm_v1_lsm_proto protoreflect.FileDescriptor
 
 const file_lsm_v1_lsm_proto_rawDesc = "" +
@@ -140,11 +519,43 @@ const file_lsm_v1_lsm_proto_rawDesc = "" +
 	"\x10lsm/v1/lsm.proto\x12\x06lsm.v1\"M\n" +
 	"\x05Index\x12.\n" +
 	"\x13sorted_string_table\x18\x01 \x01(\tR\x11sortedStringTable\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"D\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\xe5\x01\n" +
 	"\bKeyValue\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\fR\x05value\x12\x10\n" +
-	"\x03ttl\x18\x03 \x01(\x03R\x03ttlB'Z%soft.structx.io/idp/api/gen/go/lsm/v1b\x06proto3"
+	"\x03ttl\x18\x03 \x01(\x03R\x03ttl\x124\n" +
+	"\x06indice\x18\x04 \x03(\v2\x1c.lsm.v1.KeyValue.IndiceEntryR\x06indice\x12\x1c\n" +
+	"\ttombstone\x18\x05 \x01(\bR\ttombstone\x12\x10\n" +
+	"\x03seq\x18\x06 \x01(\x04R\x03seq\x1a9\n" +
+	"\vIndiceEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"E\n" +
+	"\x05Batch\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12*\n" +
+	"\aentries\x18\x02 \x03(\v2\x10.lsm.v1.KeyValueR\aentries\"R\n" +
+	"\fManifestFile\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x1a\n" +
+	"\bchecksum\x18\x03 \x01(\fR\bchecksum\"\xac\x02\n" +
+	"\bManifest\x120\n" +
+	"\bsstables\x18\x01 \x03(\v2\x14.lsm.v1.ManifestFileR\bsstables\x12(\n" +
+	"\x04wals\x18\x02 \x03(\v2\x14.lsm.v1.ManifestFileR\x04wals\x12!\n" +
+	"\fnext_sstable\x18\x03 \x01(\x04R\vnextSstable\x12\x19\n" +
+	"\bnext_wal\x18\x04 \x01(\x04R\anextWal\x12\x10\n" +
+	"\x03seq\x18\x05 \x01(\x04R\x03seq\x12#\n" +
+	"\x04keys\x18\x06 \x03(\v2\x0f.lsm.v1.DataKeyR\x04keys\x12\x1d\n" +
+	"\n" +
+	"active_key\x18\a \x01(\rR\tactiveKey\x120\n" +
+	"\bretained\x18\b \x03(\v2\x14.lsm.v1.ManifestFileR\bretained\"J\n" +
+	"\aDataKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x15\n" +
+	"\x06kek_id\x18\x02 \x01(\tR\x05kekId\x12\x18\n" +
+	"\awrapped\x18\x03 \x01(\fR\awrapped\"m\n" +
+	"\vBlockHandle\x12\x19\n" +
+	"\blast_key\x18\x01 \x01(\tR\alastKey\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x04R\x06offset\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x04R\x04size\x12\x17\n" +
+	"\amax_seq\x18\x04 \x01(\x04R\x06maxSeqB'Z%soft.structx.io/idp/api/gen/go/lsm/v1b\x06proto3"
 
 var (
 	file_lsm_v1_lsm_proto_rawDescOnce sync.Once
@@ -158,17 +569,29 @@ func file_lsm_v1_lsm_proto_rawDescGZIP() []byte {
 	return file_lsm_v1_lsm_proto_rawDescData
 }
 
-var file_lsm_v1_lsm_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_lsm_v1_lsm_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_lsm_v1_lsm_proto_goTypes = []any{
-	(*Index)(nil),    // 0: lsm.v1.Index
-	(*KeyValue)(nil), // 1: lsm.v1.KeyValue
+	(*Index)(nil),        // 0: lsm.v1.Index
+	(*KeyValue)(nil),     // 1: lsm.v1.KeyValue
+	(*Batch)(nil),        // 2: lsm.v1.Batch
+	(*ManifestFile)(nil), // 3: lsm.v1.ManifestFile
+	(*Manifest)(nil),     // 4: lsm.v1.Manifest
+	(*DataKey)(nil),      // 5: lsm.v1.DataKey
+	(*BlockHandle)(nil),  // 6: lsm.v1.BlockHandle
+	nil,                  // 7: lsm.v1.KeyValue.IndiceEntry
 }
 var file_lsm_v1_lsm_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	7, // 0: lsm.v1.KeyValue.indice:type_name -> lsm.v1.KeyValue.IndiceEntry
+	1, // 1: lsm.v1.Batch.entries:type_name -> lsm.v1.KeyValue
+	3, // 2: lsm.v1.Manifest.sstables:type_name -> lsm.v1.ManifestFile
+	3, // 3: lsm.v1.Manifest.wals:type_name -> lsm.v1.ManifestFile
+	5, // 4: lsm.v1.Manifest.keys:type_name -> lsm.v1.DataKey
+	3, // 5: lsm.v1.Manifest.retained:type_name -> lsm.v1.ManifestFile
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_lsm_v1_lsm_proto_init() }
@@ -182,7 +605,7 @@ func file_lsm_v1_lsm_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lsm_v1_lsm_proto_rawDesc), len(file_lsm_v1_lsm_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -18,8 +18,9 @@ type Server interface {
 type gateway struct {
 	logger *slog.Logger
 
-	host, port string
-	tranports  []Transport
+	host, port   string
+	tranports    []Transport
+	interceptors []grpc.UnaryServerInterceptor
 }
 
 // ServerOption server option pattern
@@ -55,6 +56,13 @@ func WithTransports(transports []Transport) ServerOption {
 	}
 }
 
+// WithUnaryInterceptors server unary interceptors, run in order
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) ServerOption {
+	return func(g *gateway) {
+		g.interceptors = interceptors
+	}
+}
+
 // WithLogger server logger
 func WithLogger(logger *slog.Logger) ServerOption {
 	return func(g *gateway) {
@@ -70,7 +78,7 @@ func (g *gateway) StartAndStop(ctx context.Context) error {
 	}
 	defer func() { _ = lis.Close() }()
 
-	s := grpc.NewServer()
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(g.interceptors...))
 	for _, tr := range g.tranports {
 		s.RegisterService(tr.ServiceDesc, tr.Service)
 	}
@@ -23,8 +23,9 @@ type TestServerOption func(*TestServer)
 type TestServer struct {
 	logger *slog.Logger
 
-	lis        *bufconn.Listener
-	transports []Transport
+	lis          *bufconn.Listener
+	transports   []Transport
+	interceptors []grpc.UnaryServerInterceptor
 
 	gserver *grpc.Server
 }
@@ -56,6 +57,13 @@ func WithTestTransports(transports []Transport) TestServerOption {
 	}
 }
 
+// WithTestUnaryInterceptors test server interceptors run after the logger
+func WithTestUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) TestServerOption {
+	return func(ts *TestServer) {
+		ts.interceptors = interceptors
+	}
+}
+
 // WithTestLogger test server logger option
 func WithTestLogger(logger *slog.Logger) TestServerOption {
 	return func(ts *TestServer) {
@@ -73,7 +81,9 @@ func (ts *TestServer) Start(ctx context.Context) error {
 	ts.logger.DebugContext(ctx, "start gRPC bufnet server")
 
 	opts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(interceptors.LoggerUnary(ts.logger)),
+		grpc.ChainUnaryInterceptor(append(
+			[]grpc.UnaryServerInterceptor{interceptors.LoggerUnary(ts.logger)},
+			ts.interceptors...)...),
 	}
 
 	ts.gserver = grpc.NewServer(opts...)
@@ -93,15 +93,232 @@ func (*UnsealResponse) Descriptor() ([]byte, []int) {
 	return file_vault_v1_vault_service_proto_rawDescGZIP(), []int{1}
 }
 
+type WrapKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Plaintext     []byte                 `protobuf:"bytes,2,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WrapKeyRequest) Reset() {
+	*x = WrapKeyRequest{}
+	mi := &file_vault_v1_vault_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WrapKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WrapKeyRequest) ProtoMessage() {}
+
+func (x *WrapKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_vault_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WrapKeyRequest.ProtoReflect.Descriptor instead.
+func (*WrapKeyRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_vault_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *WrapKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *WrapKeyRequest) GetPlaintext() []byte {
+	if x != nil {
+		return x.Plaintext
+	}
+	return nil
+}
+
+type WrapKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Ciphertext    []byte                 `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WrapKeyResponse) Reset() {
+	*x = WrapKeyResponse{}
+	mi := &file_vault_v1_vault_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WrapKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WrapKeyResponse) ProtoMessage() {}
+
+func (x *WrapKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_vault_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WrapKeyResponse.ProtoReflect.Descriptor instead.
+func (*WrapKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_vault_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WrapKeyResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *WrapKeyResponse) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+type UnwrapKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Ciphertext    []byte                 `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnwrapKeyRequest) Reset() {
+	*x = UnwrapKeyRequest{}
+	mi := &file_vault_v1_vault_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnwrapKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnwrapKeyRequest) ProtoMessage() {}
+
+func (x *UnwrapKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_vault_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnwrapKeyRequest.ProtoReflect.Descriptor instead.
+func (*UnwrapKeyRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_vault_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UnwrapKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *UnwrapKeyRequest) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+type UnwrapKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plaintext     []byte                 `protobuf:"bytes,1,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnwrapKeyResponse) Reset() {
+	*x = UnwrapKeyResponse{}
+	mi := &file_vault_v1_vault_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnwrapKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnwrapKeyResponse) ProtoMessage() {}
+
+func (x *UnwrapKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_vault_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnwrapKeyResponse.ProtoReflect.Descriptor instead.
+func (*UnwrapKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_vault_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UnwrapKeyResponse) GetPlaintext() []byte {
+	if x != nil {
+		return x.Plaintext
+	}
+	return nil
+}
+
 var File_vault_v1_vault_service_proto protoreflect.FileDescriptor
 
 const file_vault_v1_vault_service_proto_rawDesc = "" +
 	"\n" +
 	"\x1cvault/v1/vault_service.proto\x12\bvault.v1\"\x0f\n" +
 	"\rUnsealRequest\"\x10\n" +
-	"\x0eUnsealResponse2M\n" +
+	"\x0eUnsealResponse\"E\n" +
+	"\x0eWrapKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1c\n" +
+	"\tplaintext\x18\x02 \x01(\fR\tplaintext\"H\n" +
+	"\x0fWrapKeyResponse\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x02 \x01(\fR\n" +
+	"ciphertext\"I\n" +
+	"\x10UnwrapKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x02 \x01(\fR\n" +
+	"ciphertext\"1\n" +
+	"\x11UnwrapKeyResponse\x12\x1c\n" +
+	"\tplaintext\x18\x01 \x01(\fR\tplaintext2\xd7\x01\n" +
 	"\fVaultService\x12=\n" +
-	"\x06Unseal\x12\x17.vault.v1.UnsealRequest\x1a\x18.vault.v1.UnsealResponse\"\x00B\"Z soft.structx.io/idp/api/vault/v1b\x06proto3"
+	"\x06Unseal\x12\x17.vault.v1.UnsealRequest\x1a\x18.vault.v1.UnsealResponse\"\x00\x12@\n" +
+	"\aWrapKey\x12\x18.vault.v1.WrapKeyRequest\x1a\x19.vault.v1.WrapKeyResponse\"\x00\x12F\n" +
+	"\tUnwrapKey\x12\x1a.vault.v1.UnwrapKeyRequest\x1a\x1b.vault.v1.UnwrapKeyResponse\"\x00B\"Z soft.structx.io/idp/api/vault/v1b\x06proto3"
 
 var (
 	file_vault_v1_vault_service_proto_rawDescOnce sync.Once
@@ -115,16 +332,24 @@ func file_vault_v1_vault_service_proto_rawDescGZIP() []byte {
 	return file_vault_v1_vault_service_proto_rawDescData
 }
 
-var file_vault_v1_vault_service_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_vault_v1_vault_service_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_vault_v1_vault_service_proto_goTypes = []any{
-	(*UnsealRequest)(nil),  // 0: vault.v1.UnsealRequest
-	(*UnsealResponse)(nil), // 1: vault.v1.UnsealResponse
+	(*UnsealRequest)(nil),     // 0: vault.v1.UnsealRequest
+	(*UnsealResponse)(nil),    // 1: vault.v1.UnsealResponse
+	(*WrapKeyRequest)(nil),    // 2: vault.v1.WrapKeyRequest
+	(*WrapKeyResponse)(nil),   // 3: vault.v1.WrapKeyResponse
+	(*UnwrapKeyRequest)(nil),  // 4: vault.v1.UnwrapKeyRequest
+	(*UnwrapKeyResponse)(nil), // 5: vault.v1.UnwrapKeyResponse
 }
 var file_vault_v1_vault_service_proto_depIdxs = []int32{
 	0, // 0: vault.v1.VaultService.Unseal:input_type -> vault.v1.UnsealRequest
-	1, // 1: vault.v1.VaultService.Unseal:output_type -> vault.v1.UnsealResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
+	2, // 1: vault.v1.VaultService.WrapKey:input_type -> vault.v1.WrapKeyRequest
+	4, // 2: vault.v1.VaultService.UnwrapKey:input_type -> vault.v1.UnwrapKeyRequest
+	1, // 3: vault.v1.VaultService.Unseal:output_type -> vault.v1.UnsealResponse
+	3, // 4: vault.v1.VaultService.WrapKey:output_type -> vault.v1.WrapKeyResponse
+	5, // 5: vault.v1.VaultService.UnwrapKey:output_type -> vault.v1.UnwrapKeyResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -141,7 +366,7 @@ func file_vault_v1_vault_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vault_v1_vault_service_proto_rawDesc), len(file_vault_v1_vault_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
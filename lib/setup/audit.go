@@ -0,0 +1,64 @@
+package setup
+
+import "time"
+
+// Audit config
+type Audit struct {
+	// BlockInterval time between produced blocks
+	BlockInterval time.Duration
+	// MaxBlockTxs maximum transactions per block
+	MaxBlockTxs int
+	// Network address prefix of audit senders
+	Network string
+	// WalletPath node keystore file, created when missing
+	WalletPath string
+	// WalletPasswordFile file holding the keystore password
+	WalletPasswordFile string
+
+	// Signer backend signing blocks and transactions, keystore, remote, pkcs11 or threshold
+	Signer string
+	// SignerAddr address of the remote signer service
+	SignerAddr string
+	// SignerPublicKey hex public key the remote signer service must hold
+	SignerPublicKey string
+	// SignerCAFile CA bundle verifying the remote signer service, system roots when empty
+	SignerCAFile string
+	// SignerCertFile client certificate presented to the remote signer service
+	SignerCertFile string
+	// SignerKeyFile private key of the client certificate
+	SignerKeyFile string
+	// PKCS11Module path of the PKCS#11 library
+	PKCS11Module string
+	// PKCS11Token label of the token holding the signing key
+	PKCS11Token string
+	// PKCS11PINFile file holding the token user pin
+	PKCS11PINFile string
+	// PKCS11Key label of the signing key pair
+	PKCS11Key string
+
+	// ThresholdAddr bind address of the threshold service, the
+	// keystore wallet authenticates this node to the others
+	ThresholdAddr string
+	// ThresholdParticipants comma separated hex public keys of
+	// the group, every participant lists them in the same order
+	ThresholdParticipants string
+	// ThresholdPeers comma separated index=addr threshold services of the other participants
+	ThresholdPeers string
+	// Threshold participants required to sign
+	Threshold int
+	// ThresholdKeySharePath group key share file, generated with the other participants when missing
+	ThresholdKeySharePath string
+
+	// NodeID raft server id of this node
+	NodeID string
+	// RaftAddr raft bind address, empty runs a single unreplicated node
+	RaftAddr string
+	// RaftDir raft log and snapshot directory
+	RaftDir string
+	// Peers comma separated id=addr raft servers the cluster is bootstrapped with
+	Peers string
+
+	// Recorders comma separated callers allowed to record decisions,
+	// authenticated by a token signed with the auth signing key
+	Recorders string
+}
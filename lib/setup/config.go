@@ -1,10 +1,33 @@
 package setup
 
+import "time"
+
 const (
 	defaultPort = "8080"
 	defaultHost = "127.0.0.1"
 
-	defaultKeyValueDir = "data"
+	defaultKeyValueDir         = "data"
+	defaultKeyValueSync        = true
+	defaultKeyValueBlockSize   = 4096
+	defaultKeyValueCompression = "none"
+	defaultKeyValueKeyID       = "default"
+	defaultKeyValueRetention   = 16
+	defaultKeyValueCompaction  = 8
+
+	defaultAuditBlockInterval = time.Second * 5
+	defaultAuditMaxBlockTxs   = 500
+	defaultAuditRaftDir       = "raft"
+	defaultAuditNetwork       = "tbd"
+	defaultAuditWalletPath    = "wallet.json"
+	defaultAuditSigner        = "keystore"
+	defaultAuditPKCS11Key     = "audit"
+	defaultAuditThreshold     = 2
+	defaultAuditKeySharePath  = "keyshare.json"
+	defaultAuditRecorders     = defaultIdentitiesServiceName
+
+	defaultIdentitiesPolicyReloadInterval = time.Second * 30
+	defaultIdentitiesEventRelayInterval   = time.Second * 5
+	defaultIdentitiesServiceName          = "identities"
 
 	defaultLogLevel = "DEBUG"
 
@@ -16,8 +39,10 @@ const (
 
 // Config service configuration
 type Config struct {
+	Audit      Audit
 	Auth       Auth
 	Gateway    Gateway
+	Identities Identities
 	KeyValue   KeyValue
 	Logger     Logger
 	Nameserver Nameserver
@@ -26,6 +51,34 @@ type Config struct {
 // UnmarshalConfig read service config from env variables
 func UnmarshalConfig() *Config {
 	return &Config{
+		Audit: Audit{
+			BlockInterval:      envLookupDuration("AUDIT_BLOCK_INTERVAL", defaultAuditBlockInterval),
+			MaxBlockTxs:        envLookupInt("AUDIT_MAX_BLOCK_TXS", defaultAuditMaxBlockTxs),
+			Network:            envLookup("AUDIT_NETWORK", defaultAuditNetwork),
+			WalletPath:         envLookup("AUDIT_WALLET_PATH", defaultAuditWalletPath),
+			WalletPasswordFile: envLookup("AUDIT_WALLET_PASSWORD_FILE", ""),
+			Signer:             envLookup("AUDIT_SIGNER", defaultAuditSigner),
+			SignerAddr:         envLookup("AUDIT_SIGNER_ADDR", ""),
+			SignerPublicKey:    envLookup("AUDIT_SIGNER_PUBLIC_KEY", ""),
+			SignerCAFile:       envLookup("AUDIT_SIGNER_CA_FILE", ""),
+			SignerCertFile:     envLookup("AUDIT_SIGNER_CERT_FILE", ""),
+			SignerKeyFile:      envLookup("AUDIT_SIGNER_KEY_FILE", ""),
+			PKCS11Module:       envLookup("AUDIT_PKCS11_MODULE", ""),
+			PKCS11Token:        envLookup("AUDIT_PKCS11_TOKEN", ""),
+			PKCS11PINFile:      envLookup("AUDIT_PKCS11_PIN_FILE", ""),
+			PKCS11Key:          envLookup("AUDIT_PKCS11_KEY", defaultAuditPKCS11Key),
+			NodeID:             envLookup("AUDIT_NODE_ID", ""),
+			RaftAddr:           envLookup("AUDIT_RAFT_ADDR", ""),
+			RaftDir:            envLookup("AUDIT_RAFT_DIR", defaultAuditRaftDir),
+			Peers:              envLookup("AUDIT_PEERS", ""),
+			Recorders:          envLookup("AUDIT_RECORDERS", defaultAuditRecorders),
+
+			ThresholdAddr:         envLookup("AUDIT_THRESHOLD_ADDR", ""),
+			ThresholdParticipants: envLookup("AUDIT_THRESHOLD_PARTICIPANTS", ""),
+			ThresholdPeers:        envLookup("AUDIT_THRESHOLD_PEERS", ""),
+			Threshold:             envLookupInt("AUDIT_THRESHOLD", defaultAuditThreshold),
+			ThresholdKeySharePath: envLookup("AUDIT_THRESHOLD_KEY_SHARE_PATH", defaultAuditKeySharePath),
+		},
 		Auth: Auth{
 			SigningKey: envLookup("AUTH_SIGNING_KEY", defaultSigningKey),
 		},
@@ -33,8 +86,26 @@ func UnmarshalConfig() *Config {
 			Host: envLookup("GW_HOST", defaultHost),
 			Port: envLookup("GW_PORT", defaultPort),
 		},
+		Identities: Identities{
+			PolicyFile:           envLookup("IDENTITIES_POLICY_FILE", ""),
+			PolicyReloadInterval: envLookupDuration("IDENTITIES_POLICY_RELOAD_INTERVAL", defaultIdentitiesPolicyReloadInterval),
+			AuditAddr:            envLookup("IDENTITIES_AUDIT_ADDR", ""),
+			ServiceName:          envLookup("IDENTITIES_SERVICE_NAME", defaultIdentitiesServiceName),
+			EventRelayInterval:   envLookupDuration("IDENTITIES_EVENT_RELAY_INTERVAL", defaultIdentitiesEventRelayInterval),
+		},
 		KeyValue: KeyValue{
-			Dir: envLookup("KV_DIR", defaultKeyValueDir),
+			Dir:              envLookup("KV_DIR", defaultKeyValueDir),
+			Sync:             envLookupBool("KV_SYNC", defaultKeyValueSync),
+			BlockSize:        envLookupInt("KV_BLOCK_SIZE", defaultKeyValueBlockSize),
+			Compression:      envLookup("KV_COMPRESSION", defaultKeyValueCompression),
+			Retention:        envLookupInt("KV_RETENTION", defaultKeyValueRetention),
+			CompactionTables: envLookupInt("KV_COMPACTION_TABLES", defaultKeyValueCompaction),
+			ServerAddr:       envLookup("KV_SERVER_ADDR", ""),
+
+			EncryptionKeyID:        envLookup("KV_ENCRYPTION_KEY_ID", defaultKeyValueKeyID),
+			EncryptionKey:          envLookup("KV_ENCRYPTION_KEY", ""),
+			PreviousEncryptionKeys: envLookup("KV_PREVIOUS_ENCRYPTION_KEYS", ""),
+			VaultAddr:              envLookup("KV_VAULT_ADDR", ""),
 		},
 		Logger: Logger{
 			Level: envLookup("LOG_LEVEL", defaultLogLevel),
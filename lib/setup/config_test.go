@@ -16,8 +16,38 @@ func TestUnmarshalConfig(t *testing.T) {
 
 	assert.Equal(t, defaultSigningKey, cfg.Auth.SigningKey)
 
+	assert.Equal(t, defaultAuditBlockInterval, cfg.Audit.BlockInterval)
+	assert.Equal(t, defaultAuditMaxBlockTxs, cfg.Audit.MaxBlockTxs)
+	assert.Equal(t, defaultAuditNetwork, cfg.Audit.Network)
+	assert.Equal(t, defaultAuditWalletPath, cfg.Audit.WalletPath)
+	assert.Empty(t, cfg.Audit.WalletPasswordFile)
+	assert.Equal(t, defaultAuditSigner, cfg.Audit.Signer)
+	assert.Empty(t, cfg.Audit.SignerPublicKey)
+	assert.Empty(t, cfg.Audit.SignerCAFile)
+	assert.Equal(t, defaultAuditPKCS11Key, cfg.Audit.PKCS11Key)
+	assert.Empty(t, cfg.Audit.ThresholdAddr)
+	assert.Equal(t, defaultAuditThreshold, cfg.Audit.Threshold)
+	assert.Equal(t, defaultAuditKeySharePath, cfg.Audit.ThresholdKeySharePath)
+	assert.Empty(t, cfg.Audit.RaftAddr)
+	assert.Equal(t, defaultAuditRaftDir, cfg.Audit.RaftDir)
+	assert.Equal(t, defaultAuditRecorders, cfg.Audit.Recorders)
+
+	assert.Empty(t, cfg.Identities.PolicyFile)
+	assert.Equal(t, defaultIdentitiesPolicyReloadInterval, cfg.Identities.PolicyReloadInterval)
+	assert.Empty(t, cfg.Identities.AuditAddr)
+	assert.Equal(t, defaultIdentitiesServiceName, cfg.Identities.ServiceName)
+	assert.Equal(t, defaultIdentitiesEventRelayInterval, cfg.Identities.EventRelayInterval)
+
 	assert.Equal(t, defaultNameserver1, cfg.Nameserver.NS1)
 	assert.Equal(t, defaultNameserver2, cfg.Nameserver.NS2)
 
 	assert.Equal(t, defaultKeyValueDir, cfg.KeyValue.Dir)
+	assert.Equal(t, defaultKeyValueSync, cfg.KeyValue.Sync)
+	assert.Equal(t, defaultKeyValueBlockSize, cfg.KeyValue.BlockSize)
+	assert.Equal(t, defaultKeyValueCompression, cfg.KeyValue.Compression)
+	assert.Equal(t, defaultKeyValueRetention, cfg.KeyValue.Retention)
+	assert.Equal(t, defaultKeyValueCompaction, cfg.KeyValue.CompactionTables)
+	assert.Empty(t, cfg.KeyValue.ServerAddr)
+	assert.Equal(t, defaultKeyValueKeyID, cfg.KeyValue.EncryptionKeyID)
+	assert.Empty(t, cfg.KeyValue.EncryptionKey)
 }
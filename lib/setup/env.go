@@ -2,6 +2,8 @@ package setup
 
 import (
 	"os"
+	"strconv"
+	"time"
 )
 
 func envLookup(key, defaultValue string) string {
@@ -11,3 +13,27 @@ func envLookup(key, defaultValue string) string {
 	}
 	return v
 }
+
+func envLookupBool(key string, defaultValue bool) bool {
+	b, err := strconv.ParseBool(envLookup(key, strconv.FormatBool(defaultValue)))
+	if err != nil {
+		return defaultValue
+	}
+	return b
+}
+
+func envLookupInt(key string, defaultValue int) int {
+	i, err := strconv.Atoi(envLookup(key, strconv.Itoa(defaultValue)))
+	if err != nil {
+		return defaultValue
+	}
+	return i
+}
+
+func envLookupDuration(key string, defaultValue time.Duration) time.Duration {
+	d, err := time.ParseDuration(envLookup(key, defaultValue.String()))
+	if err != nil {
+		return defaultValue
+	}
+	return d
+}
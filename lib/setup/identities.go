@@ -0,0 +1,18 @@
+package setup
+
+import "time"
+
+// Identities config
+type Identities struct {
+	// PolicyFile access policy, the built in policy when empty
+	PolicyFile           string
+	PolicyReloadInterval time.Duration
+	// AuditAddr audit service recording every access decision,
+	// decisions are not recorded when empty
+	AuditAddr string
+	// ServiceName caller the service authenticates to the audit service as
+	ServiceName string
+	// EventRelayInterval longest wait before events failing
+	// to publish are retried
+	EventRelayInterval time.Duration
+}
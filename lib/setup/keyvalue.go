@@ -3,4 +3,27 @@ package setup
 // KeyValue config
 type KeyValue struct {
 	Dir string
+	// Sync fsync the write ahead log after every write
+	Sync bool
+	// BlockSize sorted string table block size in bytes
+	BlockSize int
+	// Compression sorted string table block compression
+	Compression string
+	// Retention flushed log segments kept for change subscribers
+	Retention int
+	// CompactionTables sorted string tables triggering a
+	// background compaction, zero disables it
+	CompactionTables int
+	// ServerAddr keyvalue service publishing and following change events
+	ServerAddr string
+	// EncryptionKeyID key encryption key id, encryption is
+	// disabled unless a key or vault address is set
+	EncryptionKeyID string
+	// EncryptionKey base64 encoded 32 byte key encryption key
+	EncryptionKey string
+	// PreviousEncryptionKeys comma separated id:base64 key
+	// encryption keys retired by a rotation
+	PreviousEncryptionKeys string
+	// VaultAddr vault service wrapping data keys instead of a local key
+	VaultAddr string
 }
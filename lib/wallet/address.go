@@ -0,0 +1,154 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/sha3"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// Network address prefix
+type Network string
+
+const (
+	// Mainnet production network prefix
+	Mainnet Network = "tbd"
+	// Testnet test network prefix
+	Testnet Network = "tbdt"
+
+	// AddressHashSize bytes of the public key hash
+	AddressHashSize = 20
+
+	checksumSize = 4
+	// separator between network prefix and payload,
+	// never part of the payload alphabet
+	separator = "1"
+)
+
+var (
+	// ErrInvalidAddress address is not well formed
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrAddressChecksum address checksum does not match
+	ErrAddressChecksum = errors.New("address checksum mismatch")
+	// ErrNetworkMismatch address belongs to another network
+	ErrNetworkMismatch = errors.New("address network mismatch")
+)
+
+// payload encoding, lower case base32 without padding
+var addressEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// Address public key hash with a network prefix
+//
+// encoded as network || "1" || base32(hash || checksum) where
+// hash is the truncated sha3-256 of the public key and the
+// checksum covers both network and hash
+type Address struct {
+	Network Network
+	Hash    [AddressHashSize]byte
+}
+
+// Validate network prefix
+func (n Network) Validate() error {
+	if n == "" {
+		return fmt.Errorf("%w: empty network", ErrInvalidAddress)
+	}
+	for _, r := range n {
+		if r < 'a' || r > 'z' {
+			return fmt.Errorf("%w: network %q must be lower case letters", ErrInvalidAddress, n)
+		}
+	}
+	return nil
+}
+
+// NewAddress address of public key P on network
+func NewAddress(network Network, P kyber.Point) (Address, error) {
+	err := network.Validate()
+	if err != nil {
+		return Address{}, err
+	}
+
+	pb, err := P.MarshalBinary()
+	if err != nil {
+		return Address{}, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	a := Address{Network: network}
+	h := sha3.Sum256(pb)
+	copy(a.Hash[:], h[:AddressHashSize])
+
+	return a, nil
+}
+
+// ParseAddress decode and verify the checksum of address s
+func ParseAddress(s string) (Address, error) {
+	i := strings.LastIndex(s, separator)
+	if i < 0 {
+		return Address{}, fmt.Errorf("%w: missing separator", ErrInvalidAddress)
+	}
+
+	a := Address{Network: Network(s[:i])}
+	err := a.Network.Validate()
+	if err != nil {
+		return Address{}, err
+	}
+
+	payload, err := addressEncoding.DecodeString(s[i+1:])
+	if err != nil {
+		return Address{}, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+	if len(payload) != AddressHashSize+checksumSize {
+		return Address{}, fmt.Errorf("%w: payload length %d", ErrInvalidAddress, len(payload))
+	}
+
+	copy(a.Hash[:], payload)
+	if !bytes.Equal(a.checksum(), payload[AddressHashSize:]) {
+		return Address{}, ErrAddressChecksum
+	}
+
+	// unused trailing bits must be zero so every
+	// address has exactly one encoding
+	if a.String() != s {
+		return Address{}, fmt.Errorf("%w: non canonical encoding", ErrInvalidAddress)
+	}
+
+	return a, nil
+}
+
+// ValidateAddress check s is a well formed address on network
+func ValidateAddress(s string, network Network) error {
+	a, err := ParseAddress(s)
+	if err != nil {
+		return err
+	}
+	if a.Network != network {
+		return fmt.Errorf("%w: %q is not on %q", ErrNetworkMismatch, a.Network, network)
+	}
+	return nil
+}
+
+// String encoded address
+func (a Address) String() string {
+	payload := append(a.Hash[:], a.checksum()...)
+	return string(a.Network) + separator + addressEncoding.EncodeToString(payload)
+}
+
+// Matches address is the hash of public key P
+func (a Address) Matches(P kyber.Point) bool {
+	b, err := NewAddress(a.Network, P)
+	if err != nil {
+		return false
+	}
+	return a == b
+}
+
+func (a Address) checksum() []byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(a.Network))
+	_, _ = h.Write([]byte(separator))
+	_, _ = h.Write(a.Hash[:])
+	return h.Sum(nil)[:checksumSize]
+}
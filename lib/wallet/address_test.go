@@ -0,0 +1,93 @@
+package wallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+)
+
+func TestNewV1Random(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+
+	a, b := NewV1(suite), NewV1(suite)
+	if a.P.Equal(b.P) {
+		t.Fatal("wallets share the same key")
+	}
+}
+
+func TestAddress(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	w := NewV1(suite)
+
+	addr, err := w.Addr(Mainnet)
+	if err != nil {
+		t.Fatalf("failed to create address: %v", err)
+	}
+	if !strings.HasPrefix(addr, string(Mainnet)+separator) {
+		t.Fatalf("unexpected address prefix %s", addr)
+	}
+
+	t.Run("parse", func(t *testing.T) {
+		a, err := ParseAddress(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Network != Mainnet || a.String() != addr {
+			t.Fatalf("unexpected address %s expected %s", a, addr)
+		}
+		if !a.Matches(w.P) {
+			t.Fatal("address does not match its public key")
+		}
+		if a.Matches(NewV1(suite).P) {
+			t.Fatal("address matches another public key")
+		}
+	})
+	t.Run("network", func(t *testing.T) {
+		if err := ValidateAddress(addr, Mainnet); err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidateAddress(addr, Testnet); !errors.Is(err, ErrNetworkMismatch) {
+			t.Fatalf("unexpected error %v expected %v", err, ErrNetworkMismatch)
+		}
+
+		// swapping the prefix breaks the checksum
+		swapped := string(Testnet) + strings.TrimPrefix(addr, string(Mainnet))
+		if _, err := ParseAddress(swapped); !errors.Is(err, ErrAddressChecksum) {
+			t.Fatalf("unexpected error %v expected %v", err, ErrAddressChecksum)
+		}
+	})
+	t.Run("checksum", func(t *testing.T) {
+		// the last character holds padding bits, change the hash
+		b := []byte(addr)
+		i := len(Mainnet) + len(separator)
+		if b[i] == 'a' {
+			b[i] = 'b'
+		} else {
+			b[i] = 'a'
+		}
+		if _, err := ParseAddress(string(b)); !errors.Is(err, ErrAddressChecksum) {
+			t.Fatalf("unexpected error %v expected %v", err, ErrAddressChecksum)
+		}
+	})
+	t.Run("canonical", func(t *testing.T) {
+		// the last character carries two payload bits
+		// followed by three zero padding bits
+		b := []byte(addr)
+		b[len(b)-1]++
+		if _, err := ParseAddress(string(b)); !errors.Is(err, ErrInvalidAddress) {
+			t.Fatalf("unexpected error %v expected %v", err, ErrInvalidAddress)
+		}
+	})
+	t.Run("malformed", func(t *testing.T) {
+		for _, s := range []string{"", "audit", "tbd1", "TBD1" + addr[4:], "tbd1!!", addr[:len(addr)-2]} {
+			if _, err := ParseAddress(s); !errors.Is(err, ErrInvalidAddress) && !errors.Is(err, ErrAddressChecksum) {
+				t.Fatalf("unexpected error %v for %q", err, s)
+			}
+		}
+		if _, err := ParseAddress("audit"); !errors.Is(err, ErrInvalidAddress) {
+			t.Fatalf("unexpected error %v expected %v", err, ErrInvalidAddress)
+		}
+	})
+}
@@ -2,10 +2,32 @@ module github.com/trevatk/tbd/lib/wallet
 
 go 1.24.4
 
-require go.dedis.ch/kyber/v4 v4.0.0-pre2
+replace github.com/trevatk/tbd/lib/protocol => ../protocol
 
 require (
+	buf.build/go/protovalidate v0.13.1
+	github.com/miekg/pkcs11 v1.1.2
+	github.com/trevatk/tbd/lib/protocol v0.0.0-00010101000000-000000000000
+	github.com/tyler-smith/go-bip39 v1.1.0
+	go.dedis.ch/kyber/v4 v4.0.0-pre2
+	golang.org/x/crypto v0.36.0
+	google.golang.org/grpc v1.73.0
+)
+
+require (
+	buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go v1.36.6-20250625184727-c923a0c2a132.1 // indirect
+	cel.dev/expr v0.23.1 // indirect
+	github.com/antlr4-go/antlr/v4 v4.13.0 // indirect
+	github.com/golang-jwt/jwt/v5 v5.2.2 // indirect
+	github.com/google/cel-go v0.25.0 // indirect
+	github.com/stoewer/go-strcase v1.3.0 // indirect
 	go.dedis.ch/fixbuf v1.0.3 // indirect
-	golang.org/x/crypto v0.36.0 // indirect
+	go.dedis.ch/protobuf v1.0.7 // indirect
+	golang.org/x/exp v0.0.0-20240325151524-a685a6edb6d8 // indirect
+	golang.org/x/net v0.38.0 // indirect
 	golang.org/x/sys v0.32.0 // indirect
+	golang.org/x/text v0.23.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20250324211829-b45e905df463 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250324211829-b45e905df463 // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
 )
@@ -0,0 +1,189 @@
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Hardened offset of hardened child indices
+	Hardened uint32 = 1 << 31
+
+	// Purpose bip-44 purpose of derivation paths
+	Purpose uint32 = 44
+	// CoinType coin type of derivation paths, ascii "tbd"
+	CoinType uint32 = 0x746264
+
+	// masterSecret slip-0010 hmac key of the ed25519 master key
+	masterSecret = "ed25519 seed"
+
+	minSeedSize = 16
+	maxSeedSize = 64
+)
+
+var (
+	// ErrInvalidPath derivation path is not well formed
+	ErrInvalidPath = errors.New("invalid derivation path")
+	// ErrNonHardened ed25519 only derives hardened children
+	ErrNonHardened = errors.New("non hardened derivation")
+	// ErrInvalidSeed seed length is out of range
+	ErrInvalidSeed = errors.New("invalid seed")
+)
+
+// Path hierarchical derivation path, every index is hardened
+type Path []uint32
+
+// ParsePath decode path in the m/44'/0'/0' notation
+func ParsePath(s string) (Path, error) {
+	parts := strings.Split(s, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("%w: %q must start with m", ErrInvalidPath, s)
+	}
+
+	p := make(Path, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h")
+		if !hardened {
+			return nil, fmt.Errorf("%w: %q in %q", ErrNonHardened, part, s)
+		}
+
+		i, err := strconv.ParseUint(part[:len(part)-1], 10, 32)
+		if err != nil || uint32(i) >= Hardened {
+			return nil, fmt.Errorf("%w: index %q in %q", ErrInvalidPath, part, s)
+		}
+		p = append(p, uint32(i)+Hardened)
+	}
+
+	return p, nil
+}
+
+// String path in the m/44'/0'/0' notation
+func (p Path) String() string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, i := range p {
+		sb.WriteString("/")
+		sb.WriteString(strconv.FormatUint(uint64(i&^Hardened), 10))
+		if i >= Hardened {
+			sb.WriteString("'")
+		}
+	}
+	return sb.String()
+}
+
+// ServicePath path of the index-th key of service in realm
+//
+// m / purpose' / coin type' / realm' / service' / index'
+// where realm and service are hashed into hardened indices
+func ServicePath(realm, service string, index uint32) Path {
+	return Path{
+		Purpose + Hardened,
+		CoinType + Hardened,
+		nameIndex(realm) + Hardened,
+		nameIndex(service) + Hardened,
+		index | Hardened,
+	}
+}
+
+// nameIndex 31 bit index of name
+func nameIndex(name string) uint32 {
+	h := sha256.Sum256([]byte(name))
+	return binary.BigEndian.Uint32(h[:4]) &^ Hardened
+}
+
+// HDKey slip-0010 ed25519 extended private key
+type HDKey struct {
+	key       [32]byte
+	chainCode [32]byte
+	path      Path
+}
+
+// MasterKey master extended key of seed
+func MasterKey(seed []byte) (*HDKey, error) {
+	if len(seed) < minSeedSize || len(seed) > maxSeedSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidSeed, len(seed))
+	}
+
+	mac := hmac.New(sha512.New, []byte(masterSecret))
+	_, _ = mac.Write(seed)
+
+	return newHDKey(mac.Sum(nil), Path{}), nil
+}
+
+// MasterKeyFromMnemonic master extended key of mnemonic and passphrase
+func MasterKeyFromMnemonic(mnemonic, passphrase string) (*HDKey, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return MasterKey(seed)
+}
+
+func newHDKey(i []byte, path Path) *HDKey {
+	k := &HDKey{path: path}
+	copy(k.key[:], i[:32])
+	copy(k.chainCode[:], i[32:])
+	return k
+}
+
+// Path derivation path of key
+func (k *HDKey) Path() Path {
+	return append(Path{}, k.path...)
+}
+
+// Child hardened child key at index
+func (k *HDKey) Child(index uint32) (*HDKey, error) {
+	if index < Hardened {
+		return nil, fmt.Errorf("%w: index %d", ErrNonHardened, index)
+	}
+
+	data := make([]byte, 0, 1+32+4)
+	data = append(data, 0)
+	data = append(data, k.key[:]...)
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.chainCode[:])
+	_, _ = mac.Write(data)
+
+	path := append(k.Path(), index)
+	return newHDKey(mac.Sum(nil), path), nil
+}
+
+// Derive descendant key at path relative to k
+func (k *HDKey) Derive(path Path) (*HDKey, error) {
+	var err error
+	child := k
+	for _, i := range path {
+		child, err = child.Child(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return child, nil
+}
+
+// Wallet wallet of the key
+func (k *HDKey) Wallet(suite Suite) Wallet {
+	return fromEd25519Seed(suite, k.key[:])
+}
+
+// fromEd25519Seed wallet with the scalar an ed25519
+// private key seed expands to
+func fromEd25519Seed(suite Suite, seed []byte) Wallet {
+	h := sha512.Sum512(seed)
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+
+	x := suite.Scalar().SetBytes(h[:32])
+	return Wallet{
+		p: x,
+		P: suite.Point().Mul(x, nil),
+	}
+}
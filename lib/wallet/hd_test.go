@@ -0,0 +1,167 @@
+package wallet
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+)
+
+func TestHDKeyVectors(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+
+	// slip-0010 ed25519 test vector 1
+	seed := decodeHex(t, "000102030405060708090a0b0c0d0e0f")
+	vectors := []struct {
+		path, chainCode, key, public string
+	}{
+		{
+			path:      "m",
+			chainCode: "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+			key:       "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+			public:    "a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed",
+		},
+		{
+			path:      "m/0'",
+			chainCode: "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+			key:       "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+			public:    "8c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c",
+		},
+		{
+			path:      "m/0'/1'",
+			chainCode: "a320425f77d1b5c2505a6b1b27382b37368ee640e3557c315416801243552f14",
+			key:       "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2",
+			public:    "1932a5270f335bed617d5b935c80aedb1a35bd9fc1e31acafd5372c30f5c1187",
+		},
+	}
+
+	master, err := MasterKey(seed)
+	if err != nil {
+		t.Fatalf("failed to create master key: %v", err)
+	}
+
+	for _, v := range vectors {
+		t.Run(v.path, func(t *testing.T) {
+			path, err := ParsePath(v.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path.String() != v.path {
+				t.Fatalf("unexpected path %s expected %s", path, v.path)
+			}
+
+			k, err := master.Derive(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(k.chainCode[:]) != v.chainCode {
+				t.Fatalf("unexpected chain code %x expected %s", k.chainCode, v.chainCode)
+			}
+			if hex.EncodeToString(k.key[:]) != v.key {
+				t.Fatalf("unexpected key %x expected %s", k.key, v.key)
+			}
+
+			Pb, err := k.Wallet(suite).P.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal public key: %v", err)
+			}
+			if hex.EncodeToString(Pb) != v.public {
+				t.Fatalf("unexpected public key %x expected %s", Pb, v.public)
+			}
+
+			stdlib := ed25519.NewKeyFromSeed(k.key[:]).Public().(ed25519.PublicKey)
+			if hex.EncodeToString(stdlib) != v.public {
+				t.Fatalf("crypto/ed25519 public key %x expected %s", []byte(stdlib), v.public)
+			}
+		})
+	}
+}
+
+func TestServicePath(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+
+	mnemonic, err := NewMnemonic(DefaultMnemonicBits)
+	if err != nil {
+		t.Fatalf("failed to create mnemonic: %v", err)
+	}
+	master, err := MasterKeyFromMnemonic(mnemonic, "")
+	if err != nil {
+		t.Fatalf("failed to create master key: %v", err)
+	}
+
+	derive := func(realm, service string, index uint32) Wallet {
+		k, err := master.Derive(ServicePath(realm, service, index))
+		if err != nil {
+			t.Fatalf("failed to derive key: %v", err)
+		}
+		return k.Wallet(suite)
+	}
+
+	w := derive("master-realm", "audit", 0)
+
+	t.Run("stable", func(t *testing.T) {
+		recovered, err := MasterKeyFromMnemonic(mnemonic, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, err := recovered.Derive(ServicePath("master-realm", "audit", 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !k.Wallet(suite).P.Equal(w.P) {
+			t.Fatal("recovered key does not match")
+		}
+	})
+	t.Run("scoped", func(t *testing.T) {
+		for _, other := range []Wallet{
+			derive("other-realm", "audit", 0),
+			derive("master-realm", "identities", 0),
+			derive("master-realm", "audit", 1),
+		} {
+			if other.P.Equal(w.P) {
+				t.Fatal("keys of different scopes are equal")
+			}
+		}
+	})
+	t.Run("sign", func(t *testing.T) {
+		msg := []byte("message")
+		sig, err := w.Sign(suite, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = Verify(suite, w.P, msg, sig); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestParsePath(t *testing.T) {
+	p, err := ParsePath("m/44'/0h/1'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.String() != "m/44'/0'/1'" {
+		t.Fatalf("unexpected path %s", p)
+	}
+
+	for s, expected := range map[string]error{
+		"44'/0'":        ErrInvalidPath,
+		"m/44'/0":       ErrNonHardened,
+		"m/x'":          ErrInvalidPath,
+		"m/2147483648'": ErrInvalidPath,
+	} {
+		if _, err := ParsePath(s); !errors.Is(err, expected) {
+			t.Fatalf("unexpected error %v expected %v for %q", err, expected, s)
+		}
+	}
+
+	master, err := MasterKey(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = master.Child(0); !errors.Is(err, ErrNonHardened) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrNonHardened)
+	}
+}
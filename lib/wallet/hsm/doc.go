@@ -0,0 +1,15 @@
+// Package hsm signer backed by a PKCS#11 token
+//
+// the ed25519 private key is generated inside the token and
+// marked sensitive and non extractable, signing happens in the
+// token so the key never enters process memory
+//
+// the backend requires cgo and is built with the pkcs11 tag
+//
+//	go build -tags pkcs11
+//
+// tests run against SoftHSM when SOFTHSM2_MODULE points to
+// the SoftHSM library
+//
+//	SOFTHSM2_MODULE=/usr/lib/softhsm/libsofthsm2.so go test -tags pkcs11 ./hsm/...
+package hsm
@@ -0,0 +1,336 @@
+//go:build pkcs11
+
+package hsm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+	"go.dedis.ch/kyber/v4"
+
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+// PKCS#11 v3.0 identifiers missing from the v2.40 headers
+const (
+	ckkECEdwards           = 0x00000040
+	ckmECEdwardsKeyPairGen = 0x00001055
+	ckmEdDSA               = 0x00001057
+)
+
+var (
+	// ErrTokenNotFound no token with the configured label
+	ErrTokenNotFound = errors.New("token not found")
+	// ErrKeyNotFound no key pair with the configured label
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrKeyExists key pair with the configured label already exists
+	ErrKeyExists = errors.New("key already exists")
+
+	// ed25519 object identifier 1.3.101.112 DER encoded
+	ed25519Params = []byte{0x06, 0x03, 0x2b, 0x65, 0x70}
+	// probe signed on open to check the key pair
+	probe = []byte("hsm signer probe")
+)
+
+// Config token and key of the signer
+type Config struct {
+	// Module path of the PKCS#11 library
+	Module string
+	// TokenLabel label of the token holding the key
+	TokenLabel string
+	// PIN user pin of the token
+	PIN string
+	// KeyLabel label of the key pair
+	KeyLabel string
+}
+
+// Signer PKCS#11 signer
+//
+// close a signer to log out and finalize the module, both only
+// when the signer was the one to log in and initialize it
+type Signer struct {
+	suite wallet.Suite
+	ctx   *pkcs11.Ctx
+	// initialized and loggedIn state owned by this signer
+	initialized bool
+	loggedIn    bool
+
+	// mu serialises use of the session
+	mu      sync.Mutex
+	session pkcs11.SessionHandle
+	private pkcs11.ObjectHandle
+	public  kyber.Point
+}
+
+// New return new signer of an existing key pair
+func New(suite wallet.Suite, cfg Config) (*Signer, error) {
+	s, err := open(suite, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.load(cfg.KeyLabel)
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Generate generate a key pair in the token and return its signer
+func Generate(suite wallet.Suite, cfg Config) (*Signer, error) {
+	s, err := open(suite, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.generate(cfg.KeyLabel)
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+
+	err = s.load(cfg.KeyLabel)
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// open load the module and log in to the token
+func open(suite wallet.Suite, cfg Config) (*Signer, error) {
+	ctx := pkcs11.New(cfg.Module)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load module %s", cfg.Module)
+	}
+
+	s := &Signer{
+		suite: suite,
+		ctx:   ctx,
+	}
+
+	err := ctx.Initialize()
+	if err == nil {
+		s.initialized = true
+	} else if !errors.Is(err, pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED)) {
+		ctx.Destroy()
+		return nil, fmt.Errorf("failed to initialize module: %w", err)
+	}
+
+	slot, err := s.findSlot(cfg.TokenLabel)
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+
+	s.session, err = ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		_ = s.Close()
+		return nil, fmt.Errorf("failed to open session: %w", err)
+	}
+
+	err = ctx.Login(s.session, pkcs11.CKU_USER, cfg.PIN)
+	if err == nil {
+		s.loggedIn = true
+	} else if !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+		_ = s.Close()
+		return nil, fmt.Errorf("failed to log in: %w", err)
+	}
+
+	return s, nil
+}
+
+// Public public key of the token key pair
+func (s *Signer) Public() kyber.Point {
+	return s.public
+}
+
+// Sign ed25519 signature of message computed by the token
+func (s *Signer) Sign(message []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.ctx.SignInit(s.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(ckmEdDSA, nil)}, s.private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize signing: %w", err)
+	}
+
+	sig, err := s.ctx.Sign(s.session, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	return sig, nil
+}
+
+// Close log out, close the session and finalize the module
+func (s *Signer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// login state is shared by every session of the token
+	if s.loggedIn {
+		_ = s.ctx.Logout(s.session)
+		s.loggedIn = false
+	}
+	if s.session != 0 {
+		_ = s.ctx.CloseSession(s.session)
+		s.session = 0
+	}
+
+	var err error
+	if s.initialized {
+		err = s.ctx.Finalize()
+		s.initialized = false
+	}
+	s.ctx.Destroy()
+	if err != nil {
+		return fmt.Errorf("failed to finalize module: %w", err)
+	}
+
+	return nil
+}
+
+// findSlot slot of the token labelled label
+func (s *Signer) findSlot(label string) (uint, error) {
+	slots, err := s.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list slots: %w", err)
+	}
+
+	for _, slot := range slots {
+		info, err := s.ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get token info: %w", err)
+		}
+		// labels are padded with spaces to 32 bytes
+		if strings.TrimRight(info.Label, " \x00") == label {
+			return slot, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrTokenNotFound, label)
+}
+
+// load find the key pair labelled label and check it signs
+func (s *Signer) load(label string) error {
+	priv, err := s.findKey(pkcs11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return err
+	}
+	pub, err := s.findKey(pkcs11.CKO_PUBLIC_KEY, label)
+	if err != nil {
+		return err
+	}
+
+	attrs, err := s.ctx.GetAttributeValue(s.session, pub, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get public key: %w", err)
+	}
+
+	s.public = s.suite.Point()
+	err = s.public.UnmarshalBinary(ecPoint(attrs[0].Value))
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal public key: %w", err)
+	}
+	s.private = priv
+
+	// a public key object not matching the private
+	// key would sign with an unverifiable key
+	sig, err := s.Sign(probe)
+	if err != nil {
+		return err
+	}
+	return wallet.Verify(s.suite, s.public, probe, sig)
+}
+
+// findKey single ed25519 key object of class labelled label
+func (s *Signer) findKey(class uint, label string) (pkcs11.ObjectHandle, error) {
+	objs, err := s.findObjects([]*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, ckkECEdwards),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	switch len(objs) {
+	case 0:
+		return 0, fmt.Errorf("%w: %q", ErrKeyNotFound, label)
+	case 1:
+		return objs[0], nil
+	default:
+		return 0, fmt.Errorf("key label %q is not unique", label)
+	}
+}
+
+func (s *Signer) findObjects(template []*pkcs11.Attribute) ([]pkcs11.ObjectHandle, error) {
+	err := s.ctx.FindObjectsInit(s.session, template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find objects: %w", err)
+	}
+	defer func() { _ = s.ctx.FindObjectsFinal(s.session) }()
+
+	objs, _, err := s.ctx.FindObjects(s.session, 2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find objects: %w", err)
+	}
+
+	return objs, nil
+}
+
+// generate ed25519 key pair labelled label, the private
+// key can sign but is never revealed by the token
+func (s *Signer) generate(label string) error {
+	objs, err := s.findObjects([]*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	})
+	if err != nil {
+		return err
+	}
+	if len(objs) > 0 {
+		return fmt.Errorf("%w: %q", ErrKeyExists, label)
+	}
+
+	public := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, ed25519Params),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	private := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+
+	_, _, err = s.ctx.GenerateKeyPair(s.session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(ckmECEdwardsKeyPairGen, nil)},
+		public, private)
+	if err != nil {
+		return fmt.Errorf("failed to generate key pair: %w", err)
+	}
+
+	return nil
+}
+
+// ecPoint raw public key of a CKA_EC_POINT value, tokens
+// return it either raw or as a DER octet string
+func ecPoint(b []byte) []byte {
+	if len(b) == 34 && b[0] == 0x04 && b[1] == 32 {
+		return b[2:]
+	}
+	return b
+}
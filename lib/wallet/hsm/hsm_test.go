@@ -0,0 +1,165 @@
+//go:build pkcs11
+
+package hsm
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+const (
+	tokenLabel = "tbd-test"
+	soPIN      = "12345678"
+	userPIN    = "1234"
+)
+
+// setupToken initialize an empty SoftHSM token in a temporary
+// directory, the test is skipped without SOFTHSM2_MODULE
+func setupToken(t *testing.T) Config {
+	t.Helper()
+
+	module := os.Getenv("SOFTHSM2_MODULE")
+	if module == "" {
+		t.Skip("SOFTHSM2_MODULE not set")
+	}
+
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "softhsm2.conf")
+	err := os.WriteFile(conf, []byte("directories.tokendir = "+dir+"\nobjectstore.backend = file\n"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write softhsm config: %v", err)
+	}
+	t.Setenv("SOFTHSM2_CONF", conf)
+
+	ctx := pkcs11.New(module)
+	if ctx == nil {
+		t.Fatalf("failed to load module %s", module)
+	}
+	defer ctx.Destroy()
+
+	if err = ctx.Initialize(); err != nil {
+		t.Fatalf("failed to initialize module: %v", err)
+	}
+	defer func() { _ = ctx.Finalize() }()
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil || len(slots) == 0 {
+		t.Fatalf("failed to list slots: %v", err)
+	}
+	if err = ctx.InitToken(slots[0], soPIN, tokenLabel); err != nil {
+		t.Fatalf("failed to initialize token: %v", err)
+	}
+
+	// the token moves to a new slot once initialized
+	slot, err := (&Signer{ctx: ctx}).findSlot(tokenLabel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		t.Fatalf("failed to open session: %v", err)
+	}
+	defer func() { _ = ctx.CloseSession(session) }()
+
+	if err = ctx.Login(session, pkcs11.CKU_SO, soPIN); err != nil {
+		t.Fatalf("failed to log in as security officer: %v", err)
+	}
+	defer func() { _ = ctx.Logout(session) }()
+	if err = ctx.InitPIN(session, userPIN); err != nil {
+		t.Fatalf("failed to set user pin: %v", err)
+	}
+
+	return Config{
+		Module:     module,
+		TokenLabel: tokenLabel,
+		PIN:        userPIN,
+		KeyLabel:   "audit",
+	}
+}
+
+func TestSigner(t *testing.T) {
+	cfg := setupToken(t)
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+
+	_, err := New(suite, cfg)
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrKeyNotFound)
+	}
+
+	s, err := Generate(suite, cfg)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	public := s.Public()
+	if err = s.Close(); err != nil {
+		t.Fatalf("failed to close signer: %v", err)
+	}
+
+	if _, err = Generate(suite, cfg); !errors.Is(err, ErrKeyExists) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrKeyExists)
+	}
+
+	// login state is shared by the sessions of a token,
+	// check the pin before any signer is logged in
+	wrong := cfg
+	wrong.PIN = "0000"
+	if _, err = New(suite, wrong); err == nil {
+		t.Fatal("expected error for wrong pin")
+	}
+	unknown := cfg
+	unknown.TokenLabel = "missing"
+	if _, err = New(suite, unknown); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("unexpected error %v expected %v", err, ErrTokenNotFound)
+	}
+
+	s, err = New(suite, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+
+	if !s.Public().Equal(public) {
+		t.Fatal("reopened signer has another public key")
+	}
+
+	var signer wallet.Signer = s
+	msg := []byte("audit block")
+
+	t.Run("sign", func(t *testing.T) {
+		sig, err := signer.Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = wallet.Verify(suite, signer.Public(), msg, sig); err != nil {
+			t.Fatal(err)
+		}
+
+		pb, err := signer.Public().MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to marshal public key: %v", err)
+		}
+		if !ed25519.Verify(pb, msg, sig) {
+			t.Fatal("ed25519 rejected the token signature")
+		}
+	})
+	t.Run("non extractable", func(t *testing.T) {
+		priv, err := s.findKey(pkcs11.CKO_PRIVATE_KEY, cfg.KeyLabel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = s.ctx.GetAttributeValue(s.session, priv, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+		})
+		if err == nil {
+			t.Fatal("token revealed the private key")
+		}
+	})
+}
@@ -0,0 +1,329 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// keystoreVersion version of the keystore file format
+	keystoreVersion = 1
+
+	kdfScrypt    = "scrypt"
+	cipherAESGCM = "aes-256-gcm"
+
+	// DefaultScryptN scrypt cost parameter
+	DefaultScryptN = 1 << 18
+	// DefaultScryptR scrypt block size parameter
+	DefaultScryptR = 8
+	// DefaultScryptP scrypt parallelization parameter
+	DefaultScryptP = 1
+
+	// maxScryptN upper bound of the cost accepted on import
+	maxScryptN = 1 << 22
+	// maxScryptR upper bound of the block size accepted on import
+	maxScryptR = 32
+	// maxScryptP upper bound of the parallelization accepted on import
+	maxScryptP = 16
+	// maxScryptMemory upper bound of the 128*N*r*p bytes scrypt
+	// works over, four times the default parameters
+	maxScryptMemory = 1 << 30
+
+	saltSize = 32
+	// derived key is split into the cipher key and the mac key
+	cipherKeySize = 32
+	macKeySize    = 32
+
+	keystorePerm = 0o600
+)
+
+var (
+	// ErrInvalidPassword password does not open the keystore or the file was modified
+	ErrInvalidPassword = errors.New("invalid password or corrupted keystore")
+	// ErrUnsupportedKeystore keystore version, kdf or cipher is not supported
+	ErrUnsupportedKeystore = errors.New("unsupported keystore")
+)
+
+// keystore password encrypted wallet file
+//
+// the key derived from the password by scrypt is split into an
+// aes-256-gcm key and an hmac-sha256 key, the mac covers the
+// header and ciphertext so a wrong password is detected before
+// decrypting and the header is bound to the ciphertext as gcm
+// additional data
+type keystore struct {
+	keystoreHeader
+	Ciphertext []byte `json:"ciphertext"`
+	MAC        []byte `json:"mac"`
+}
+
+type keystoreHeader struct {
+	Version   int          `json:"version"`
+	PublicKey string       `json:"public_key"`
+	KDF       string       `json:"kdf"`
+	KDFParams scryptParams `json:"kdf_params"`
+	Cipher    string       `json:"cipher"`
+	Nonce     []byte       `json:"nonce"`
+}
+
+type scryptParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+}
+
+// KeystoreOption keystore option pattern
+type KeystoreOption func(*scryptParams)
+
+// WithScryptParams scrypt cost parameters of the keystore
+func WithScryptParams(n, r, p int) KeystoreOption {
+	return func(sp *scryptParams) {
+		sp.N = n
+		sp.R = r
+		sp.P = p
+	}
+}
+
+// Export encrypt wallet with password to keystore file
+//
+// the file is written with 0600 permissions to a temporary
+// file and renamed over filePath
+func (w Wallet) Export(filePath string, password []byte, opts ...KeystoreOption) error {
+	pb, err := w.PrivateKey()
+	if err != nil {
+		return err
+	}
+
+	Pb, err := w.P.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	params := scryptParams{
+		N:    DefaultScryptN,
+		R:    DefaultScryptR,
+		P:    DefaultScryptP,
+		Salt: make([]byte, saltSize),
+	}
+	for _, opt := range opts {
+		opt(&params)
+	}
+
+	_, err = rand.Read(params.Salt)
+	if err != nil {
+		return fmt.Errorf("failed to read salt: %w", err)
+	}
+
+	ks := keystore{
+		keystoreHeader: keystoreHeader{
+			Version:   keystoreVersion,
+			PublicKey: hex.EncodeToString(Pb),
+			KDF:       kdfScrypt,
+			KDFParams: params,
+			Cipher:    cipherAESGCM,
+		},
+	}
+
+	cipherKey, macKey, err := params.deriveKeys(password)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(cipherKey)
+	if err != nil {
+		return err
+	}
+
+	ks.Nonce = make([]byte, aead.NonceSize())
+	_, err = rand.Read(ks.Nonce)
+	if err != nil {
+		return fmt.Errorf("failed to read nonce: %w", err)
+	}
+
+	ad, err := json.Marshal(ks.keystoreHeader)
+	if err != nil {
+		return fmt.Errorf("failed to marshal keystore header: %w", err)
+	}
+
+	ks.Ciphertext = aead.Seal(nil, ks.Nonce, pb, ad)
+	ks.MAC = computeMAC(macKey, ad, ks.Ciphertext)
+
+	kb, err := json.Marshal(&ks)
+	if err != nil {
+		return fmt.Errorf("failed to marshal keystore: %w", err)
+	}
+
+	return writeFileAtomic(filePath, kb)
+}
+
+// Import decrypt keystore file with password
+func Import(suite Suite, filePath string, password []byte) (Wallet, error) {
+	fp := filepath.Clean(filePath)
+	kb, err := os.ReadFile(fp)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Wallet{}, ErrNotExists
+		}
+		return Wallet{}, fmt.Errorf("failed to read file: %s %w", fp, err)
+	}
+
+	var ks keystore
+	err = json.Unmarshal(kb, &ks)
+	if err != nil {
+		return Wallet{}, fmt.Errorf("failed to decode keystore: %w", err)
+	}
+
+	if ks.Version != keystoreVersion || ks.KDF != kdfScrypt || ks.Cipher != cipherAESGCM {
+		return Wallet{}, fmt.Errorf("%w: version %d kdf %q cipher %q", ErrUnsupportedKeystore, ks.Version, ks.KDF, ks.Cipher)
+	}
+	err = ks.KDFParams.validate()
+	if err != nil {
+		return Wallet{}, err
+	}
+
+	cipherKey, macKey, err := ks.KDFParams.deriveKeys(password)
+	if err != nil {
+		return Wallet{}, err
+	}
+
+	ad, err := json.Marshal(ks.keystoreHeader)
+	if err != nil {
+		return Wallet{}, fmt.Errorf("failed to marshal keystore header: %w", err)
+	}
+
+	if !hmac.Equal(ks.MAC, computeMAC(macKey, ad, ks.Ciphertext)) {
+		return Wallet{}, ErrInvalidPassword
+	}
+
+	aead, err := newAEAD(cipherKey)
+	if err != nil {
+		return Wallet{}, err
+	}
+
+	pb, err := aead.Open(nil, ks.Nonce, ks.Ciphertext, ad)
+	if err != nil {
+		return Wallet{}, ErrInvalidPassword
+	}
+
+	w, err := FromPrivateKey(suite, pb)
+	if err != nil {
+		return Wallet{}, err
+	}
+
+	Pb, err := w.P.MarshalBinary()
+	if err != nil {
+		return Wallet{}, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	if hex.EncodeToString(Pb) != ks.PublicKey {
+		return Wallet{}, fmt.Errorf("%w: public key does not match private key", ErrInvalidPassword)
+	}
+
+	return w, nil
+}
+
+// validate bound the cost of deriving keys so a crafted
+// keystore cannot exhaust memory or cpu on import
+func (sp scryptParams) validate() error {
+	if sp.N < 2 || sp.N > maxScryptN {
+		return fmt.Errorf("%w: scrypt cost %d", ErrUnsupportedKeystore, sp.N)
+	}
+	if sp.R < 1 || sp.R > maxScryptR {
+		return fmt.Errorf("%w: scrypt block size %d", ErrUnsupportedKeystore, sp.R)
+	}
+	if sp.P < 1 || sp.P > maxScryptP {
+		return fmt.Errorf("%w: scrypt parallelization %d", ErrUnsupportedKeystore, sp.P)
+	}
+
+	// each factor is bounded above so the product cannot overflow
+	if mem := 128 * uint64(sp.N) * uint64(sp.R) * uint64(sp.P); mem > maxScryptMemory {
+		return fmt.Errorf("%w: scrypt memory %d bytes", ErrUnsupportedKeystore, mem)
+	}
+
+	return nil
+}
+
+// deriveKeys cipher and mac keys of password
+func (sp scryptParams) deriveKeys(password []byte) ([]byte, []byte, error) {
+	dk, err := scrypt.Key(password, sp.Salt, sp.N, sp.R, sp.P, cipherKeySize+macKeySize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrUnsupportedKeystore, err)
+	}
+	return dk[:cipherKeySize], dk[cipherKeySize:], nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cipher.NewGCM: %w", err)
+	}
+
+	return aead, nil
+}
+
+// computeMAC hmac-sha256 of header and ciphertext
+func computeMAC(key, header, ciphertext []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write(header)
+	_, _ = h.Write(ciphertext)
+	return h.Sum(nil)
+}
+
+// writeFileAtomic write b to a temporary file next to
+// filePath then rename it into place
+func writeFileAtomic(filePath string, b []byte) error {
+	fp := filepath.Clean(filePath)
+
+	f, err := os.CreateTemp(filepath.Dir(fp), "."+filepath.Base(fp)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmp := f.Name()
+	defer func() { _ = os.Remove(tmp) }()
+
+	err = f.Chmod(keystorePerm)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to chmod temporary file: %w", err)
+	}
+
+	_, err = f.Write(b)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+
+	err = f.Sync()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to sync keystore: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close keystore: %w", err)
+	}
+
+	err = os.Rename(tmp, fp)
+	if err != nil {
+		return fmt.Errorf("failed to rename keystore: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/xof/blake2xb"
+)
+
+const (
+	dir      = "testfiles"
+	filePath = dir + "/wallet.json"
+)
+
+var (
+	password = []byte("correct horse battery staple")
+
+	// low cost keeps the tests fast
+	testScrypt = WithScryptParams(1<<10, 8, 1)
+)
+
+func setupTest() error {
+	err := os.Mkdir(dir, os.ModePerm)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to create testfiles dir: %w", err)
+	}
+	return nil
+}
+
+func teardownTest() error {
+	err := os.RemoveAll(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove all: %w", err)
+	}
+	return nil
+}
+
+func TestExport(t *testing.T) {
+	err := setupTest()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer func() { _ = teardownTest() }()
+
+	rng := blake2xb.New(nil)
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(rng)
+	w := NewV1(suite)
+	err = w.Export(filePath, password, testScrypt)
+	if err != nil {
+		t.Fatalf("failed to export wallet: %v", err)
+	}
+
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("failed to stat keystore: %v", err)
+	}
+	if fi.Mode().Perm() != keystorePerm {
+		t.Fatalf("unexpected permissions %v expected %v", fi.Mode().Perm(), os.FileMode(keystorePerm))
+	}
+
+	pb, err := w.PrivateKey()
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+	kb, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to read keystore: %v", err)
+	}
+	for _, enc := range []string{base64.StdEncoding.EncodeToString(pb), hex.EncodeToString(pb)} {
+		if bytes.Contains(kb, []byte(enc)) {
+			t.Fatal("keystore contains the plaintext private key")
+		}
+	}
+
+	// overwriting leaves no temporary files behind
+	err = w.Export(filePath, password, testScrypt)
+	if err != nil {
+		t.Fatalf("failed to overwrite wallet: %v", err)
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, ".*"))
+	if err != nil {
+		t.Fatalf("failed to glob: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Fatalf("unexpected temporary files %v", matches)
+	}
+}
+
+func TestImport(t *testing.T) {
+	err := setupTest()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer func() { _ = teardownTest() }()
+
+	rng := blake2xb.New(nil)
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(rng)
+
+	w := NewV1(suite)
+	err = w.Export(filePath, password, testScrypt)
+	if err != nil {
+		t.Fatalf("failed to export file: %v", err)
+	}
+
+	rewrite := func(t *testing.T, fn func(ks map[string]any)) string {
+		t.Helper()
+		kb, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read keystore: %v", err)
+		}
+		ks := make(map[string]any)
+		if err = json.Unmarshal(kb, &ks); err != nil {
+			t.Fatalf("failed to decode keystore: %v", err)
+		}
+		fn(ks)
+		kb, err = json.Marshal(ks)
+		if err != nil {
+			t.Fatalf("failed to encode keystore: %v", err)
+		}
+		fp := filepath.Join(dir, t.Name()[len("TestImport/"):]+".json")
+		if err = os.WriteFile(fp, kb, keystorePerm); err != nil {
+			t.Fatalf("failed to write keystore: %v", err)
+		}
+		return fp
+	}
+
+	t.Run("success", func(t *testing.T) {
+		imported, err := Import(suite, filePath, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !imported.P.Equal(w.P) || !imported.p.Equal(w.p) {
+			t.Fatal("imported wallet does not match exported wallet")
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		expected := ErrNotExists
+		_, err = Import(suite, filePath+"2", password)
+		if !errors.Is(err, expected) {
+			t.Fatalf("unexpected error %v expected %v", err, expected)
+		}
+	})
+	t.Run("wrong password", func(t *testing.T) {
+		expected := ErrInvalidPassword
+		_, err = Import(suite, filePath, []byte("wrong"))
+		if !errors.Is(err, expected) {
+			t.Fatalf("unexpected error %v expected %v", err, expected)
+		}
+	})
+	t.Run("tampered", func(t *testing.T) {
+		fp := rewrite(t, func(ks map[string]any) {
+			ks["public_key"] = "00"
+		})
+		expected := ErrInvalidPassword
+		_, err = Import(suite, fp, password)
+		if !errors.Is(err, expected) {
+			t.Fatalf("unexpected error %v expected %v", err, expected)
+		}
+	})
+	scryptTests := map[string]map[string]any{
+		"scrypt cost":            {"n": maxScryptN * 2, "r": 1, "p": 1},
+		"scrypt block size":      {"n": 1 << 10, "r": maxScryptR + 1, "p": 1},
+		"scrypt parallelization": {"n": 1 << 10, "r": 8, "p": maxScryptP + 1},
+		"scrypt zero block size": {"n": 1 << 10, "r": 0, "p": 1},
+		"scrypt memory":          {"n": maxScryptN, "r": maxScryptR, "p": maxScryptP},
+	}
+	for name, params := range scryptTests {
+		t.Run(name, func(t *testing.T) {
+			fp := rewrite(t, func(ks map[string]any) {
+				kdf := ks["kdf_params"].(map[string]any)
+				for k, v := range params {
+					kdf[k] = v
+				}
+			})
+			expected := ErrUnsupportedKeystore
+			_, err := Import(suite, fp, password)
+			if !errors.Is(err, expected) {
+				t.Fatalf("unexpected error %v expected %v", err, expected)
+			}
+		})
+	}
+	t.Run("version", func(t *testing.T) {
+		fp := rewrite(t, func(ks map[string]any) {
+			ks["version"] = keystoreVersion + 1
+		})
+		expected := ErrUnsupportedKeystore
+		_, err = Import(suite, fp, password)
+		if !errors.Is(err, expected) {
+			t.Fatalf("unexpected error %v expected %v", err, expected)
+		}
+	})
+}
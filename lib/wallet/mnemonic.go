@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	// DefaultMnemonicBits entropy of a 24 word mnemonic
+	DefaultMnemonicBits = 256
+)
+
+var (
+	// ErrInvalidMnemonic mnemonic words or checksum are not valid
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+)
+
+// NewMnemonic bip-39 english mnemonic of bits entropy
+// read from crypto/rand, 128 to 256 in steps of 32
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", fmt.Errorf("failed to create entropy: %w", err)
+	}
+
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", fmt.Errorf("failed to create mnemonic: %w", err)
+	}
+
+	return mnemonic, nil
+}
+
+// ValidateMnemonic check words and checksum of mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	_, err := bip39.EntropyFromMnemonic(normalizeMnemonic(mnemonic))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidMnemonic, err)
+	}
+	return nil
+}
+
+// SeedFromMnemonic bip-39 seed of mnemonic and optional passphrase
+func SeedFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	mnemonic = normalizeMnemonic(mnemonic)
+
+	err := ValidateMnemonic(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	return bip39.NewSeed(mnemonic, passphrase), nil
+}
+
+// normalizeMnemonic lower case words separated by single spaces
+func normalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
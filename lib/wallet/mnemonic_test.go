@@ -0,0 +1,57 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMnemonic(t *testing.T) {
+	t.Run("vector", func(t *testing.T) {
+		// bip-39 reference vector of zero entropy
+		mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+		expected := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+
+		seed, err := SeedFromMnemonic(mnemonic, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(seed) != expected {
+			t.Fatalf("unexpected seed %x expected %s", seed, expected)
+		}
+
+		// whitespace and case are normalized
+		seed, err = SeedFromMnemonic("  "+strings.ToUpper(mnemonic)+"\n", "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(seed) != expected {
+			t.Fatalf("unexpected seed %x expected %s", seed, expected)
+		}
+	})
+	t.Run("generate", func(t *testing.T) {
+		mnemonic, err := NewMnemonic(DefaultMnemonicBits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if words := strings.Fields(mnemonic); len(words) != 24 {
+			t.Fatalf("unexpected word count %d", len(words))
+		}
+		if err = ValidateMnemonic(mnemonic); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		expected := ErrInvalidMnemonic
+		for _, m := range []string{
+			"",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon zzzz",
+		} {
+			if _, err := SeedFromMnemonic(m, ""); !errors.Is(err, expected) {
+				t.Fatalf("unexpected error %v expected %v for %q", err, expected, m)
+			}
+		}
+	})
+}
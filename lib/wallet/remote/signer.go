@@ -0,0 +1,109 @@
+// Package remote signer backed by a signer service over gRPC
+//
+// the private key stays with the signer service, every
+// signature it returns is verified before it is handed out
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.dedis.ch/kyber/v4"
+
+	"github.com/trevatk/tbd/lib/wallet"
+
+	pb "github.com/trevatk/tbd/lib/protocol/signer/v1"
+)
+
+const (
+	defaultTimeout = time.Second * 10
+)
+
+var (
+	// ErrPublicKeyMismatch signer service key is not the pinned key
+	ErrPublicKeyMismatch = errors.New("signer public key does not match pinned key")
+)
+
+// Signer remote signer
+type Signer struct {
+	suite   wallet.Suite
+	client  pb.SignerServiceClient
+	public  kyber.Point
+	pinned  kyber.Point
+	timeout time.Duration
+}
+
+// SignerOption remote signer option pattern
+type SignerOption func(*Signer)
+
+// WithTimeout deadline of every signing request
+func WithTimeout(d time.Duration) SignerOption {
+	return func(s *Signer) {
+		s.timeout = d
+	}
+}
+
+// WithPublicKey expected public key of the signer service, a
+// service advertising another key is rejected by NewSigner
+func WithPublicKey(p kyber.Point) SignerOption {
+	return func(s *Signer) {
+		s.pinned = p
+	}
+}
+
+// NewSigner return new remote signer, the public
+// key is fetched once from the signer service
+func NewSigner(ctx context.Context, suite wallet.Suite, client pb.SignerServiceClient, opts ...SignerOption) (*Signer, error) {
+	s := &Signer{
+		suite:   suite,
+		client:  client,
+		timeout: defaultTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	resp, err := client.PublicKey(ctx, &pb.PublicKeyRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key: %w", err)
+	}
+
+	s.public = suite.Point()
+	err = s.public.UnmarshalBinary(resp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal public key: %w", err)
+	}
+
+	if s.pinned != nil && !s.pinned.Equal(s.public) {
+		return nil, fmt.Errorf("%w: signer advertised %s", ErrPublicKeyMismatch, s.public)
+	}
+
+	return s, nil
+}
+
+// Public public key of the signer service
+func (s *Signer) Public() kyber.Point {
+	return s.public
+}
+
+// Sign schnorr signature of message by the signer service
+func (s *Signer) Sign(message []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	resp, err := s.client.Sign(ctx, &pb.SignRequest{Message: message})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign remotely: %w", err)
+	}
+
+	// a signer answering with another key must not go unnoticed
+	err = wallet.Verify(s.suite, s.public, message, resp.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Signature, nil
+}
@@ -0,0 +1,109 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/trevatk/tbd/lib/protocol"
+	"github.com/trevatk/tbd/lib/wallet"
+
+	pb "github.com/trevatk/tbd/lib/protocol/signer/v1"
+)
+
+// impostor advertises one key and signs with another
+type impostor struct {
+	wallet.Signer
+	public kyber.Point
+}
+
+func (i impostor) Public() kyber.Point {
+	return i.public
+}
+
+func newClient(t *testing.T, ctx context.Context, signer wallet.Signer) pb.SignerServiceClient {
+	t.Helper()
+
+	logger := slog.Default()
+	desc, service := NewTransport(logger, signer)
+
+	ts := protocol.NewTestServer(
+		protocol.WithTestTransports([]protocol.Transport{{ServiceDesc: desc, Service: service}}),
+		protocol.WithTestLogger(logger),
+	)
+	go func() { _ = ts.Start(ctx) }()
+	t.Cleanup(func() { ts.Stop(ctx) })
+
+	conn, err := protocol.NewTestConn(ctx, ts.BufDialer)
+	if err != nil {
+		t.Fatalf("failed to create test conn: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewSignerServiceClient(conn)
+}
+
+func TestSigner(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	local := wallet.NewSigner(suite, wallet.NewV1(suite))
+	msg := []byte("audit block")
+
+	t.Run("success", func(t *testing.T) {
+		client := newClient(t, ctx, local)
+		s, err := NewSigner(ctx, suite, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.Public().Equal(local.Public()) {
+			t.Fatal("remote signer has another public key")
+		}
+
+		sig, err := s.Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = wallet.Verify(suite, local.Public(), msg, sig); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = client.Sign(ctx, &pb.SignRequest{})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("unexpected error %v expected %v", err, codes.InvalidArgument)
+		}
+	})
+	t.Run("key mismatch", func(t *testing.T) {
+		client := newClient(t, ctx, impostor{Signer: local, public: wallet.NewV1(suite).P})
+		s, err := NewSigner(ctx, suite, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := wallet.ErrInvalidSignature
+		if _, err = s.Sign(msg); !errors.Is(err, expected) {
+			t.Fatalf("unexpected error %v expected %v", err, expected)
+		}
+	})
+	t.Run("pinned key", func(t *testing.T) {
+		client := newClient(t, ctx, local)
+		if _, err := NewSigner(ctx, suite, client, WithPublicKey(local.Public())); err != nil {
+			t.Fatal(err)
+		}
+
+		// a service holding another key is never used
+		other := wallet.NewSigner(suite, wallet.NewV1(suite))
+		client = newClient(t, ctx, other)
+		if _, err := NewSigner(ctx, suite, client, WithPublicKey(local.Public())); !errors.Is(err, ErrPublicKeyMismatch) {
+			t.Fatalf("unexpected error %v expected %v", err, ErrPublicKeyMismatch)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+
+	"buf.build/go/protovalidate"
+	"google.golang.org/grpc"
+
+	"github.com/trevatk/tbd/lib/protocol"
+	"github.com/trevatk/tbd/lib/wallet"
+
+	pb "github.com/trevatk/tbd/lib/protocol/signer/v1"
+)
+
+type transport struct {
+	pb.UnimplementedSignerServiceServer
+
+	logger *slog.Logger
+	signer wallet.Signer
+}
+
+// NewTransport return signer gateway transport implementation
+//
+// every caller able to reach the service can sign, serve
+// it on an authenticated connection only
+func NewTransport(logger *slog.Logger, signer wallet.Signer) (*grpc.ServiceDesc, pb.SignerServiceServer) {
+	return &pb.SignerService_ServiceDesc,
+		&transport{
+			logger: logger,
+			signer: signer,
+		}
+}
+
+// PublicKey
+func (t *transport) PublicKey(ctx context.Context, _ *pb.PublicKeyRequest) (*pb.PublicKeyResponse, error) {
+	Pb, err := t.signer.Public().MarshalBinary()
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to marshal public key", "error", err)
+		return nil, protocol.ErrInternal()
+	}
+
+	return &pb.PublicKeyResponse{PublicKey: Pb}, nil
+}
+
+// Sign
+func (t *transport) Sign(ctx context.Context, in *pb.SignRequest) (*pb.SignResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	sig, err := t.signer.Sign(in.Message)
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to sign", "error", err)
+		return nil, protocol.ErrInternal()
+	}
+
+	h := sha256.Sum256(in.Message)
+	t.logger.InfoContext(ctx, "signed message", "sha256", hex.EncodeToString(h[:]))
+
+	return &pb.SignResponse{Signature: sig}, nil
+}
@@ -0,0 +1,48 @@
+package wallet
+
+import (
+	"go.dedis.ch/kyber/v4"
+)
+
+// Signer sign with a private key that is never handed out
+//
+// every implementation produces signatures checked by Verify,
+// the private key may live in memory, a remote signer or an HSM
+type Signer interface {
+	// Public public key of the signer
+	Public() kyber.Point
+	// Sign schnorr signature of message
+	Sign(message []byte) ([]byte, error)
+}
+
+type memorySigner struct {
+	suite Suite
+	w     Wallet
+}
+
+// NewSigner in memory signer of wallet w
+func NewSigner(suite Suite, w Wallet) Signer {
+	return &memorySigner{
+		suite: suite,
+		w:     w,
+	}
+}
+
+// Public public key of the wallet
+func (s *memorySigner) Public() kyber.Point {
+	return s.w.P
+}
+
+// Sign schnorr signature of message
+func (s *memorySigner) Sign(message []byte) ([]byte, error) {
+	return s.w.Sign(s.suite, message)
+}
+
+// SignerAddr encoded address of signer on network
+func SignerAddr(s Signer, network Network) (string, error) {
+	a, err := NewAddress(network, s.Public())
+	if err != nil {
+		return "", err
+	}
+	return a.String(), nil
+}
@@ -0,0 +1,90 @@
+package threshold
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v4/share"
+	pedersen "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	vss "go.dedis.ch/kyber/v4/share/vss/pedersen"
+	"go.dedis.ch/kyber/v4/sign/dss"
+
+	pb "github.com/trevatk/tbd/lib/protocol/threshold/v1"
+)
+
+func dealToProto(sessionID string, d *pedersen.Deal, sessionSig []byte) *pb.DealRequest {
+	return &pb.DealRequest{
+		SessionId:        sessionID,
+		Dealer:           d.Index,
+		DhKey:            d.Deal.DHKey,
+		DealSignature:    d.Deal.Signature,
+		Nonce:            d.Deal.Nonce,
+		Cipher:           d.Deal.Cipher,
+		Signature:        d.Signature,
+		SessionSignature: sessionSig,
+	}
+}
+
+func dealFromProto(in *pb.DealRequest) *pedersen.Deal {
+	return &pedersen.Deal{
+		Index: in.Dealer,
+		Deal: &vss.EncryptedDeal{
+			DHKey:     in.DhKey,
+			Signature: in.DealSignature,
+			Nonce:     in.Nonce,
+			Cipher:    in.Cipher,
+		},
+		Signature: in.Signature,
+	}
+}
+
+func responseToProto(sessionID string, r *pedersen.Response, sessionSig []byte) *pb.RespondRequest {
+	return &pb.RespondRequest{
+		SessionId:        sessionID,
+		Dealer:           r.Index,
+		DealSessionId:    r.Response.SessionID,
+		Verifier:         r.Response.Index,
+		Approved:         r.Response.Status,
+		Signature:        r.Response.Signature,
+		SessionSignature: sessionSig,
+	}
+}
+
+func responseFromProto(in *pb.RespondRequest) *pedersen.Response {
+	return &pedersen.Response{
+		Index: in.Dealer,
+		Response: &vss.Response{
+			SessionID: in.DealSessionId,
+			Index:     in.Verifier,
+			Status:    in.Approved,
+			Signature: in.Signature,
+		},
+	}
+}
+
+func partialToProto(ps *dss.PartialSig) (*pb.PartialSignResponse, error) {
+	vb, err := ps.Partial.V.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal partial signature: %w", err)
+	}
+
+	return &pb.PartialSignResponse{
+		Index:     uint32(ps.Partial.I),
+		Partial:   vb,
+		SessionId: ps.SessionID,
+		Signature: ps.Signature,
+	}, nil
+}
+
+func partialFromProto(suite Suite, in *pb.PartialSignResponse) (*dss.PartialSig, error) {
+	v := suite.Scalar()
+	err := v.UnmarshalBinary(in.Partial)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal partial signature: %w", err)
+	}
+
+	return &dss.PartialSig{
+		Partial:   &share.PriShare{I: int(in.Index), V: v},
+		SessionID: in.SessionId,
+		Signature: in.Signature,
+	}, nil
+}
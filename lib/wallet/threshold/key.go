@@ -0,0 +1,102 @@
+package threshold
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+	pedersen "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+// KeyShare share of the group key held by a participant
+//
+// the share is secret, persist it the way wallets are
+type KeyShare struct {
+	share *pedersen.DistKeyShare
+}
+
+type keyShareJSON struct {
+	Index   int      `json:"index"`
+	Share   string   `json:"share"`
+	Commits []string `json:"commits"`
+}
+
+// Public group public key
+func (k *KeyShare) Public() kyber.Point {
+	return k.share.Public()
+}
+
+// MarshalJSON encode index, private share and public commitments
+func (k *KeyShare) MarshalJSON() ([]byte, error) {
+	sb, err := k.share.Share.V.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal share: %w", err)
+	}
+
+	commits := make([]string, 0, len(k.share.Commits))
+	for _, c := range k.share.Commits {
+		cb, err := c.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal commitment: %w", err)
+		}
+		commits = append(commits, hex.EncodeToString(cb))
+	}
+
+	return json.Marshal(keyShareJSON{
+		Index:   k.share.Share.I,
+		Share:   hex.EncodeToString(sb),
+		Commits: commits,
+	})
+}
+
+// UnmarshalKeyShare decode a key share encoded by MarshalJSON
+func UnmarshalKeyShare(suite Suite, b []byte) (*KeyShare, error) {
+	var kj keyShareJSON
+	err := json.Unmarshal(b, &kj)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	sb, err := hex.DecodeString(kj.Share)
+	if err != nil {
+		return nil, fmt.Errorf("invalid share %q", kj.Share)
+	}
+	v := suite.Scalar()
+	err = v.UnmarshalBinary(sb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal share: %w", err)
+	}
+
+	if len(kj.Commits) == 0 {
+		return nil, fmt.Errorf("key share has no commitments")
+	}
+
+	commits := make([]kyber.Point, 0, len(kj.Commits))
+	for _, c := range kj.Commits {
+		cb, err := hex.DecodeString(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commitment %q", c)
+		}
+		p := suite.Point()
+		err = p.UnmarshalBinary(cb)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal commitment: %w", err)
+		}
+		commits = append(commits, p)
+	}
+
+	// the share must lie on the committed polynomial
+	pub := share.NewPubPoly(suite, suite.Point().Base(), commits).Eval(kj.Index)
+	if !pub.V.Equal(suite.Point().Mul(v, nil)) {
+		return nil, fmt.Errorf("share does not match its commitments")
+	}
+
+	return &KeyShare{
+		share: &pedersen.DistKeyShare{
+			Commits: commits,
+			Share:   &share.PriShare{I: kj.Index, V: v},
+		},
+	}, nil
+}
@@ -0,0 +1,556 @@
+// Package threshold t-of-n schnorr signatures of a group key
+// generated by a distributed key generation ceremony
+//
+// no participant ever holds the group private key, any t of
+// the n participants produce a signature that verifies with
+// wallet.Verify against the group public key
+package threshold
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.dedis.ch/kyber/v4"
+	pedersen "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign/dss"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"google.golang.org/grpc"
+
+	"github.com/trevatk/tbd/lib/wallet"
+
+	pb "github.com/trevatk/tbd/lib/protocol/threshold/v1"
+)
+
+const (
+	// keySession session of the long term group key
+	keySession = "key"
+	// noncePrefix prefix of single use signing nonce sessions
+	noncePrefix = "nonce-"
+
+	defaultTimeout    = time.Second * 10
+	defaultSessionTTL = time.Minute
+	// maxSessions open nonce sessions, each one runs a key generation
+	maxSessions = 64
+
+	// kinds of messages participants sign into a session
+	kindDeal     = "deal"
+	kindResponse = "response"
+	kindSign     = "sign"
+)
+
+var (
+	errInvalidThreshold = errors.New("threshold must be between 2 and the number of participants")
+	errNotParticipant   = errors.New("wallet is not a participant")
+	errInvalidSession   = errors.New("invalid session")
+	errNoKey            = errors.New("group key not generated")
+	errNonceUsed        = errors.New("nonce already used")
+	errNotCertified     = errors.New("key generation not certified by a threshold of participants")
+	errComplaint        = errors.New("deal received a complaint")
+	errRejected         = errors.New("message rejected")
+	errNotEnough        = errors.New("not enough partial signatures")
+	errTooManyPending   = errors.New("too many responses ahead of their deal")
+	errTooManySessions  = errors.New("too many open sessions")
+	errUnauthenticated  = errors.New("message not signed by a participant")
+)
+
+// Suite threshold crypto suite
+type Suite interface {
+	wallet.Suite
+	kyber.HashFactory
+}
+
+// Approver accept or reject a message a remote participant asks to sign
+type Approver func(message []byte) error
+
+// Node participant of a threshold group
+type Node struct {
+	suite        Suite
+	secret       kyber.Scalar
+	index        int
+	participants []kyber.Point
+	t            int
+
+	// peers clients of the other participants by index
+	peers    map[int]pb.ThresholdServiceClient
+	approve  Approver
+	timeout  time.Duration
+	ttl      time.Duration
+	keyShare *KeyShare
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	// used nonce sessions, kept until they expire
+	used map[string]time.Time
+}
+
+// NodeOption node option pattern
+type NodeOption func(*Node)
+
+// WithPeers clients of the other participants by index
+func WithPeers(peers map[int]pb.ThresholdServiceClient) NodeOption {
+	return func(n *Node) {
+		n.peers = peers
+	}
+}
+
+// WithApprover check messages remote participants ask to sign,
+// every request is rejected when not set
+func WithApprover(approve Approver) NodeOption {
+	return func(n *Node) {
+		n.approve = approve
+	}
+}
+
+// WithTimeout time to wait for every participant during nonce
+// generation before continuing with a threshold of them
+//
+// participants that time out with different responses derive
+// different nonces, their partial signatures are rejected
+func WithTimeout(d time.Duration) NodeOption {
+	return func(n *Node) {
+		n.timeout = d
+	}
+}
+
+// WithKeyShare resume a group key generated before
+func WithKeyShare(k *KeyShare) NodeOption {
+	return func(n *Node) {
+		n.keyShare = k
+	}
+}
+
+// NewNode return new participant signing with w
+//
+// every participant is configured with the same ordered list
+// of public keys and threshold t
+func NewNode(suite Suite, w wallet.Wallet, participants []kyber.Point, t int, opts ...NodeOption) (*Node, error) {
+	if t < 2 || t > len(participants) {
+		return nil, errInvalidThreshold
+	}
+
+	pk, err := w.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	secret := suite.Scalar()
+	err = secret.UnmarshalBinary(pk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal private key: %w", err)
+	}
+
+	index := -1
+	for i, p := range participants {
+		if p.Equal(w.P) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, errNotParticipant
+	}
+
+	n := &Node{
+		suite:        suite,
+		secret:       secret,
+		index:        index,
+		participants: participants,
+		t:            t,
+		peers:        make(map[int]pb.ThresholdServiceClient),
+		approve: func([]byte) error {
+			return fmt.Errorf("%w: no approver configured", errRejected)
+		},
+		timeout:  defaultTimeout,
+		ttl:      defaultSessionTTL,
+		sessions: make(map[string]*session),
+		used:     make(map[string]time.Time),
+	}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	if n.keyShare != nil && n.keyShare.share.Share.I != index {
+		return nil, fmt.Errorf("key share of participant %d used by participant %d", n.keyShare.share.Share.I, index)
+	}
+
+	return n, nil
+}
+
+// Index participant index of the node
+func (n *Node) Index() int {
+	return n.index
+}
+
+// KeyShare share of the group key, nil before key generation
+func (n *Node) KeyShare() *KeyShare {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.keyShare
+}
+
+// Keygen run the key generation ceremony and return the group
+// public key, every participant must take part
+func (n *Node) Keygen(ctx context.Context) (kyber.Point, error) {
+	if k := n.KeyShare(); k != nil {
+		return k.Public(), nil
+	}
+
+	s, err := n.session(keySession)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := s.wait(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("key generation failed: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.keyShare == nil {
+		n.keyShare = &KeyShare{share: share}
+	}
+
+	return n.keyShare.Public(), nil
+}
+
+// Sign collect partial signatures of message from the participants
+// and return the group signature once t of them are valid
+func (n *Node) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	key := n.KeyShare()
+	if key == nil {
+		return nil, errNoKey
+	}
+
+	nonce := make([]byte, 16)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nonce session: %w", err)
+	}
+	id := noncePrefix + hex.EncodeToString(nonce)
+
+	// the own partial signature is recorded in d
+	d, _, err := n.partialSign(ctx, id, message)
+	if err != nil {
+		return nil, err
+	}
+
+	requestSig, err := n.signSession(kindSign, id, message)
+	if err != nil {
+		return nil, err
+	}
+	req := &pb.PartialSignRequest{
+		SessionId: id,
+		Message:   message,
+		Requester: uint32(n.index),
+		Signature: requestSig,
+	}
+
+	type result struct {
+		ps  *dss.PartialSig
+		err error
+	}
+
+	results := make(chan result, len(n.peers))
+	for _, peer := range n.peers {
+		go func() {
+			resp, err := peer.PartialSign(ctx, req, grpc.WaitForReady(true))
+			if err != nil {
+				results <- result{err: err}
+				return
+			}
+			ps, err := partialFromProto(n.suite, resp)
+			results <- result{ps: ps, err: err}
+		}()
+	}
+
+	errs := make([]error, 0)
+	for range n.peers {
+		if d.EnoughPartialSig() {
+			break
+		}
+
+		r := <-results
+		if r.err == nil {
+			r.err = d.ProcessPartialSig(r.ps)
+		}
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+	}
+
+	if !d.EnoughPartialSig() {
+		return nil, fmt.Errorf("%w: %w", errNotEnough, errors.Join(errs...))
+	}
+
+	sig, err := d.Signature()
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine partial signatures: %w", err)
+	}
+
+	// never hand out a signature the group key does not verify
+	err = wallet.Verify(n.suite, key.Public(), message, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// partialSign partial signature of message with the nonce of session id
+func (n *Node) partialSign(ctx context.Context, id string, message []byte) (*dss.DSS, *dss.PartialSig, error) {
+	key := n.KeyShare()
+	if key == nil {
+		return nil, nil, errNoKey
+	}
+
+	s, err := n.session(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce, err := s.wait(ctx, n.timeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("nonce generation failed: %w", err)
+	}
+
+	err = n.consume(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d, err := dss.NewDSS(n.suite, n.secret, n.participants, key.share, nonce, message, n.t)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dss.NewDSS: %w", err)
+	}
+
+	ps, err := d.PartialSig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create partial signature: %w", err)
+	}
+
+	return d, ps, nil
+}
+
+// remotePartialSign partial signature requested by another participant,
+// requests not signed by a participant never reach the approver
+func (n *Node) remotePartialSign(ctx context.Context, id string, message []byte, requester uint32, sig []byte) (*dss.PartialSig, error) {
+	err := n.authenticate(kindSign, requester, id, message, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	err = n.approve(message)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errRejected, err)
+	}
+
+	_, ps, err := n.partialSign(ctx, id, message)
+	return ps, err
+}
+
+// consume mark nonce session id used
+func (n *Node) consume(id string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.used[id]; ok {
+		return errNonceUsed
+	}
+
+	n.used[id] = time.Now()
+	delete(n.sessions, id)
+
+	return nil
+}
+
+// session get or start the session id, a started session
+// deals its shares to every other participant
+func (n *Node) session(id string) (*session, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if s, ok := n.sessions[id]; ok {
+		return s, nil
+	}
+
+	switch {
+	case id == keySession:
+		if n.keyShare != nil {
+			return nil, fmt.Errorf("%w: group key already generated", errInvalidSession)
+		}
+	case strings.HasPrefix(id, noncePrefix):
+		if n.keyShare == nil {
+			return nil, errNoKey
+		}
+		if _, ok := n.used[id]; ok {
+			return nil, errNonceUsed
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", errInvalidSession, id)
+	}
+
+	n.prune()
+
+	if id != keySession && len(n.sessions) >= maxSessions {
+		return nil, errTooManySessions
+	}
+
+	dkg, err := pedersen.NewDistKeyGenerator(n.suite, n.secret, n.participants, n.t)
+	if err != nil {
+		return nil, fmt.Errorf("pedersen.NewDistKeyGenerator: %w", err)
+	}
+
+	deals, err := dkg.Deals()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deals: %w", err)
+	}
+
+	s := newSession(id, n.index, len(n.participants), dkg)
+	n.sessions[id] = s
+
+	for i, d := range deals {
+		sessionSig, err := n.signSession(kindDeal, id, d.Signature)
+		if err != nil {
+			return nil, err
+		}
+		go n.send(i, func(ctx context.Context, peer pb.ThresholdServiceClient) error {
+			_, err := peer.Deal(ctx, dealToProto(id, d, sessionSig), grpc.WaitForReady(true))
+			return err
+		})
+	}
+
+	return s, nil
+}
+
+// prune drop nonce sessions and used nonces older than ttl, must hold mu
+func (n *Node) prune() {
+	for id, s := range n.sessions {
+		if id != keySession && time.Since(s.created) > n.ttl {
+			delete(n.sessions, id)
+		}
+	}
+	for id, at := range n.used {
+		if time.Since(at) > n.ttl {
+			delete(n.used, id)
+		}
+	}
+}
+
+// send deliver a message to participant i
+func (n *Node) send(i int, fn func(context.Context, pb.ThresholdServiceClient) error) {
+	peer, ok := n.peers[i]
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+
+	// an undelivered message shows as a missing
+	// response once the session times out
+	_ = fn(ctx, peer)
+}
+
+// processDeal verify a deal and broadcast the response
+//
+// the dealer is authenticated before the session is
+// started, unsigned deals never trigger a key generation
+func (n *Node) processDeal(id string, d *pedersen.Deal, sessionSig []byte) error {
+	err := n.authenticate(kindDeal, d.Index, id, d.Signature, sessionSig)
+	if err != nil {
+		return err
+	}
+
+	db, err := d.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal deal: %w", err)
+	}
+	err = wallet.Verify(n.suite, n.participants[d.Index], db, d.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: deal of participant %d: %w", errUnauthenticated, d.Index, err)
+	}
+
+	s, err := n.session(id)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.processDeal(d)
+	if err != nil {
+		return err
+	}
+
+	sessionSig, err = n.signSession(kindResponse, id, resp.Response.Signature)
+	if err != nil {
+		return err
+	}
+
+	for i := range n.participants {
+		if i == n.index {
+			continue
+		}
+		go n.send(i, func(ctx context.Context, peer pb.ThresholdServiceClient) error {
+			_, err := peer.Respond(ctx, responseToProto(id, resp, sessionSig), grpc.WaitForReady(true))
+			return err
+		})
+	}
+
+	return nil
+}
+
+// processResponse store the response of a participant to a deal
+//
+// the verifier is authenticated before the session is started
+func (n *Node) processResponse(id string, r *pedersen.Response, sessionSig []byte) error {
+	err := n.authenticate(kindResponse, r.Response.Index, id, r.Response.Signature, sessionSig)
+	if err != nil {
+		return err
+	}
+
+	err = wallet.Verify(n.suite, n.participants[r.Response.Index], r.Response.Hash(n.suite), r.Response.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: response of participant %d: %w", errUnauthenticated, r.Response.Index, err)
+	}
+
+	s, err := n.session(id)
+	if err != nil {
+		return err
+	}
+	return s.processResponse(r)
+}
+
+// signSession bind sig of a deal or response, or the message
+// of a partial signature request, to session id
+func (n *Node) signSession(kind, id string, sig []byte) ([]byte, error) {
+	sessionSig, err := schnorr.Sign(n.suite, n.secret, sessionMessage(kind, id, sig))
+	if err != nil {
+		return nil, fmt.Errorf("schnorr.Sign: %w", err)
+	}
+	return sessionSig, nil
+}
+
+// authenticate verify participant i bound sig to session id
+func (n *Node) authenticate(kind string, i uint32, id string, sig, sessionSig []byte) error {
+	if int(i) >= len(n.participants) {
+		return fmt.Errorf("%w: unknown participant %d", errUnauthenticated, i)
+	}
+
+	err := wallet.Verify(n.suite, n.participants[i], sessionMessage(kind, id, sig), sessionSig)
+	if err != nil {
+		return fmt.Errorf("%w: participant %d: %w", errUnauthenticated, i, err)
+	}
+
+	return nil
+}
+
+// sessionMessage kind and length prefixed session id followed by sig
+func sessionMessage(kind, id string, sig []byte) []byte {
+	return append(fmt.Appendf(nil, "%s:%d:%s", kind, len(id), id), sig...)
+}
@@ -0,0 +1,376 @@
+package threshold
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	pedersen "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	vss "go.dedis.ch/kyber/v4/share/vss/pedersen"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"golang.org/x/crypto/sha3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/trevatk/tbd/lib/wallet"
+
+	pb "github.com/trevatk/tbd/lib/protocol/threshold/v1"
+)
+
+const (
+	bufSize = 1024 * 1024
+
+	testN = 5
+	testT = 3
+)
+
+// group n nodes connected over bufconn
+type group struct {
+	nodes   []*Node
+	servers []*grpc.Server
+}
+
+// newGroup start testN nodes, timeout zero keeps the default
+func newGroup(t *testing.T, approve Approver, timeout time.Duration) *group {
+	t.Helper()
+
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	wallets := make([]wallet.Wallet, testN)
+	participants := make([]kyber.Point, testN)
+	listeners := make([]*bufconn.Listener, testN)
+	for i := range testN {
+		wallets[i] = wallet.NewV1(suite)
+		participants[i] = wallets[i].P
+		listeners[i] = bufconn.Listen(bufSize)
+	}
+
+	g := &group{}
+	for i := range testN {
+		peers := make(map[int]pb.ThresholdServiceClient)
+		for j, lis := range listeners {
+			if j == i {
+				continue
+			}
+			conn, err := grpc.NewClient("passthrough:///bufnet",
+				grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+					return lis.DialContext(ctx)
+				}),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+			peers[j] = pb.NewThresholdServiceClient(conn)
+		}
+
+		opts := []NodeOption{WithPeers(peers)}
+		if approve != nil {
+			opts = append(opts, WithApprover(approve))
+		}
+		if timeout > 0 {
+			opts = append(opts, WithTimeout(timeout))
+		}
+		n, err := NewNode(suite, wallets[i], participants, testT, opts...)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+
+		s := grpc.NewServer()
+		s.RegisterService(NewTransport(logger, n))
+		go func() { _ = s.Serve(listeners[i]) }()
+		t.Cleanup(s.Stop)
+
+		g.nodes = append(g.nodes, n)
+		g.servers = append(g.servers, s)
+	}
+
+	return g
+}
+
+// keygen run the ceremony on every node and return the group key
+func (g *group) keygen(t *testing.T) kyber.Point {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	keys := make([]kyber.Point, len(g.nodes))
+	errs := make([]error, len(g.nodes))
+	var wg sync.WaitGroup
+	for i, n := range g.nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			keys[i], errs[i] = n.Keygen(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("node %d failed key generation: %v", i, err)
+		}
+		if !keys[i].Equal(keys[0]) {
+			t.Fatalf("node %d generated another group key", i)
+		}
+	}
+
+	return keys[0]
+}
+
+func TestNewNode(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	w := wallet.NewV1(suite)
+	participants := []kyber.Point{w.P, wallet.NewV1(suite).P, wallet.NewV1(suite).P}
+
+	for _, th := range []int{1, 4} {
+		if _, err := NewNode(suite, w, participants, th); !errors.Is(err, errInvalidThreshold) {
+			t.Fatalf("unexpected error %v expected %v", err, errInvalidThreshold)
+		}
+	}
+	if _, err := NewNode(suite, wallet.NewV1(suite), participants, 2); !errors.Is(err, errNotParticipant) {
+		t.Fatalf("unexpected error %v expected %v", err, errNotParticipant)
+	}
+}
+
+func TestProcessUnauthenticated(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	wallets := []wallet.Wallet{wallet.NewV1(suite), wallet.NewV1(suite), wallet.NewV1(suite)}
+	participants := []kyber.Point{wallets[0].P, wallets[1].P, wallets[2].P}
+
+	nodes := make([]*Node, len(wallets))
+	for i, w := range wallets {
+		n, err := NewNode(suite, w, participants, 2)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		nodes[i] = n
+	}
+
+	dkg, err := pedersen.NewDistKeyGenerator(suite, nodes[1].secret, participants, 2)
+	if err != nil {
+		t.Fatalf("failed to create key generator: %v", err)
+	}
+	deals, err := dkg.Deals()
+	if err != nil {
+		t.Fatalf("failed to create deals: %v", err)
+	}
+	deal := deals[0]
+	sessionSig, err := nodes[1].signSession(kindDeal, keySession, deal.Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedSig, err := schnorr.Sign(suite, suite.Scalar().Pick(suite.RandomStream()), sessionMessage(kindDeal, keySession, deal.Signature))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := noncePrefix + "replayed"
+	tests := []struct {
+		name       string
+		id         string
+		deal       *pedersen.Deal
+		sessionSig []byte
+	}{
+		{name: "forged session signature", id: keySession, deal: deal, sessionSig: forgedSig},
+		{name: "replayed into another session", id: replayed, deal: deal, sessionSig: sessionSig},
+		{name: "unknown dealer", id: keySession, deal: &pedersen.Deal{Index: 9, Deal: deal.Deal, Signature: deal.Signature}, sessionSig: sessionSig},
+		{name: "tampered deal", id: keySession, deal: &pedersen.Deal{Index: 1, Deal: &vss.EncryptedDeal{
+			DHKey:     deal.Deal.DHKey,
+			Signature: deal.Deal.Signature,
+			Nonce:     deal.Deal.Nonce,
+			Cipher:    append([]byte{0}, deal.Deal.Cipher...),
+		}, Signature: deal.Signature}, sessionSig: sessionSig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := nodes[0].processDeal(tt.id, tt.deal, tt.sessionSig)
+			if !errors.Is(err, errUnauthenticated) {
+				t.Fatalf("unexpected error %v expected %v", err, errUnauthenticated)
+			}
+			if len(nodes[0].sessions) != 0 {
+				t.Fatal("unauthenticated deal started a session")
+			}
+		})
+	}
+
+	forged := &pedersen.Response{Index: 1, Response: &vss.Response{SessionID: []byte("deal"), Index: 2, Status: true, Signature: deal.Signature}}
+	forgedSig, err = nodes[2].signSession(kindResponse, keySession, forged.Response.Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = nodes[0].processResponse(keySession, forged, forgedSig); !errors.Is(err, errUnauthenticated) {
+		t.Fatalf("unexpected error %v expected %v", err, errUnauthenticated)
+	}
+	if len(nodes[0].sessions) != 0 {
+		t.Fatal("unauthenticated response started a session")
+	}
+
+	// the deal signed into its session is accepted
+	if err = nodes[0].processDeal(keySession, deal, sessionSig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	g := newGroup(t, func([]byte) error { return nil }, 0)
+	public := g.keygen(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	msg := []byte("audit block")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+
+	sig, err := g.nodes[0].Sign(ctx, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = wallet.Verify(suite, public, msg, sig); err != nil {
+		t.Fatal(err)
+	}
+
+	// group signatures are plain ed25519 signatures
+	pb, err := public.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal group key: %v", err)
+	}
+	if !ed25519.Verify(pb, msg, sig) {
+		t.Fatal("ed25519 rejected the group signature")
+	}
+	if ed25519.Verify(pb, []byte("other block"), sig) {
+		t.Fatal("ed25519 accepted the group signature of another message")
+	}
+}
+
+func TestSignThreshold(t *testing.T) {
+	g := newGroup(t, func([]byte) error { return nil }, time.Second*3)
+	public := g.keygen(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// stopped participants leave a threshold to sign
+	g.servers[3].Stop()
+	g.servers[4].Stop()
+
+	msg := []byte("master realm")
+	sig, err := g.nodes[1].Sign(ctx, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = wallet.Verify(edwards25519.NewBlakeSHA256Ed25519(), public, msg, sig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignRejected(t *testing.T) {
+	g := newGroup(t, nil, 0)
+	g.keygen(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// the default approver rejects every request
+	_, err := g.nodes[0].Sign(ctx, []byte("master realm"))
+	if !errors.Is(err, errNotEnough) {
+		t.Fatalf("unexpected error %v expected %v", err, errNotEnough)
+	}
+}
+
+func TestSigner(t *testing.T) {
+	g := newGroup(t, DigestApprover, 0)
+	if _, err := NewSigner(g.nodes[0]); !errors.Is(err, errNoKey) {
+		t.Fatalf("unexpected error %v expected %v", err, errNoKey)
+	}
+	public := g.keygen(t)
+
+	s, err := NewSigner(g.nodes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Public().Equal(public) {
+		t.Fatal("signer public key is not the group key")
+	}
+
+	digest := sha3.Sum256([]byte("audit block"))
+	sig, err := s.Sign(digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = wallet.Verify(edwards25519.NewBlakeSHA256Ed25519(), public, digest[:], sig); err != nil {
+		t.Fatal(err)
+	}
+
+	// the other participants only sign digests
+	if _, err = s.Sign([]byte("master realm")); !errors.Is(err, errNotEnough) {
+		t.Fatalf("unexpected error %v expected %v", err, errNotEnough)
+	}
+}
+
+func TestPartialSignUnauthenticated(t *testing.T) {
+	var approved int
+	g := newGroup(t, func([]byte) error {
+		approved++
+		return nil
+	}, 0)
+	g.keygen(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	id := noncePrefix + "forged"
+	msg := []byte("master realm")
+	sig, err := g.nodes[1].signSession(kindSign, id, []byte("other realm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// requests not signed by the requester never reach the approver
+	for _, requester := range []uint32{1, testN} {
+		_, err = g.nodes[0].remotePartialSign(ctx, id, msg, requester, sig)
+		if !errors.Is(err, errUnauthenticated) {
+			t.Fatalf("unexpected error %v expected %v", err, errUnauthenticated)
+		}
+	}
+	if approved != 0 {
+		t.Fatal("unauthenticated request reached the approver")
+	}
+}
+
+func TestKeyShare(t *testing.T) {
+	g := newGroup(t, nil, 0)
+	public := g.keygen(t)
+
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	b, err := g.nodes[2].KeyShare().MarshalJSON()
+	if err != nil {
+		t.Fatalf("failed to marshal key share: %v", err)
+	}
+
+	k, err := UnmarshalKeyShare(suite, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !k.Public().Equal(public) {
+		t.Fatal("decoded key share has another group key")
+	}
+
+	// a share of one participant can not be used by another
+	w := wallet.NewV1(suite)
+	if _, err = NewNode(suite, w, []kyber.Point{w.P, w.P, w.P}, 2, WithKeyShare(k)); err == nil {
+		t.Fatal("expected error for key share of another participant")
+	}
+}
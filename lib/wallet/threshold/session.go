@@ -0,0 +1,163 @@
+package threshold
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pedersen "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+// session key generation run between the participants,
+// either the long term group key or a single use nonce
+type session struct {
+	id      string
+	created time.Time
+
+	// mu guards dkg, the generator is not safe for concurrent use
+	mu        sync.Mutex
+	dkg       *pedersen.DistKeyGenerator
+	share     *pedersen.DistKeyShare
+	certified chan struct{}
+
+	// dealers whose deal was processed, responses to
+	// any other deal are held until the deal arrives
+	dealers    map[uint32]bool
+	pending    []*pedersen.Response
+	maxPending int
+}
+
+func newSession(id string, index, n int, dkg *pedersen.DistKeyGenerator) *session {
+	return &session{
+		// every participant responds to every deal
+		maxPending: n * n,
+		id:         id,
+		created:    time.Now(),
+		dkg:        dkg,
+		certified:  make(chan struct{}),
+		dealers:    map[uint32]bool{uint32(index): true},
+	}
+}
+
+// processDeal verify a deal addressed to this node and
+// return the response to broadcast to every participant
+func (s *session) processDeal(d *pedersen.Deal) (*pedersen.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp, err := s.dkg.ProcessDeal(d)
+	if err != nil {
+		return nil, err
+	}
+	s.dealers[d.Index] = true
+
+	// responses that arrived ahead of the deal
+	pending := s.pending
+	s.pending = nil
+	for _, r := range pending {
+		err = s.addResponse(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, s.checkCertified()
+}
+
+// processResponse verify and store the response of a participant
+//
+// complaints are not justified, a deal with a complaint is
+// left out of the key once the session times out
+func (s *session) processResponse(r *pedersen.Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.addResponse(r)
+	if err != nil {
+		return err
+	}
+
+	return s.checkCertified()
+}
+
+// addResponse process r or hold it until its deal arrives, must hold mu
+func (s *session) addResponse(r *pedersen.Response) error {
+	if !s.dealers[r.Index] {
+		if len(s.pending) >= s.maxPending {
+			return errTooManyPending
+		}
+		s.pending = append(s.pending, r)
+		return nil
+	}
+
+	j, err := s.dkg.ProcessResponse(r)
+	if err != nil {
+		return err
+	}
+	if j != nil {
+		return errComplaint
+	}
+
+	return nil
+}
+
+// checkCertified finish once every deal is certified, must hold mu
+func (s *session) checkCertified() error {
+	if s.share != nil || !s.dkg.Certified() {
+		return nil
+	}
+	return s.finish()
+}
+
+// finish compute the distributed key share, must hold mu
+func (s *session) finish() error {
+	share, err := s.dkg.DistKeyShare()
+	if err != nil {
+		return err
+	}
+
+	s.share = share
+	close(s.certified)
+
+	return nil
+}
+
+// wait distributed key share once every deal is certified
+//
+// when timeout is set and expires before, the missing responses
+// are treated as complaints and the share is computed from the
+// deals certified by a threshold of participants
+func (s *session) wait(ctx context.Context, timeout time.Duration) (*pedersen.DistKeyShare, error) {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout - time.Since(s.created))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.certified:
+	case <-expired:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.share != nil {
+		return s.share, nil
+	}
+
+	s.dkg.SetTimeout()
+	if !s.dkg.ThresholdCertified() {
+		return nil, errNotCertified
+	}
+
+	err := s.finish()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.share, nil
+}
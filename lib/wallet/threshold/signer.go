@@ -0,0 +1,56 @@
+package threshold
+
+import (
+	"context"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+// digestSize size of the transaction and header digests
+const digestSize = 32
+
+// Signer wallet.Signer of the group key, every signature is
+// a signing round with a threshold of the participants
+type Signer struct {
+	node *Node
+	key  *KeyShare
+}
+
+var _ wallet.Signer = (*Signer)(nil)
+
+// NewSigner return signer of the group key generated by n
+func NewSigner(n *Node) (*Signer, error) {
+	key := n.KeyShare()
+	if key == nil {
+		return nil, errNoKey
+	}
+
+	return &Signer{node: n, key: key}, nil
+}
+
+// Public group public key
+func (s *Signer) Public() kyber.Point {
+	return s.key.Public()
+}
+
+// Sign group signature of message, bounded by the time to generate
+// the nonce and collect partial signatures from the participants
+func (s *Signer) Sign(message []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.node.timeout*2)
+	defer cancel()
+
+	return s.node.Sign(ctx, message)
+}
+
+// DigestApprover approve requests to sign 32 byte digests only, the
+// signed hashes of transactions and block headers, so a group key
+// dedicated to them never signs a message meant for anything else
+func DigestApprover(message []byte) error {
+	if len(message) != digestSize {
+		return fmt.Errorf("message of %d bytes is not a digest", len(message))
+	}
+	return nil
+}
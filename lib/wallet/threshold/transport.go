@@ -0,0 +1,101 @@
+package threshold
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"buf.build/go/protovalidate"
+	"google.golang.org/grpc"
+
+	"github.com/trevatk/tbd/lib/protocol"
+	pb "github.com/trevatk/tbd/lib/protocol/threshold/v1"
+)
+
+type transport struct {
+	pb.UnimplementedThresholdServiceServer
+
+	logger *slog.Logger
+	node   *Node
+}
+
+// NewTransport return threshold gateway transport implementation
+func NewTransport(logger *slog.Logger, node *Node) (*grpc.ServiceDesc, pb.ThresholdServiceServer) {
+	return &pb.ThresholdService_ServiceDesc,
+		&transport{
+			logger: logger,
+			node:   node,
+		}
+}
+
+// Deal
+func (t *transport) Deal(ctx context.Context, in *pb.DealRequest) (*pb.DealResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	err := t.node.processDeal(in.SessionId, dealFromProto(in), in.SessionSignature)
+	if err != nil {
+		return nil, t.sessionErr(ctx, "failed to process deal", err)
+	}
+
+	return &pb.DealResponse{}, nil
+}
+
+// Respond
+func (t *transport) Respond(ctx context.Context, in *pb.RespondRequest) (*pb.RespondResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	err := t.node.processResponse(in.SessionId, responseFromProto(in), in.SessionSignature)
+	if err != nil {
+		return nil, t.sessionErr(ctx, "failed to process response", err)
+	}
+
+	return &pb.RespondResponse{}, nil
+}
+
+// PartialSign
+func (t *transport) PartialSign(ctx context.Context, in *pb.PartialSignRequest) (*pb.PartialSignResponse, error) {
+	if err := protovalidate.Validate(in); err != nil {
+		return nil, protocol.ErrInvalidArgument()
+	}
+
+	ps, err := t.node.remotePartialSign(ctx, in.SessionId, in.Message, in.Requester, in.Signature)
+	if errors.Is(err, errRejected) || errors.Is(err, errUnauthenticated) {
+		t.logger.WarnContext(ctx, "rejected partial signature request", "session_id", in.SessionId, "error", err)
+		return nil, protocol.ErrPermissionDenied()
+	} else if errors.Is(err, errNotCertified) || errors.Is(err, context.DeadlineExceeded) {
+		return nil, protocol.ErrUnavailable()
+	} else if err != nil {
+		return nil, t.sessionErr(ctx, "failed to partially sign", err)
+	}
+
+	resp, err := partialToProto(ps)
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to encode partial signature", "error", err)
+		return nil, protocol.ErrInternal()
+	}
+
+	return resp, nil
+}
+
+// sessionErr map session errors to status errors, any other
+// error is a deal or response that failed verification
+func (t *transport) sessionErr(ctx context.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, errUnauthenticated):
+		t.logger.WarnContext(ctx, msg, "error", err)
+		return protocol.ErrPermissionDenied()
+	case errors.Is(err, errInvalidSession):
+		return protocol.ErrInvalidArgument()
+	case errors.Is(err, errTooManySessions):
+		return protocol.ErrUnavailable()
+	case errors.Is(err, errNoKey), errors.Is(err, errNonceUsed):
+		return protocol.ErrFailedPrecondition()
+	}
+
+	t.logger.WarnContext(ctx, msg, "error", err)
+	return protocol.ErrInvalidArgument()
+}
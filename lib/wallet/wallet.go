@@ -1,28 +1,27 @@
 package wallet
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/util/random"
 )
 
-// ErrNotExists path does not exist
-var ErrNotExists = errors.New("path does not exist")
+var (
+	// ErrNotExists path does not exist
+	ErrNotExists = errors.New("path does not exist")
+	// ErrInvalidSignature signature does not match message and public key
+	ErrInvalidSignature = errors.New("invalid signature")
+)
 
 // Suite crpyto suite
 type Suite interface {
 	kyber.Group
 	kyber.Encoding
 	kyber.XOFFactory
-}
-
-type basicSig struct {
-	C kyber.Scalar // challenge
-	R kyber.Scalar // response
+	kyber.Random
 }
 
 // Wallet ...
@@ -31,13 +30,10 @@ type Wallet struct {
 	P kyber.Point  // public key
 }
 
-// NewV1 return new wallet v1
+// NewV1 return new wallet v1 with a private key
+// picked from crypto/rand
 func NewV1(suite Suite) Wallet {
-	// TODO
-	// generate randmom using crypto
-	rand := suite.XOF([]byte("example"))
-
-	x := suite.Scalar().Pick(rand)
+	x := suite.Scalar().Pick(random.New())
 	X := suite.Point().Mul(x, nil)
 
 	return Wallet{
@@ -46,109 +42,55 @@ func NewV1(suite Suite) Wallet {
 	}
 }
 
-// Import from file
-func Import(suite Suite, filePath string) (Wallet, error) {
-	fp := filepath.Clean(filePath)
-	f, err := os.OpenFile(fp, os.O_RDONLY, os.ModePerm)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return Wallet{}, ErrNotExists
-		}
-		return Wallet{}, fmt.Errorf("failed to open file: %s %w", fp, err)
-	}
-	defer func() { _ = f.Close() }()
-
-	dec := json.NewDecoder(f)
-	dec.DisallowUnknownFields()
-
-	var export exportWallet
-	err = dec.Decode(&export)
-	if err != nil {
-		return Wallet{}, fmt.Errorf("failed to deocde file to export wallet: %w", err)
-	}
-
-	var P kyber.Point = suite.Point()
-	err = P.UnmarshalBinary(export.Pu)
-	if err != nil {
-		return Wallet{}, fmt.Errorf("failed to unmarshal public key: %w", err)
-	}
-
-	var p kyber.Scalar = suite.Scalar()
-	err = p.UnmarshalBinary(export.Pr)
+// FromPrivateKey wallet of binary encoded private key
+func FromPrivateKey(suite Suite, key []byte) (Wallet, error) {
+	x := suite.Scalar()
+	err := x.UnmarshalBinary(key)
 	if err != nil {
 		return Wallet{}, fmt.Errorf("failed to unmarshal private key: %w", err)
 	}
 
 	return Wallet{
-		p: p,
-		P: P,
+		p: x,
+		P: suite.Point().Mul(x, nil),
 	}, nil
 }
 
-// Addr generate wallet address
-func (w Wallet) Addr() (string, error) {
-	return "", nil
-}
-
-// Sign hashSchnorrV1 signature
-func (w Wallet) Sign(suite Suite, message []byte) (kyber.Scalar, error) {
-	// temp fix unused basic signature
-	_ = basicSig{}
-
-	pb, err := w.P.MarshalBinary()
-	if err != nil {
-		return nil, fmt.Errorf("kyber.Point marshal binary: %w", err)
-	}
-
-	c := suite.XOF(pb)
-	_, err = c.Write(message)
-	if err != nil {
-		return nil, fmt.Errorf("c.Write: %w", err)
-	}
-
-	return suite.Scalar().Pick(c), nil
-}
-
-// export wallet is used when import/export wallet
-// from file
-type exportWallet struct {
-	Pu []byte `json:"public_key"`
-	Pr []byte `json:"private_key"`
-}
-
-// Export to file
-func (w Wallet) Export(filePath string) error {
-	Pb, err := w.P.MarshalBinary()
-	if err != nil {
-		return fmt.Errorf("failed to marshal public key: %w", err)
-	}
-
+// PrivateKey binary encoded private key
+func (w Wallet) PrivateKey() ([]byte, error) {
 	pb, err := w.p.MarshalBinary()
 	if err != nil {
-		return fmt.Errorf("failed to marshal private key: %w", err)
-	}
-
-	ew := exportWallet{
-		Pu: Pb,
-		Pr: pb,
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
 	}
+	return pb, nil
+}
 
-	eb, err := json.Marshal(&ew)
+// Addr encoded wallet address on network
+func (w Wallet) Addr(network Network) (string, error) {
+	a, err := NewAddress(network, w.P)
 	if err != nil {
-		return fmt.Errorf("failed to marshal export wallet: %w", err)
+		return "", err
 	}
+	return a.String(), nil
+}
 
-	fp := filepath.Clean(filePath)
-	f, err := os.OpenFile(fp, os.O_CREATE|os.O_RDWR, os.ModePerm)
+// Sign schnorr signature of message
+//
+// the signature is R || s with a random nonce, over the
+// edwards25519 group it is a valid EdDSA signature
+func (w Wallet) Sign(suite Suite, message []byte) ([]byte, error) {
+	sig, err := schnorr.Sign(suite, w.p, message)
 	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+		return nil, fmt.Errorf("schnorr.Sign: %w", err)
 	}
-	defer func() { _ = f.Close() }()
+	return sig, nil
+}
 
-	_, err = f.Write(eb)
+// Verify schnorr signature of message by public key P
+func Verify(suite Suite, P kyber.Point, message, sig []byte) error {
+	err := schnorr.Verify(suite, P, message, sig)
 	if err != nil {
-		return fmt.Errorf("failed to write wallet bytes to file: %w", err)
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
 	}
-
 	return nil
 }
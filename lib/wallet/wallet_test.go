@@ -1,79 +1,132 @@
 package wallet
 
 import (
+	"crypto/ed25519"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
 	"testing"
 
 	"go.dedis.ch/kyber/v4/group/edwards25519"
 	"go.dedis.ch/kyber/v4/xof/blake2xb"
 )
 
-const (
-	dir      = "testfiles"
-	filePath = dir + "/wallet.json"
-)
-
-func setupTest() error {
-	err := os.Mkdir(dir, os.ModePerm)
-	if err != nil && !errors.Is(err, os.ErrExist) {
-		return fmt.Errorf("failed to create testfiles dir: %w", err)
-	}
-	return nil
-}
-
-func teardownTest() error {
-	err := os.RemoveAll(dir)
-	if err != nil && !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("failed to remove all: %w", err)
-	}
-	return nil
-}
-
-func TestExport(t *testing.T) {
-	err := setupTest()
-	if err != nil {
-		t.Fatalf("failed to setup test: %v", err)
-	}
-	defer func() { _ = teardownTest() }()
-
-	rng := blake2xb.New(nil)
-	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(rng)
-	w := NewV1(suite)
-	err = w.Export(filePath)
-	if err != nil {
-		t.Fatalf("failed to export wallet: %v", err)
-	}
-}
-
-func TestImport(t *testing.T) {
-	err := setupTest()
-	if err != nil {
-		t.Fatalf("failed to setup test: %v", err)
-	}
-	defer func() { _ = teardownTest() }()
-
+func TestVerify(t *testing.T) {
 	rng := blake2xb.New(nil)
 	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(rng)
 
 	w := NewV1(suite)
-	err = w.Export(filePath)
+	msg := []byte("message")
+
+	sig, err := w.Sign(suite, msg)
 	if err != nil {
-		t.Fatalf("failed to export file: %v", err)
+		t.Fatalf("failed to sign message: %v", err)
 	}
 
 	t.Run("success", func(t *testing.T) {
-		_, err = Import(suite, filePath)
+		err = Verify(suite, w.P, msg, sig)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
-	t.Run("not found", func(t *testing.T) {
-		expected := ErrNotExists
-		_, err = Import(suite, filePath+"2")
+	t.Run("tampered", func(t *testing.T) {
+		expected := ErrInvalidSignature
+		err = Verify(suite, w.P, []byte("tampered"), sig)
 		if !errors.Is(err, expected) {
 			t.Fatalf("unexpected error %v expected %v", err, expected)
 		}
 	})
 }
+
+// rfc 8032 section 7.1 ed25519 test vectors
+var rfc8032Vectors = []struct {
+	seed, public, message, sig string
+}{
+	{
+		seed:    "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		public:  "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		message: "",
+		sig:     "e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b",
+	},
+	{
+		seed:    "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+		public:  "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		message: "72",
+		sig:     "92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00",
+	},
+	{
+		seed:    "c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+		public:  "fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		message: "af82",
+		sig:     "6291d657deec24024827e69c3abe01a30ce548a284743a445e3680d7db5ac3ac18ff9b538d16f290ae67f760984dc6594a7c15e9716ed28dc027beceea1ec40a",
+	},
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("failed to decode %q: %v", s, err)
+	}
+	return b
+}
+
+func TestVerifyVectors(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+
+	for i, v := range rfc8032Vectors {
+		t.Run(fmt.Sprintf("vector_%d", i+1), func(t *testing.T) {
+			P := suite.Point()
+			err := P.UnmarshalBinary(decodeHex(t, v.public))
+			if err != nil {
+				t.Fatalf("failed to unmarshal public key: %v", err)
+			}
+
+			msg := decodeHex(t, v.message)
+			sig := decodeHex(t, v.sig)
+
+			err = Verify(suite, P, msg, sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sig[0] ^= 1
+			err = Verify(suite, P, msg, sig)
+			if !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("unexpected error %v expected %v", err, ErrInvalidSignature)
+			}
+		})
+	}
+}
+
+func TestSignVectors(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+
+	for i, v := range rfc8032Vectors {
+		t.Run(fmt.Sprintf("vector_%d", i+1), func(t *testing.T) {
+			w := fromEd25519Seed(suite, decodeHex(t, v.seed))
+
+			Pb, err := w.P.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal public key: %v", err)
+			}
+			if hex.EncodeToString(Pb) != v.public {
+				t.Fatalf("unexpected public key %x expected %s", Pb, v.public)
+			}
+
+			msg := decodeHex(t, v.message)
+			sig, err := w.Sign(suite, msg)
+			if err != nil {
+				t.Fatalf("failed to sign message: %v", err)
+			}
+			if len(sig) != 64 {
+				t.Fatalf("unexpected signature length %d", len(sig))
+			}
+
+			// nonce is random, signatures must still verify as ed25519
+			if !ed25519.Verify(ed25519.PublicKey(Pb), msg, sig) {
+				t.Fatal("signature rejected by crypto/ed25519")
+			}
+		})
+	}
+}
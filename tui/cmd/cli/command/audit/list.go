@@ -3,33 +3,56 @@ package audit
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc/codes"
+
+	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
 )
 
 var (
-	limit  int64
-	offset int64
+	limit    int64
+	subject  string
+	resource string
+	outcome  string
+
+	headerStyle   = lipgloss.NewStyle().Bold(true).Padding(0, 1)
+	cellStyle     = lipgloss.NewStyle().Padding(0, 1)
+	selectedStyle = cellStyle.Reverse(true)
+	helpStyle     = lipgloss.NewStyle().Faint(true)
 )
 
 type (
-	listStatusMsg uint32
+	pageMsg struct {
+		decisions []*pb.DecisionRecord
+		next      string
+	}
 
 	listModel struct {
-		ctx        context.Context
-		serverAddr string
+		ctx    context.Context
+		client pb.AuditServiceClient
+		filter *pb.DecisionFilter
 
-		statusCode codes.Code
-		err        error
+		// cursors cursor of every page visited, the last is the current page
+		cursors   []string
+		next      string
+		decisions []*pb.DecisionRecord
+		selected  int
+
+		loading bool
+		err     error
 	}
 
 	errMsg struct{ error }
 )
 
 func (m listModel) Init() tea.Cmd {
-	return listTxs(m.ctx, m.serverAddr, limit, offset)
+	return m.fetch()
 }
 
 func (m listModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -38,13 +61,37 @@ func (m listModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "q", "ctrl+c", "esc":
 			return m, tea.Quit
+		case "up", "k":
+			m.selected = max(m.selected-1, 0)
+			return m, nil
+		case "down", "j":
+			m.selected = min(m.selected+1, max(len(m.decisions)-1, 0))
+			return m, nil
+		case "right", "n":
+			if m.loading || m.next == "" {
+				return m, nil
+			}
+			m.cursors = append(m.cursors, m.next)
+			return m, m.fetch()
+		case "left", "p":
+			if m.loading || len(m.cursors) == 1 {
+				return m, nil
+			}
+			m.cursors = m.cursors[:len(m.cursors)-1]
+			return m, m.fetch()
 		default:
 			return m, nil
 		}
-	case listStatusMsg:
-		m.statusCode = codes.Code(msg)
-		return m, tea.Quit
+
+	case pageMsg:
+		m.loading = false
+		m.decisions = msg.decisions
+		m.next = msg.next
+		m.selected = 0
+		return m, nil
+
 	case errMsg:
+		m.loading = false
 		m.err = msg
 		return m, nil
 
@@ -54,36 +101,114 @@ func (m listModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m listModel) View() string {
-	s := "list transactions..."
 	if m.err != nil {
-		s += fmt.Sprintf("error occured %s", m.err)
-	} else if m.statusCode != codes.OK {
-		s += fmt.Sprintf("%d %s", m.statusCode, codes.Code(m.statusCode).String())
+		return fmt.Sprintf("error occured %s\n", m.err)
 	}
-	return s
+	if m.decisions == nil {
+		return "list decisions...\n"
+	}
+
+	rows := make([][]string, 0, len(m.decisions))
+	for _, d := range m.decisions {
+		rows = append(rows, []string{
+			d.Timestamp.AsTime().Local().Format(time.DateTime),
+			d.Subject,
+			d.Resource,
+			d.Action,
+			strings.TrimPrefix(d.Outcome.String(), "OUTCOME_"),
+			strconv.FormatInt(d.Height, 10),
+		})
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		Headers("TIME", "SUBJECT", "RESOURCE", "ACTION", "OUTCOME", "HEIGHT").
+		Rows(rows...).
+		StyleFunc(func(row, _ int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return headerStyle
+			case row == m.selected:
+				return selectedStyle
+			default:
+				return cellStyle
+			}
+		})
+
+	var b strings.Builder
+	b.WriteString(t.Render())
+	b.WriteString("\n")
+
+	if len(m.decisions) > 0 {
+		b.WriteString(fmt.Sprintf("tx %s request %s\n", m.decisions[m.selected].TxHash, m.decisions[m.selected].RequestId))
+	}
+
+	b.WriteString(helpStyle.Render(fmt.Sprintf("page %d  ←/p prev  →/n next  ↑/↓ select  q quit", len(m.cursors))))
+	b.WriteString("\n")
+
+	return b.String()
 }
 
-func listTxs(_ context.Context, _ string, _, _ int64) tea.Cmd {
+// fetch page at the current cursor
+func (m *listModel) fetch() tea.Cmd {
+	m.loading = true
+
+	ctx, client, filter := m.ctx, m.client, m.filter
+	cursor := m.cursors[len(m.cursors)-1]
+
 	return func() tea.Msg {
-		// client, err := audit.NewClient(addr)
-		// if err != nil {
-		// 	return errMsg{err}
-		// }
-
-		// txs, err := client.List(ctx, limit, offset)
-		// if err != nil {
-		// 	return errMsg{err}
-		// }
-		// fmt.Println(txs)
-		return listStatusMsg(codes.OK)
+		resp, err := client.ListDecisions(ctx, &pb.ListDecisionsRequest{
+			Limit:  limit,
+			Cursor: cursor,
+			Filter: filter,
+		})
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return pageMsg{decisions: resp.Decisions, next: resp.NextCursor}
+	}
+}
+
+func newFilter() (*pb.DecisionFilter, error) {
+	f := &pb.DecisionFilter{
+		Subject:  subject,
+		Resource: resource,
+	}
+
+	switch outcome {
+	case "":
+	case "allow":
+		f.Outcome = pb.Outcome_OUTCOME_ALLOW
+	case "deny":
+		f.Outcome = pb.Outcome_OUTCOME_DENY
+	default:
+		return nil, fmt.Errorf("invalid outcome %q, expected allow or deny", outcome)
 	}
+
+	return f, nil
 }
 
 var listCmd = &cobra.Command{
 	Use: "list",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		p := tea.NewProgram(listModel{ctx: cmd.Context(), serverAddr: "localhost:8000"})
-		_, err := p.Run()
+		filter, err := newFilter()
+		if err != nil {
+			return err
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		p := tea.NewProgram(listModel{
+			ctx:     cmd.Context(),
+			client:  client,
+			filter:  filter,
+			cursors: []string{""},
+		})
+		_, err = p.Run()
 		if err != nil {
 			return fmt.Errorf("failed to run program: %w", err)
 		}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
+)
+
+var (
+	proofCmd = &cobra.Command{
+		Use:   "proof [tx hash]",
+		Short: "print the merkle inclusion proof of a transaction",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			proof, err := client.GetProof(cmd.Context(), &pb.GetProofRequest{TxHash: args[0]})
+			if err != nil {
+				return fmt.Errorf("failed to get proof: %w", err)
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), protojson.Format(proof))
+			return err
+		},
+	}
+
+	headerCmd = &cobra.Command{
+		Use:   "header [height]",
+		Short: "print the signed block header at height, the tip when omitted",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var height int64
+			if len(args) == 1 {
+				var err error
+				height, err = strconv.ParseInt(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid height %q: %w", args[0], err)
+				}
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			header, err := client.GetBlockHeader(cmd.Context(), &pb.GetBlockHeaderRequest{Height: height})
+			if err != nil {
+				return fmt.Errorf("failed to get block header: %w", err)
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), protojson.Format(header))
+			return err
+		},
+	}
+)
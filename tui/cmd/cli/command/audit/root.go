@@ -1,16 +1,47 @@
 package audit
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/structx/tbd/tui/cmd/cli/command"
+	"github.com/trevatk/tbd/lib/protocol"
+
+	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
 )
 
-var auditCmd = &cobra.Command{
-	Use: "audit",
-}
+var (
+	serverAddr string
+
+	auditCmd = &cobra.Command{
+		Use: "audit",
+	}
+)
 
 func init() {
-	auditCmd.AddCommand(listCmd)
+	auditCmd.PersistentFlags().StringVarP(&serverAddr, "server", "s", "localhost:8080", "audit server address")
+
+	listCmd.Flags().Int64VarP(&limit, "limit", "l", 20, "decisions per page")
+	listCmd.Flags().StringVar(&subject, "subject", "", "filter by subject")
+	listCmd.Flags().StringVar(&resource, "resource", "", "filter by resource")
+	listCmd.Flags().StringVar(&outcome, "outcome", "", "filter by outcome allow or deny")
+
+	verifyCmd.Flags().StringVarP(&proofFile, "proof", "p", "", "proof json file")
+	verifyCmd.Flags().StringVar(&headerFile, "header", "", "published block header json file")
+	verifyCmd.Flags().StringVar(&publicKey, "public-key", "", "trusted signer public key hex")
+	_ = verifyCmd.MarkFlagRequired("proof")
+	_ = verifyCmd.MarkFlagRequired("header")
+	_ = verifyCmd.MarkFlagRequired("public-key")
+
+	auditCmd.AddCommand(listCmd, proofCmd, headerCmd, verifyCmd)
 	command.RootCmd.AddCommand(auditCmd)
 }
+
+func newClient() (pb.AuditServiceClient, error) {
+	conn, err := protocol.NewConn(serverAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", serverAddr, err)
+	}
+	return pb.NewAuditServiceClient(conn), nil
+}
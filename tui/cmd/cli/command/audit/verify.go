@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/trevatk/tbd/lib/protocol/audit/v1"
+	"github.com/trevatk/tbd/lib/protocol/ledger"
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+var (
+	proofFile  string
+	headerFile string
+	publicKey  string
+
+	verifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "verify a proof offline against a published block header",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var proof pb.Proof
+			err := readJSON(proofFile, &proof)
+			if err != nil {
+				return err
+			}
+
+			var header pb.BlockHeader
+			err = readJSON(headerFile, &header)
+			if err != nil {
+				return err
+			}
+
+			// the header names its signer, only the trusted key is accepted
+			if header.Signer != publicKey {
+				return fmt.Errorf("header rejected: signed by %s not %s", header.Signer, publicKey)
+			}
+
+			err = ledger.VerifyProof(&proof, &header)
+			if err != nil {
+				return fmt.Errorf("proof rejected: %w", err)
+			}
+
+			err = verifyHeaderSig(&header, publicKey)
+			if err != nil {
+				return fmt.Errorf("header rejected: %w", err)
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "tx %s sealed in block %s at height %d\n", proof.TxHash, header.Hash, header.Height)
+			return err
+		},
+	}
+)
+
+// verifyHeaderSig check the header hash is signed by the trusted key
+func verifyHeaderSig(h *pb.BlockHeader, trusted string) error {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+
+	kb, err := hex.DecodeString(trusted)
+	if err != nil {
+		return fmt.Errorf("invalid public key %q", trusted)
+	}
+	signer := suite.Point()
+	err = signer.UnmarshalBinary(kb)
+	if err != nil {
+		return fmt.Errorf("invalid signer: %w", err)
+	}
+
+	sb, err := hex.DecodeString(h.Sig)
+	if err != nil {
+		return fmt.Errorf("invalid signature %q", h.Sig)
+	}
+
+	hb, _ := hex.DecodeString(h.Hash)
+	return wallet.Verify(suite, signer, hb, sb)
+}
+
+func readJSON(path string, m proto.Message) error {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	err = protojson.Unmarshal(b, m)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	return nil
+}
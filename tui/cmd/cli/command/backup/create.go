@@ -0,0 +1,36 @@
+package backup
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/structx/tbd/tui/internal/pkg/logging"
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+var (
+	createCmd = &cobra.Command{
+		Use: "create",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := keyvalue.CheckpointDir(dataDir, outDir)
+			if err != nil {
+				return fmt.Errorf("failed to checkpoint %s: %w", dataDir, err)
+			}
+
+			manifest, err := keyvalue.VerifyCheckpoint(outDir)
+			if err != nil {
+				return fmt.Errorf("failed to verify checkpoint: %w", err)
+			}
+
+			logging.FromContext(cmd.Context()).Info("checkpoint created...",
+				"dir", outDir,
+				"seq", manifest.Seq,
+				"sstables", len(manifest.Sstables),
+				"wals", len(manifest.Wals),
+			)
+
+			return nil
+		},
+	}
+)
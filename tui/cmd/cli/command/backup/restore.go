@@ -0,0 +1,40 @@
+package backup
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/structx/tbd/tui/internal/pkg/logging"
+	"github.com/trevatk/tbd/lib/keyvalue"
+)
+
+var (
+	restoreCmd = &cobra.Command{
+		Use: "restore",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := keyvalue.Restore(outDir, dataDir)
+			if err != nil {
+				return fmt.Errorf("failed to restore %s: %w", outDir, err)
+			}
+
+			logging.FromContext(cmd.Context()).Info("checkpoint restored...", "data_dir", dataDir)
+
+			return nil
+		},
+	}
+
+	verifyCmd = &cobra.Command{
+		Use: "verify",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, err := keyvalue.VerifyCheckpoint(outDir)
+			if err != nil {
+				return fmt.Errorf("failed to verify %s: %w", outDir, err)
+			}
+
+			logging.FromContext(cmd.Context()).Info("checkpoint verified...", "dir", outDir, "seq", manifest.Seq)
+
+			return nil
+		},
+	}
+)
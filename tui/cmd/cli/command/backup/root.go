@@ -0,0 +1,32 @@
+package backup
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/structx/tbd/tui/cmd/cli/command"
+)
+
+var (
+	dataDir string
+	outDir  string
+
+	backupCmd = &cobra.Command{
+		Use: "backup",
+	}
+)
+
+func init() {
+	createCmd.Flags().StringVarP(&dataDir, "data-dir", "d", "data", "key value data directory")
+	createCmd.Flags().StringVarP(&outDir, "out", "o", "", "checkpoint directory, must not exist")
+	_ = createCmd.MarkFlagRequired("out")
+
+	restoreCmd.Flags().StringVarP(&dataDir, "data-dir", "d", "data", "key value data directory, must not exist")
+	restoreCmd.Flags().StringVarP(&outDir, "from", "f", "", "checkpoint directory")
+	_ = restoreCmd.MarkFlagRequired("from")
+
+	verifyCmd.Flags().StringVarP(&outDir, "from", "f", "", "checkpoint directory")
+	_ = verifyCmd.MarkFlagRequired("from")
+
+	backupCmd.AddCommand(createCmd, restoreCmd, verifyCmd)
+	command.RootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,33 @@
+package kv
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/structx/tbd/tui/internal/pkg/logging"
+
+	pb "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
+)
+
+var (
+	compactCmd = &cobra.Command{
+		Use:  "compact",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			_, err = client.Compact(cmd.Context(), &pb.CompactRequest{})
+			if err != nil {
+				return fmt.Errorf("failed to compact: %w", err)
+			}
+
+			logging.FromContext(cmd.Context()).Info("store compacted...")
+
+			return nil
+		},
+	}
+)
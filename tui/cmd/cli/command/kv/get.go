@@ -0,0 +1,30 @@
+package kv
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
+)
+
+var (
+	getCmd = &cobra.Command{
+		Use:  "get [key]",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.Get(cmd.Context(), &pb.GetRequest{Key: args[0]})
+			if err != nil {
+				return fmt.Errorf("failed to get %s: %w", args[0], err)
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(resp.Entry.Value))
+			return err
+		},
+	}
+)
@@ -0,0 +1,43 @@
+package kv
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/structx/tbd/tui/internal/pkg/logging"
+
+	pb "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
+)
+
+var (
+	ttl    int64
+	indice map[string]string
+
+	putCmd = &cobra.Command{
+		Use:  "put [key] [value]",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			_, err = client.Put(cmd.Context(), &pb.PutRequest{
+				Entry: &pb.Entry{
+					Key:    args[0],
+					Value:  []byte(args[1]),
+					Indice: indice,
+					Ttl:    ttl,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to put %s: %w", args[0], err)
+			}
+
+			logging.FromContext(cmd.Context()).Info("key stored...", "key", args[0])
+
+			return nil
+		},
+	}
+)
@@ -0,0 +1,45 @@
+package kv
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/structx/tbd/tui/cmd/cli/command"
+	"github.com/trevatk/tbd/lib/protocol"
+
+	pb "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
+)
+
+var (
+	serverAddr string
+
+	kvCmd = &cobra.Command{
+		Use: "kv",
+	}
+)
+
+func init() {
+	kvCmd.PersistentFlags().StringVarP(&serverAddr, "server", "s", "localhost:8080", "key value server address")
+
+	putCmd.Flags().Int64VarP(&ttl, "ttl", "t", -1, "time to live in seconds, -1 never expires")
+	putCmd.Flags().StringToStringVarP(&indice, "index", "i", nil, "secondary index name=value")
+
+	scanCmd.Flags().StringVarP(&prefix, "prefix", "p", "", "key prefix")
+	scanCmd.Flags().StringVar(&indexName, "index-name", "", "secondary index name")
+	scanCmd.Flags().StringVar(&indexValue, "index-value", "", "secondary index value")
+	scanCmd.Flags().Int64VarP(&limit, "limit", "l", 0, "maximum records, 0 for all")
+
+	rotateKeyCmd.Flags().BoolVar(&reencrypt, "compact", true, "compact so existing data is re-encrypted with the new key")
+
+	kvCmd.AddCommand(getCmd, putCmd, scanCmd, compactCmd, rotateKeyCmd)
+	command.RootCmd.AddCommand(kvCmd)
+}
+
+func newClient() (pb.KeyValueServiceClient, error) {
+	conn, err := protocol.NewConn(serverAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", serverAddr, err)
+	}
+	return pb.NewKeyValueServiceClient(conn), nil
+}
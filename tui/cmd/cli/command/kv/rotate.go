@@ -0,0 +1,35 @@
+package kv
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/structx/tbd/tui/internal/pkg/logging"
+
+	pb "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
+)
+
+var (
+	reencrypt bool
+
+	rotateKeyCmd = &cobra.Command{
+		Use:  "rotate-key",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			_, err = client.RotateKey(cmd.Context(), &pb.RotateKeyRequest{Compact: reencrypt})
+			if err != nil {
+				return fmt.Errorf("failed to rotate data key: %w", err)
+			}
+
+			logging.FromContext(cmd.Context()).Info("data key rotated...", "reencrypted", reencrypt)
+
+			return nil
+		},
+	}
+)
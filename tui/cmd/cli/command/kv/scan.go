@@ -0,0 +1,52 @@
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/trevatk/tbd/lib/protocol/keyvalue/v1"
+)
+
+var (
+	prefix     string
+	indexName  string
+	indexValue string
+	limit      int64
+
+	scanCmd = &cobra.Command{
+		Use: "scan",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			stream, err := client.Scan(cmd.Context(), &pb.ScanRequest{
+				Prefix:     prefix,
+				IndexName:  indexName,
+				IndexValue: indexValue,
+				Limit:      limit,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to scan: %w", err)
+			}
+
+			for {
+				resp, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					return nil
+				} else if err != nil {
+					return fmt.Errorf("failed to receive: %w", err)
+				}
+
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", resp.Entry.Key, resp.Entry.Value)
+				if err != nil {
+					return err
+				}
+			}
+		},
+	}
+)
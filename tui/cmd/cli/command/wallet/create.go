@@ -0,0 +1,48 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+var (
+	createCmd = &cobra.Command{
+		Use:   "create",
+		Short: "create a random wallet and write it to an encrypted keystore",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := checkOverwrite()
+			if err != nil {
+				return err
+			}
+
+			password, err := readPassword(cmd, true)
+			if err != nil {
+				return err
+			}
+
+			w := wallet.NewV1(edwards25519.NewBlakeSHA256Ed25519())
+			return writeKeystore(cmd, w, password)
+		},
+	}
+)
+
+// writeKeystore export w to the keystore and print its address
+func writeKeystore(cmd *cobra.Command, w wallet.Wallet, password []byte) error {
+	addr, err := w.Addr(wallet.Network(network))
+	if err != nil {
+		return fmt.Errorf("failed to create address: %w", err)
+	}
+
+	err = w.Export(keystorePath, password)
+	if err != nil {
+		return fmt.Errorf("failed to export wallet: %w", err)
+	}
+
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "wallet %s written to %s\n", addr, keystorePath)
+	return err
+}
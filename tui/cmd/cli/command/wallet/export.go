@@ -0,0 +1,38 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+var (
+	exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "decrypt the keystore and print the hex private key",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			password, err := readPassword(cmd, false)
+			if err != nil {
+				return err
+			}
+
+			w, err := wallet.Import(edwards25519.NewBlakeSHA256Ed25519(), keystorePath, password)
+			if err != nil {
+				return fmt.Errorf("failed to import wallet: %w", err)
+			}
+
+			key, err := w.PrivateKey()
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), hex.EncodeToString(key))
+			return err
+		},
+	}
+)
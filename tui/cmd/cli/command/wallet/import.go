@@ -0,0 +1,56 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+var (
+	importCmd = &cobra.Command{
+		Use:   "import [private key file]",
+		Short: "encrypt a hex private key into a keystore, - reads the key from stdin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := checkOverwrite()
+			if err != nil {
+				return err
+			}
+
+			var kb []byte
+			if args[0] == "-" {
+				kb, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				kb, err = os.ReadFile(filepath.Clean(args[0]))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read private key: %w", err)
+			}
+
+			key, err := hex.DecodeString(string(bytes.TrimSpace(kb)))
+			if err != nil {
+				return fmt.Errorf("private key is not hex encoded")
+			}
+
+			w, err := wallet.FromPrivateKey(edwards25519.NewBlakeSHA256Ed25519(), key)
+			if err != nil {
+				return err
+			}
+
+			password, err := readPassword(cmd, true)
+			if err != nil {
+				return err
+			}
+
+			return writeKeystore(cmd, w, password)
+		},
+	}
+)
@@ -0,0 +1,101 @@
+package wallet
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+var (
+	mnemonicBits int
+	mnemonicFile string
+	realm        string
+	service      string
+	index        uint32
+	path         string
+
+	mnemonicCmd = &cobra.Command{
+		Use:   "mnemonic",
+		Short: "print a new mnemonic to back up hierarchical wallets",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mnemonic, err := wallet.NewMnemonic(mnemonicBits)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), mnemonic)
+			return err
+		},
+	}
+
+	deriveCmd = &cobra.Command{
+		Use:   "derive",
+		Short: "derive the key of a realm service from a mnemonic into a keystore",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := checkOverwrite()
+			if err != nil {
+				return err
+			}
+
+			p := wallet.ServicePath(realm, service, index)
+			if path != "" {
+				p, err = wallet.ParsePath(path)
+				if err != nil {
+					return err
+				}
+			}
+
+			mnemonic, err := readMnemonic(cmd)
+			if err != nil {
+				return err
+			}
+
+			master, err := wallet.MasterKeyFromMnemonic(mnemonic, "")
+			if err != nil {
+				return err
+			}
+
+			k, err := master.Derive(p)
+			if err != nil {
+				return fmt.Errorf("failed to derive %s: %w", p, err)
+			}
+
+			password, err := readPassword(cmd, true)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "derived %s\n", p)
+			if err != nil {
+				return err
+			}
+
+			return writeKeystore(cmd, k.Wallet(edwards25519.NewBlakeSHA256Ed25519()), password)
+		},
+	}
+)
+
+// readMnemonic mnemonic from --mnemonic-file or the terminal
+func readMnemonic(cmd *cobra.Command) (string, error) {
+	if mnemonicFile != "" {
+		mb, err := os.ReadFile(filepath.Clean(mnemonicFile))
+		if err != nil {
+			return "", fmt.Errorf("failed to read mnemonic file: %w", err)
+		}
+		return string(bytes.TrimSpace(mb)), nil
+	}
+
+	mb, err := promptSecret(cmd, "mnemonic: ")
+	if err != nil {
+		return "", err
+	}
+	return string(mb), nil
+}
@@ -0,0 +1,116 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"github.com/structx/tbd/tui/cmd/cli/command"
+	"github.com/trevatk/tbd/lib/wallet"
+)
+
+var (
+	keystorePath string
+	passwordFile string
+	network      string
+	force        bool
+
+	walletCmd = &cobra.Command{
+		Use:   "wallet",
+		Short: "manage password encrypted wallet keystores",
+	}
+)
+
+func init() {
+	walletCmd.PersistentFlags().StringVarP(&keystorePath, "path", "p", "wallet.json", "keystore file")
+	walletCmd.PersistentFlags().StringVar(&passwordFile, "password-file", "", "file holding the keystore password, prompted when omitted")
+
+	createCmd.Flags().StringVarP(&network, "network", "n", "tbd", "address network prefix")
+	createCmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite an existing keystore")
+
+	importCmd.Flags().StringVarP(&network, "network", "n", "tbd", "address network prefix")
+	importCmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite an existing keystore")
+
+	mnemonicCmd.Flags().IntVarP(&mnemonicBits, "bits", "b", wallet.DefaultMnemonicBits, "mnemonic entropy bits, 128 to 256 in steps of 32")
+
+	deriveCmd.Flags().StringVarP(&network, "network", "n", "tbd", "address network prefix")
+	deriveCmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite an existing keystore")
+	deriveCmd.Flags().StringVar(&mnemonicFile, "mnemonic-file", "", "file holding the mnemonic, prompted when omitted")
+	deriveCmd.Flags().StringVar(&realm, "realm", "", "realm the key is scoped to")
+	deriveCmd.Flags().StringVar(&service, "service", "", "service the key is scoped to")
+	deriveCmd.Flags().Uint32VarP(&index, "index", "i", 0, "key index within the service")
+	deriveCmd.Flags().StringVar(&path, "derivation-path", "", "explicit hardened derivation path, overrides realm and service")
+	deriveCmd.MarkFlagsRequiredTogether("realm", "service")
+	deriveCmd.MarkFlagsOneRequired("realm", "derivation-path")
+	deriveCmd.MarkFlagsMutuallyExclusive("realm", "derivation-path")
+
+	walletCmd.AddCommand(createCmd, importCmd, exportCmd, mnemonicCmd, deriveCmd)
+	command.RootCmd.AddCommand(walletCmd)
+}
+
+// checkOverwrite refuse to replace an existing keystore without --force
+func checkOverwrite() error {
+	_, err := os.Stat(keystorePath)
+	if err == nil && !force {
+		return fmt.Errorf("keystore %s already exists, use --force to overwrite", keystorePath)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to stat keystore: %w", err)
+	}
+	return nil
+}
+
+// promptSecret read a line from the terminal without echo
+func promptSecret(cmd *cobra.Command, label string) ([]byte, error) {
+	fd := os.Stdin.Fd()
+	if !term.IsTerminal(fd) {
+		return nil, errors.New("stdin is not a terminal, use the file flag instead")
+	}
+
+	_, _ = fmt.Fprint(cmd.ErrOrStderr(), label)
+	b, err := term.ReadPassword(fd)
+	_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s %w", strings.TrimSpace(label), err)
+	}
+	return b, nil
+}
+
+// readPassword keystore password from --password-file or
+// the terminal, new passwords are prompted twice
+func readPassword(cmd *cobra.Command, confirm bool) ([]byte, error) {
+	if passwordFile != "" {
+		pb, err := os.ReadFile(filepath.Clean(passwordFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read password file: %w", err)
+		}
+		return bytes.TrimRight(pb, "\r\n"), nil
+	}
+
+	password, err := promptSecret(cmd, "password: ")
+	if err != nil {
+		return nil, err
+	}
+	if !confirm {
+		return password, nil
+	}
+
+	if len(password) == 0 {
+		return nil, errors.New("password must not be empty")
+	}
+
+	repeat, err := promptSecret(cmd, "repeat password: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(password, repeat) {
+		return nil, errors.New("passwords do not match")
+	}
+
+	return password, nil
+}